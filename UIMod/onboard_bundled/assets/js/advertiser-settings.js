@@ -71,7 +71,7 @@
         saveButton.disabled = true;
         validation.textContent = '';
         try {
-            const response = await fetch('/api/v2/advertiser/override', {
+            const response = await fetch(BASE_PATH + '/api/v2/advertiser/override', {
                 method: 'POST',
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify({ mode, value })
@@ -81,7 +81,7 @@
 
             document.getElementById('advertiser-restarting').hidden = false;
             document.querySelector('.advertiser-modal-actions').hidden = true;
-            setTimeout(() => window.location.href = '/', 4000);
+            setTimeout(() => window.location.href = BASE_PATH + '/', 4000);
         } catch (error) {
             validation.textContent = error.message;
             saveButton.disabled = false;
@@ -106,14 +106,124 @@ function clearActiveStream() {
     updateStreamToolbar();
 }
 
+// Live streams share one WebSocket instead of opening an SSE connection each, browsers allow only ~6 connections per domain.
+// openStream takes the SSE URL and returns an EventSource-like object; if the WebSocket cannot connect, e.g. behind a proxy
+// without WebSocket support, it falls back to the SSE endpoint. New streams start with the server's scrollback; pass the
+// lastEventId of the last message seen when reconnecting to only get what was missed.
+// Stream URLs are root-relative, BASE_PATH is added when connecting.
+const LIVE_SOCKET_PATH = '/api/v2/ws';
+const liveStreams = new Map(); // stream name -> Set of subscribers
+const liveRequests = new Map(); // request id -> stream names of a pending subscribe
+let liveSocket = null;
+let liveSocketFailed = false;
+let liveRequestId = 0;
+
+function openStream(url, lastEventId = '') {
+    if (liveSocketFailed || !('WebSocket' in window)) {
+        return new EventSource(BASE_PATH + (lastEventId ? `${url}?lastEventId=${encodeURIComponent(lastEventId)}` : url));
+    }
+    const name = url.slice(1);
+    const stream = {
+        lastEventId,
+        opened: false,
+        onopen: null,
+        onmessage: null,
+        onerror: null,
+        close() {
+            const subscribers = liveStreams.get(name);
+            if (!subscribers || !subscribers.delete(stream)) return;
+            if (subscribers.size === 0) {
+                liveStreams.delete(name);
+                sendLiveRequest({ type: 'unsubscribe', streams: [name] });
+            }
+        },
+    };
+    if (!liveStreams.has(name)) liveStreams.set(name, new Set());
+    liveStreams.get(name).add(stream);
+    if (liveSocket && liveSocket.readyState === WebSocket.OPEN) {
+        sendLiveRequest({ type: 'subscribe', streams: [name], lastEventIds: liveLastEventIds([name]) });
+    } else {
+        connectLiveSocket();
+    }
+    return stream;
+}
+
+// liveLastEventIds collects where the subscribers of streams want to resume
+function liveLastEventIds(names) {
+    const ids = {};
+    names.forEach(name => (liveStreams.get(name) || []).forEach(stream => {
+        if (stream.lastEventId && !ids[name]) ids[name] = Number(stream.lastEventId);
+    }));
+    return ids;
+}
+
+function sendLiveRequest(request) {
+    if (!liveSocket || liveSocket.readyState !== WebSocket.OPEN) return;
+    request.id = String(++liveRequestId);
+    if (request.type === 'subscribe') liveRequests.set(request.id, request.streams);
+    liveSocket.send(JSON.stringify(request));
+}
+
+function connectLiveSocket() {
+    if (liveSocket) return;
+    const url = new URL(BASE_PATH + LIVE_SOCKET_PATH, window.location.href);
+    url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+    let connected = false;
+    liveSocket = new WebSocket(url);
+
+    liveSocket.onopen = () => {
+        connected = true;
+        const names = Array.from(liveStreams.keys());
+        sendLiveRequest({ type: 'subscribe', streams: names, lastEventIds: liveLastEventIds(names) });
+    };
+
+    liveSocket.onmessage = event => {
+        const message = JSON.parse(event.data);
+        if (message.type === 'message') {
+            const event = { data: message.data, lastEventId: String(message.eventId || '') };
+            (liveStreams.get(message.stream) || []).forEach(stream => stream.onmessage && stream.onmessage(event));
+            return;
+        }
+        if (message.type !== 'response' || !liveRequests.has(message.id)) return;
+        const names = liveRequests.get(message.id);
+        liveRequests.delete(message.id);
+        names.forEach(name => {
+            const failed = message.status === 'error' && message.message.includes(`${name} (`);
+            Array.from(liveStreams.get(name) || []).forEach(stream => {
+                if (failed) {
+                    stream.onerror && stream.onerror();
+                } else if (!stream.opened) {
+                    stream.opened = true;
+                    stream.onopen && stream.onopen();
+                }
+            });
+        });
+    };
+
+    liveSocket.onclose = () => {
+        liveSocket = null;
+        liveRequests.clear();
+        if (!connected) {
+            liveSocketFailed = true;
+            console.warn('Live stream WebSocket unavailable, falling back to SSE');
+        }
+        // every subscriber reconnects on its own, like with EventSource
+        const subscribers = Array.from(liveStreams.values()).flatMap(set => Array.from(set));
+        liveStreams.clear();
+        subscribers.forEach(stream => stream.onerror && stream.onerror());
+    };
+}
+
 // Detection events streaming
 function fetchDetectionEvents() {
     registerStreamPanel('detection-tab', 'detection-console');
+    let lastEventId = '';
     
     const connect = () => {
-        detectionEventSource = new EventSource('/events');
+        detectionEventSource = openStream('/events', lastEventId);
         
         detectionEventSource.onmessage = event => {
+            lastEventId = event.lastEventId;
             const message = document.createElement('div');
             message.className = `detection-event ${getEventClassName(event.data)}`;
             
@@ -145,7 +255,7 @@ function fetchDetectionEvents() {
             detectionEventSource.close();
             detectionEventSource = null;
             setStreamConnection('detection-tab', '/events', 'reconnecting');
-            if (window.location.pathname === '/') {
+            if (window.location.pathname === BASE_PATH + '/') {
                 setTimeout(connect, 2000);
             }
         };
@@ -153,7 +263,7 @@ function fetchDetectionEvents() {
     connect();
 }
 
-// Console initialization with SSE stream setup
+// Console initialization with live stream setup
 function handleConsole() {
     const consoleElement = document.getElementById('console');
     consoleElement.innerHTML = '';
@@ -223,7 +333,7 @@ function handleConsole() {
     const createCommandInput = async () => {
         try {
             // Make API call to check if SSCM is enabled
-            const response = await fetch('/api/v2/SSCM/enabled', {
+            const response = await fetch(BASE_PATH + '/api/v2/SSCM/enabled', {
                 method: 'GET',
                 headers: {
                     'Accept': 'application/json'
@@ -279,7 +389,7 @@ function handleConsole() {
         addMessage(funMessages[messageIndex2], cssVar('--console-info'), 'italic');
 
         // Set up the persistent console stream
-        outputEventSource = new EventSource('/console');
+        outputEventSource = openStream('/console');
         setStreamConnection('console-tab', '/console', 'connecting');
         
         // Persistent message handler
@@ -301,7 +411,7 @@ function handleConsole() {
             outputEventSource = null;
             setStreamConnection('console-tab', '/console', 'reconnecting');
             addMessage("Warning: Console stream unavailable. Retrying...", cssVar('--console-warning'));
-            if (window.location.pathname === '/') {
+            if (window.location.pathname === BASE_PATH + '/') {
                 setTimeout(() => {
                     if (!outputEventSource) {
                         // Re-run setup to reconnect
@@ -347,10 +457,11 @@ function setupLogStreams({ consoleId, streamUrls, maxMessages, messageClass }) {
     consoleElement.innerHTML = '';
     registerStreamPanel('log-tab', consoleId, streamUrls.length);
 
-    const connectStream = (streamUrl) => {
-        const eventSource = new EventSource(streamUrl);
+    const connectStream = (streamUrl, lastEventId = '') => {
+        const eventSource = openStream(streamUrl, lastEventId);
 
         eventSource.onmessage = event => {
+            lastEventId = event.lastEventId;
             const message = document.createElement('div');
             let finalClass = messageClass;
 
@@ -381,8 +492,8 @@ function setupLogStreams({ consoleId, streamUrls, maxMessages, messageClass }) {
             console.error(`Stream ${streamUrl} disconnected for console ${consoleId}`);
             eventSource.close();
             setStreamConnection('log-tab', streamUrl, 'reconnecting');
-            if (window.location.pathname === '/') {
-                setTimeout(() => connectStream(streamUrl), 5000); // Reconnect after 5 seconds
+            if (window.location.pathname === BASE_PATH + '/') {
+                setTimeout(() => connectStream(streamUrl, lastEventId), 5000); // Reconnect after 5 seconds, resuming after the last message
             }
         };
     };
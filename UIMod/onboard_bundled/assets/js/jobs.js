@@ -0,0 +1,51 @@
+// Background jobs: endpoints that start long-running work answer 202 with a jobId, which is followed on /api/v2/jobs
+
+const JOB_POLL_INTERVAL = 2000;
+
+// Submit a request that queues a job, resolves with the {status, message, jobId, state, queuePosition} answer
+function submitJob(url, options = { method: 'POST' }) {
+    return fetch(BASE_PATH + url, options)
+        .then(response => response.json().then(data => ({ ok: response.ok, data })))
+        .then(({ ok, data }) => {
+            if (!ok || !data.jobId) {
+                throw new Error(data.error || data.message || 'Unknown error');
+            }
+            return data;
+        });
+}
+
+// Poll a job until it is done, onProgress gets the job on every poll while it is queued or running.
+// Resolves with the finished job, rejects with its error if it failed or was cancelled.
+function waitForJob(jobId, onProgress) {
+    return new Promise((resolve, reject) => {
+        const poll = () => {
+            fetch(`${BASE_PATH}/api/v2/jobs?id=${encodeURIComponent(jobId)}`)
+                .then(response => response.json())
+                .then(job => {
+                    if (job.state === 'queued' || job.state === 'running') {
+                        if (onProgress) onProgress(job);
+                        setTimeout(poll, JOB_POLL_INTERVAL);
+                        return;
+                    }
+                    if (job.state === 'succeeded') {
+                        resolve(job);
+                        return;
+                    }
+                    const error = new Error(job.error || job.message || `Job ${job.state || 'failed'}`);
+                    error.job = job;
+                    reject(error);
+                })
+                .catch(reject);
+        };
+        poll();
+    });
+}
+
+// Short status text of a queued or running job
+function describeJob(job) {
+    if (job.state === 'queued') {
+        return `Queued behind ${job.queuePosition} other job(s)...`;
+    }
+    const percent = job.progress ? ` ${job.progress.toFixed(1)}%` : '';
+    return (job.progressText || job.description) + percent;
+}
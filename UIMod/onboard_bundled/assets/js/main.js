@@ -18,7 +18,7 @@ document.addEventListener('DOMContentLoaded', () => {
         resourceSaver(false);
     }
     typeh1(document.querySelector('h1'), 30);
-    if (window.location.pathname == '/') {
+    if (window.location.pathname == BASE_PATH + '/') {
         setupTabs();
         fetchDetectionEvents();
         setupLogStreams({
@@ -51,7 +51,7 @@ document.addEventListener('DOMContentLoaded', () => {
         flag.addEventListener('click', async () => {
             const lang = flag.dataset.lang;
             try {
-                const response = await fetch('/api/v2/saveconfig', {
+                const response = await fetch(BASE_PATH + '/api/v2/saveconfig', {
                     method: 'POST',
                     headers: { 'Content-Type': 'application/json' },
                     body: JSON.stringify({ LanguageSetting: lang })
@@ -4,7 +4,18 @@ let backupFetchSequence = 0;
 
 // Server control functions
 function startServer() {
-    toggleServer('/start');
+    // mods that keep throwing exceptions on the current build are likely to break the server
+    fetch(BASE_PATH + '/api/v2/mods/compat')
+        .then(response => response.ok ? response.json() : { mods: [] })
+        .catch(() => ({ mods: [] }))
+        .then(data => {
+            const flagged = (data.mods || []).filter(mod => mod.flagged);
+            if (flagged.length > 0) {
+                const list = flagged.map(mod => `- ${mod.name || mod.folder}: ${mod.errors} errors`).join('\n');
+                if (!confirm(`These mods are likely incompatible with build ${data.buildId}:\n\n${list}\n\nStart the server anyway?`)) return;
+            }
+            toggleServer('/start');
+        });
 }
 
 function stopServer() {
@@ -13,7 +24,7 @@ function stopServer() {
 
 function toggleServer(endpoint) {
     const status = document.getElementById('status');
-    fetch(endpoint)
+    fetch(BASE_PATH + endpoint, { method: 'POST' })
         .then(response => response.text())
         .then(data => {
             status.hidden = false;
@@ -28,16 +39,20 @@ function triggerSteamCMD() {
     const status = document.getElementById('status');
     status.hidden = false;
     typeTextWithCallback(status, 'Running SteamCMD, please wait... ', 20, () => {
-        fetch('/api/v2/steamcmd/run')
-            .then(response => response.json())
+        submitJob('/api/v2/steamcmd/run')
             .then(data => {
-                showPopup("info", data.message);
+                if (data.status === 'queued') status.textContent = data.message;
+                return waitForJob(data.jobId, job => status.textContent = describeJob(job));
+            })
+            .then(job => {
+                status.hidden = true;
+                showPopup("info", `SteamCMD ran successfully in ${job.result.duration}, gameserver files are up-to-date! (build ${job.result.newBuildId || 'unknown'})`);
             })
             .catch(err => {
-                typeTextWithCallback(status, 'Error: Failed to trigger SteamCMD', 20, () => {
-                    setTimeout(() => status.hidden = true, 10000);
-                });
-                console.error(`Failed to trigger SteamCMD:`, err);
+                status.hidden = true;
+                const update = err.job && err.job.result;
+                showPopup("error", update ? `SteamCMD failed (${update.errorClass}): ${update.error}` : `SteamCMD failed: ${err.message}`);
+                console.error(`Failed to run SteamCMD:`, err);
             });
     });
 }
@@ -45,7 +60,7 @@ function triggerSteamCMD() {
 function fetchBackups() {
     const requestSequence = ++backupFetchSequence;
     const limit = '3';
-    const url = limit ? `/api/v2/backups?limit=${limit}` : '/api/v2/backups';
+    const url = BASE_PATH + (limit ? `/api/v2/backups?limit=${limit}` : '/api/v2/backups');
     
     return fetch(url)
         .then(response => {
@@ -134,7 +149,7 @@ function closeBackupManager() {
 
 function fetchModalBackups() {
     const limit = document.getElementById('backupModalLimit').value;
-    const url = limit ? `/api/v2/backups?limit=${limit}` : '/api/v2/backups';
+    const url = BASE_PATH + (limit ? `/api/v2/backups?limit=${limit}` : '/api/v2/backups');
     const list = document.getElementById('backupModalList');
     list.innerHTML = '<li class="no-backups">Loading backups...</li>';
 
@@ -179,9 +194,9 @@ function fetchPlayers() {
         "/static/playerimages/pierre.webp",
         "/static/playerimages/rolf.webp",
         "/static/playerimages/ronald.webp",
-    ];
+    ].map(path => BASE_PATH + path);
 
-    return fetch('/api/v2/server/status/connectedplayers')
+    return fetch(BASE_PATH + '/api/v2/server/status/connectedplayers')
         .then(response => response.json())
         .then(data => {
             playerList.innerHTML = '';
@@ -299,16 +314,23 @@ function extractIndex(backupText) {
 
 function restoreBackup(index) {
     const status = document.getElementById('status');
-    fetch(`/api/v2/backups/restore?index=${index}`)
-        .then(response => response.text())
+    status.hidden = false;
+    submitJob(`/api/v2/backups/restore?index=${index}`)
         .then(data => {
-            status.hidden = false;
-            typeTextWithCallback(status, data, 20, () => {
+            status.textContent = data.message;
+            return waitForJob(data.jobId, job => status.textContent = describeJob(job));
+        })
+        .then(() => {
+            const message = `Server stopped & Backup ${index} restored successfully, Start the server to load the restored backup`;
+            typeTextWithCallback(status, message, 20, () => {
                 setTimeout(() => status.hidden = true, 30000);
             });
-            showPopup('info', data);
+            showPopup('info', message);
         })
-        .catch(err => console.error(`Failed to restore backup ${index}:`, err));
+        .catch(err => {
+            status.hidden = true;
+            showPopup('error', `Failed to restore backup ${index}: ${err.message}`);
+        });
 }
 
 function downloadBackup(index) {
@@ -316,7 +338,7 @@ function downloadBackup(index) {
     status.hidden = false;
     typeTextWithCallback(status, 'Preparing download...', 20, () => {});
     
-    fetch('/api/v2/backups/download', {
+    fetch(BASE_PATH + '/api/v2/backups/download', {
         method: 'POST',
         headers: {
             'Content-Type': 'application/json'
@@ -357,7 +379,7 @@ function pollRecurringTasks() {
     window.gamserverstate = false;
 
     const fetchServerStatus = () => {
-        fetch('/api/v2/server/status')
+        fetch(BASE_PATH + '/api/v2/server/status')
             .then(response => response.json())
             .then(data => {
                 updateStatusIndicator(data.isRunning, false, data.uptime, data.state);
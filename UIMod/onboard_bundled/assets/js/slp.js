@@ -66,18 +66,13 @@ function installSLP() {
     setButtonLoading('installSLPBtn', true);
     showPopup('info', 'Installing Stationeers Launch Pad...');
     
-    fetch('/api/v2/slp/install')
-        .then(response => response.json())
-        .then(data => {
-            if (data.success) {
-                showPopup('success', 'Stationeers Launch Pad installed successfully! The page will refresh automatically.');
-                setButtonLoading('installSLPBtn', false);
-                // Reload after 3 seconds to show the success message
-                setTimeout(() => window.location.reload(), 3000);
-            } else {
-                showPopup('error', 'Failed to install SLP:\n\n' + (data.error || 'Unknown error'));
-                setButtonLoading('installSLPBtn', false);
-            }
+    submitJob('/api/v2/slp/install')
+        .then(data => waitForJob(data.jobId))
+        .then(() => {
+            showPopup('success', 'Stationeers Launch Pad installed successfully! The page will refresh automatically.');
+            setButtonLoading('installSLPBtn', false);
+            // Reload after 3 seconds to show the success message
+            setTimeout(() => window.location.reload(), 3000);
         })
         .catch(error => {
             showPopup('error', 'Failed to install SLP:\n\n' + (error.message || 'Network error'));
@@ -92,17 +87,12 @@ function uninstallSLP() {
     setButtonLoading('uninstallSLPBtn', true);
     showPopup('info', 'Uninstalling Stationeers Launch Pad...');
 
-    fetch('/api/v2/slp/uninstall')
-        .then(response => response.json())
-        .then(data => {
-            if (data.success) {
-                showPopup('success', 'Stationeers Launch Pad uninstalled successfully! The page will refresh automatically.');
-                setButtonLoading('uninstallSLPBtn', false);
-                setTimeout(() => window.location.reload(), 3000);
-            } else {
-                showPopup('error', 'Failed to uninstall SLP:\n\n' + (data.error || 'Unknown error'));
-                setButtonLoading('uninstallSLPBtn', false);
-            }
+    submitJob('/api/v2/slp/uninstall')
+        .then(data => waitForJob(data.jobId))
+        .then(() => {
+            showPopup('success', 'Stationeers Launch Pad uninstalled successfully! The page will refresh automatically.');
+            setButtonLoading('uninstallSLPBtn', false);
+            setTimeout(() => window.location.reload(), 3000);
         })
         .catch(error => {
             showPopup('error', 'Failed to uninstall SLP:\n\n' + (error.message || 'Network error'));
@@ -115,19 +105,14 @@ function reinstallSLP() {
         return;
     }
     setButtonLoading('reinstallSLPBtn', true);
-    showPopup('info', 'Reinstalling Stationeers Launch Pad...\n\nThis will re-download the latest version while keeping your mods intact.');
+    showPopup('info', 'Reinstalling Stationeers Launch Pad...\n\nThis will re-download the configured SLP version while keeping your mods intact.');
 
-    fetch('/api/v2/slp/reinstall')
-        .then(response => response.json())
-        .then(data => {
-            if (data.success) {
-                showPopup('success', 'Stationeers Launch Pad reinstalled successfully!' + (data.version ? ' (Version: ' + data.version + ')' : '') + ' The page will refresh automatically.');
-                setButtonLoading('reinstallSLPBtn', false);
-                setTimeout(() => window.location.reload(), 3000);
-            } else {
-                showPopup('error', 'Failed to reinstall SLP:\n\n' + (data.error || 'Unknown error'));
-                setButtonLoading('reinstallSLPBtn', false);
-            }
+    submitJob('/api/v2/slp/reinstall')
+        .then(data => waitForJob(data.jobId))
+        .then(job => {
+            showPopup('success', 'Stationeers Launch Pad reinstalled successfully!' + (job.result ? ' (Version: ' + job.result + ')' : '') + ' The page will refresh automatically.');
+            setButtonLoading('reinstallSLPBtn', false);
+            setTimeout(() => window.location.reload(), 3000);
         })
         .catch(error => {
             showPopup('error', 'Failed to reinstall SLP:\n\n' + (error.message || 'Network error'));
@@ -135,25 +120,67 @@ function reinstallSLP() {
         });
 }
 
+function loadSLPVersion() {
+    const info = document.getElementById('slpVersionInfo');
+    const rollbackBtn = document.getElementById('rollbackSLPBtn');
+    if (!info) {
+        return;
+    }
+    fetch(BASE_PATH + '/api/v2/slp/version')
+        .then(response => response.ok ? response.json() : Promise.reject(new Error('HTTP ' + response.status)))
+        .then(data => {
+            let text = 'Installs use: ' + data.version;
+            if (data.current) {
+                text += ' · Installed: ' + data.current.version + ' (verified: ' + data.current.verified + (data.current.confirmed ? '' : ', not yet started') + ')';
+            }
+            if (data.previous) {
+                text += ' · Previous: ' + data.previous.version;
+            }
+            info.textContent = text;
+            if (rollbackBtn) {
+                rollbackBtn.style.display = data.previous ? '' : 'none';
+            }
+        })
+        .catch(error => console.error('Error loading SLP version:', error));
+}
+
+function rollbackSLP() {
+    if (!confirm('Roll back to the previously installed SLP version? The current version is kept, rolling back again restores it.')) {
+        return;
+    }
+    setButtonLoading('rollbackSLPBtn', true);
+
+    submitJob('/api/v2/slp/rollback')
+        .then(data => waitForJob(data.jobId))
+        .then(job => {
+            showNotification('Rolled back to SLP ' + job.result, 'success');
+            setButtonLoading('rollbackSLPBtn', false);
+            loadSLPVersion();
+        })
+        .catch(error => {
+            showPopup('error', 'Failed to roll back SLP:\n\n' + (error.message || 'Network error'));
+            setButtonLoading('rollbackSLPBtn', false);
+        });
+}
+
 function updateSingleMod(workshopHandle, index) {
     const btnId = 'update-mod-btn-' + index;
     setButtonLoading(btnId, true);
     showPopup('info', 'Updating workshop mod ' + workshopHandle + '...\n\nPlease wait.');
 
-    fetch('/api/v2/steamcmd/updatemod', {
+    submitJob('/api/v2/steamcmd/updatemod', {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify({ workshopHandle: workshopHandle })
     })
-        .then(response => response.json())
         .then(data => {
+            if (data.status === 'queued') showPopup('info', data.message);
+            return waitForJob(data.jobId);
+        })
+        .then(() => {
             setButtonLoading(btnId, false);
-            if (data.success) {
-                showPopup('success', 'Workshop mod updated successfully!\n\nReloading mod list...');
-                loadInstalledMods();
-            } else {
-                showPopup('error', 'Failed to update mod:\n\n' + (data.error || 'Unknown error'));
-            }
+            showPopup('success', 'Workshop mod updated successfully!\n\nReloading mod list...');
+            loadInstalledMods();
         })
         .catch(error => {
             showPopup('error', 'Failed to update mod:\n\n' + (error.message || 'Network error'));
@@ -165,24 +192,62 @@ function updateWorkshopMods() {
     setButtonLoading('updateWorkshopModsBtn', true);
     showPopup('info', 'Updating workshop mods...\n\nThis may take some time depending on the number of mods. Please wait.');
     
-    fetch('/api/v2/steamcmd/updatemods')
-        .then(response => response.json())
+    submitJob('/api/v2/steamcmd/updatemods')
         .then(data => {
+            if (data.status === 'queued') showPopup('info', data.message);
+            return waitForJob(data.jobId);
+        })
+        .then(job => {
             setButtonLoading('updateWorkshopModsBtn', false);
-            if (data.success) {
-                const logsText = data.logs && data.logs.length > 0 ? '\n\n' + data.logs.join('\n') : '';
-                showPopup('success', 'Workshop mods updated successfully!' + logsText);
-            } else {
-                const logsText = data.logs && data.logs.length > 0 ? '\n\n' + data.logs.join('\n') : '';
-                showPopup('error', 'Failed to update workshop mods:\n\n' + (data.error || 'Unknown error') + logsText);
-            }
+            const logsText = job.logs && job.logs.length > 0 ? '\n\n' + job.logs.join('\n') : '';
+            showPopup('success', 'Workshop mods updated successfully!' + logsText);
         })
         .catch(error => {
-            showPopup('error', 'Failed to update workshop mods:\n\n' + (error.message || 'Network error'));
+            const logs = error.job && error.job.logs;
+            const logsText = logs && logs.length > 0 ? '\n\n' + logs.join('\n') : '';
+            showPopup('error', 'Failed to update workshop mods:\n\n' + (error.message || 'Network error') + logsText);
             setButtonLoading('updateWorkshopModsBtn', false);
         });
 }
 
+function syncMods() {
+    setButtonLoading('syncModsBtn', true);
+    showPopup('info', 'Syncing mods with the mod manifest...\n\nMissing mods and dependencies are downloaded, this may take some time.');
+
+    submitJob('/api/v2/mods/sync')
+        .then(data => {
+            if (data.status === 'queued') showPopup('info', data.message);
+            return waitForJob(data.jobId);
+        })
+        .then(job => {
+            setButtonLoading('syncModsBtn', false);
+            const result = job.result || {};
+            const warnings = result.warnings && result.warnings.length > 0 ? '\n\nWarnings:\n' + result.warnings.join('\n') : '';
+            const loaded = result.loadOrder ? result.loadOrder.length : 0;
+            showPopup('success', `Mods synced, ${loaded} mod(s) enabled in modconfig.xml.` + warnings);
+            loadInstalledMods();
+        })
+        .catch(error => {
+            showPopup('error', 'Failed to sync mods:\n\n' + (error.message || 'Network error'));
+            setButtonLoading('syncModsBtn', false);
+        });
+}
+
+function postModPackToDiscord() {
+    setButtonLoading('postModPackBtn', true);
+    fetch(BASE_PATH + '/api/v2/mods/modpack/discord', { method: 'POST' })
+        .then(response => response.json().then(data => ({ ok: response.ok, data })))
+        .then(({ ok, data }) => {
+            setButtonLoading('postModPackBtn', false);
+            if (!ok) throw new Error(data.error || data.message || 'Unknown error');
+            showNotification(data.message, 'success');
+        })
+        .catch(error => {
+            setButtonLoading('postModPackBtn', false);
+            showPopup('error', 'Failed to post the mod pack:\n\n' + (error.message || 'Network error'));
+        });
+}
+
 let selectedModFile = null;
 
 function handleModPackageSelection(files) {
@@ -262,27 +327,25 @@ function uploadModPackage() {
     reader.onload = function(e) {
         const zipData = e.target.result;
         
-        fetch('/api/v2/slp/upload', {
+        submitJob('/api/v2/slp/upload', {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/zip'
             },
             body: zipData
         })
-        .then(response => response.json())
         .then(data => {
-            if (data.success) {
-                showPopup('success', 'Mod package uploaded successfully!\n\n' + (data.message || 'The mods have been extracted and are ready to use.'));
-                selectedModFile = null;
-                document.getElementById('modPackageUpload').value = '';
-                updateFileDisplay();
-                updateUploadProgress(100);
-                setTimeout(() => updateUploadProgress(0), 2000);
-                setButtonLoading('uploadModPackageBtn', false);
-            } else {
-                showPopup('error', 'Failed to upload mod package:\n\n' + (data.error || 'Unknown error'));
-                setButtonLoading('uploadModPackageBtn', false);
-            }
+            updateUploadProgress(50);
+            return waitForJob(data.jobId);
+        })
+        .then(() => {
+            showPopup('success', 'Mod package uploaded successfully!\n\nThe mods have been extracted and are ready to use.');
+            selectedModFile = null;
+            document.getElementById('modPackageUpload').value = '';
+            updateFileDisplay();
+            updateUploadProgress(100);
+            setTimeout(() => updateUploadProgress(0), 2000);
+            setButtonLoading('uploadModPackageBtn', false);
         })
         .catch(error => {
             showPopup('error', 'Upload failed:\n\n' + (error.message || 'Network error'));
@@ -367,6 +430,8 @@ if (document.readyState === 'loading') {
 
 // Mods List Management
 let modsData = [];
+let modConfig = { mods: [], warnings: [] }; // load order and enabled state from modconfig.xml
+let modCompat = []; // exceptions attributed to each mod on the current build
 
 function loadInstalledMods() {
     const container = document.getElementById('mods-list-container');
@@ -379,10 +444,19 @@ function loadInstalledMods() {
     if (loader) loader.style.display = 'block';
     if (modsList) modsList.innerHTML = '';
     
-    fetch('/api/v2/slp/mods')
-        .then(response => response.json())
-        .then(data => {
+    const configRequest = fetch(BASE_PATH + '/api/v2/mods/config')
+        .then(response => response.ok ? response.json() : { mods: [], warnings: [] })
+        .catch(() => ({ mods: [], warnings: [] }));
+
+    const compatRequest = fetch(BASE_PATH + '/api/v2/mods/compat')
+        .then(response => response.ok ? response.json() : { mods: [] })
+        .catch(() => ({ mods: [] }));
+
+    Promise.all([fetch(BASE_PATH + '/api/v2/slp/mods').then(response => response.json()), configRequest, compatRequest])
+        .then(([data, config, compat]) => {
             if (loader) loader.style.display = 'none';
+            modConfig = config;
+            modCompat = compat.mods || [];
             
             if (data.success && data.mods && data.mods.length > 0) {
                 modsData = data.mods;
@@ -409,10 +483,61 @@ function renderModsList(mods) {
         return;
     }
     
-    mods.forEach((mod, index) => {
-        const modCard = createModCard(mod, index);
-        modsList.appendChild(modCard);
-    });
+    if (modConfig.warnings && modConfig.warnings.length > 0) {
+        const warnings = document.createElement('div');
+        warnings.className = 'mods-warnings';
+        warnings.innerHTML = modConfig.warnings.map(warning => `<div>⚠️ ${escapeHtml(warning)}</div>`).join('');
+        modsList.appendChild(warnings);
+    }
+
+    // show the mods in load order, mods missing from modconfig.xml last
+    const position = folder => {
+        const index = modConfig.mods.findIndex(entry => entry.folder === folder);
+        return index < 0 ? modConfig.mods.length : index;
+    };
+    mods.map((mod, index) => ({ mod, index }))
+        .sort((a, b) => position(modFolder(a.mod)) - position(modFolder(b.mod)))
+        .forEach(({ mod, index }) => {
+            const modCard = createModCard(mod, index);
+            modsList.appendChild(modCard);
+        });
+}
+
+// Folder name of a mod in ./mods, which the mod config endpoints address mods by
+function modFolder(mod) {
+    return (mod.Folder || '').split(/[\\/]/).pop();
+}
+
+function editModConfig(url, body, errorPrefix) {
+    return fetch(BASE_PATH + url, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(body)
+    })
+        .then(response => response.json().then(data => ({ ok: response.ok, data })))
+        .then(({ ok, data }) => {
+            if (!ok) throw new Error(data.error || data.message || 'Unknown error');
+            showNotification(data.message, 'success');
+            loadInstalledMods();
+        })
+        .catch(error => showPopup('error', errorPrefix + '\n\n' + (error.message || 'Network error')));
+}
+
+function setModEnabled(folder, enabled) {
+    editModConfig('/api/v2/mods/enable', { folder, enabled }, 'Failed to change the mod:');
+}
+
+function moveModUp(folder) {
+    const order = modConfig.mods.map(entry => entry.folder);
+    const index = order.indexOf(folder);
+    if (index <= 0) return;
+    [order[index - 1], order[index]] = [order[index], order[index - 1]];
+    editModConfig('/api/v2/mods/reorder', { order }, 'Failed to change the load order:');
+}
+
+function removeMod(folder, name) {
+    if (!confirm(`Remove ${name} from the server? Its folder in ./mods is deleted.`)) return;
+    editModConfig('/api/v2/mods/remove', { folder }, 'Failed to remove the mod:');
 }
 
 function createModCard(mod, index) {
@@ -466,6 +591,46 @@ function createModCard(mod, index) {
         updateButton.addEventListener('click', () => updateSingleMod(mod.WorkshopHandle, index));
         card.appendChild(updateButton);
     }
+
+    const folder = modFolder(mod);
+    const compat = modCompat.find(record => record.folder === folder);
+    if (compat && compat.errors > 0) {
+        const errors = document.createElement('div');
+        errors.className = compat.flagged ? 'mod-compat flagged' : 'mod-compat';
+        errors.textContent = `${compat.flagged ? '⚠️ Likely incompatible: ' : ''}${compat.errors} exception(s) on build ${compat.buildId}`;
+        errors.title = compat.lastError || '';
+        card.appendChild(errors);
+
+        const resetButton = document.createElement('button');
+        resetButton.className = 'mod-update-button';
+        resetButton.textContent = '🧹 Reset errors';
+        resetButton.addEventListener('click', () => editModConfig('/api/v2/mods/compat/reset', { folder }, 'Failed to reset the error counters:'));
+        card.appendChild(resetButton);
+    }
+
+    const entry = modConfig.mods.find(configured => configured.folder === folder);
+    const enabled = entry ? entry.enabled : false;
+    if (!enabled) card.classList.add('mod-disabled');
+
+    const toggleButton = document.createElement('button');
+    toggleButton.className = 'mod-update-button';
+    toggleButton.textContent = enabled ? '⏸ Disable' : '▶ Enable';
+    toggleButton.addEventListener('click', () => setModEnabled(folder, !enabled));
+    card.appendChild(toggleButton);
+
+    if (entry && modConfig.mods.indexOf(entry) > 0) {
+        const upButton = document.createElement('button');
+        upButton.className = 'mod-update-button';
+        upButton.textContent = '⬆ Load earlier';
+        upButton.addEventListener('click', () => moveModUp(folder));
+        card.appendChild(upButton);
+    }
+
+    const removeButton = document.createElement('button');
+    removeButton.className = 'mod-update-button';
+    removeButton.textContent = '🗑 Remove';
+    removeButton.addEventListener('click', () => removeMod(folder, mod.Name || folder));
+    card.appendChild(removeButton);
     
     return card;
 }
@@ -537,10 +702,12 @@ if (document.readyState === 'loading') {
         if (modsContainer) {
             loadInstalledMods();
         }
+        loadSLPVersion();
     });
 } else {
     const modsContainer = document.getElementById('mods-list-container');
     if (modsContainer) {
         loadInstalledMods();
     }
+    loadSLPVersion();
 }
@@ -29,12 +29,12 @@
         saveButton.disabled = true;
         validation.textContent = '';
         try {
-            const response = await fetch('/api/v2/tls/certificate', { method: 'POST', body });
+            const response = await fetch(BASE_PATH + '/api/v2/tls/certificate', { method: 'POST', body });
             const result = await response.json();
             if (!response.ok) throw new Error(result.message || 'Failed to save TLS certificate.');
             document.getElementById('tls-restarting').hidden = false;
             document.getElementById('tls-modal-actions').hidden = true;
-            setTimeout(() => window.location.href = '/', 5000);
+            setTimeout(() => window.location.reload(), 2000);
         } catch (error) {
             validation.textContent = error.message;
             saveButton.disabled = false;
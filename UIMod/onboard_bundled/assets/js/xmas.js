@@ -1046,7 +1046,7 @@ function addChristmasOrbitingTreats() {
     orbit.style.animation = `orbit ${speed}s linear infinite ${delay}s`;
 
     const img = document.createElement('img');
-    img.src = imgUrl;
+    img.src = BASE_PATH + imgUrl;
     img.style.width = `${size}px`;
     img.style.height = `${size}px`;
     img.style.objectFit = 'contain';
@@ -1073,7 +1073,7 @@ function addChristmasOrbitingTreats() {
 
 function replaceBanner() {
   const banner = document.getElementById('banner');
-  banner.src = '/static/xmas/stationeers-winter.webp';
+  banner.src = BASE_PATH + '/static/xmas/stationeers-winter.webp';
 }
 
 addChristmasOrbitingTreats();
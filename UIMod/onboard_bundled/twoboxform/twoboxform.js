@@ -55,7 +55,7 @@ document.addEventListener('DOMContentLoaded', () => {
     }
     async function preloadNextPage() {
         try {
-            const response = await fetch('/static/favicon.ico', { method: 'HEAD', cache: 'force-cache' });
+            const response = await fetch(BASE_PATH + '/static/favicon.ico', { method: 'HEAD', cache: 'force-cache' });
             return response.ok;
         } catch (error) {
             console.error('Preload failed:', error);
@@ -76,23 +76,122 @@ document.addEventListener('DOMContentLoaded', () => {
         return false; // Default to false if invalid input
     }
 
+    // Two-factor authentication
+    let twoFactorChallenge = null; // set once the password was accepted and a code is required
+    let twoFactorEnabled = false;
+
+    // Switch the login form to the second step
+    function showTwoFactorStep(challenge) {
+        twoFactorChallenge = challenge;
+        ['primary-field', 'secondary-field'].forEach(id => {
+            const field = document.getElementById(id);
+            field.required = false;
+            field.closest('.form-group').hidden = true;
+        });
+        document.getElementById('two-factor-group').hidden = false;
+        const codeField = document.getElementById('two-factor-code');
+        codeField.required = true;
+        codeField.focus();
+    }
+
+    // On the /twofactor page, start an enrolment or offer to disable an enabled 2FA
+    async function loadTwoFactorPage() {
+        try {
+            const status = await (await fetch(BASE_PATH + '/api/v2/auth/2fa')).json();
+            if (status.error) {
+                showNotification(status.error, 'error');
+                return;
+            }
+            twoFactorEnabled = status.enabled;
+            if (twoFactorEnabled) {
+                document.querySelector('.step-message').textContent = `${document.getElementById('two-factor-enabled-message').value} (${status.recoveryCodesLeft})`;
+                document.querySelector('.two-box-form-button').value = document.getElementById('two-factor-disable-text').value;
+                return;
+            }
+            const response = await fetch(BASE_PATH + '/api/v2/auth/2fa/enroll', { method: 'POST' });
+            const data = await response.json();
+            if (!response.ok) {
+                showNotification(data.error || 'Failed to start enrolment!', 'error');
+                return;
+            }
+            const link = document.getElementById('two-factor-uri');
+            link.href = data.uri;
+            link.textContent = data.uri;
+            document.getElementById('two-factor-secret').textContent = data.secret;
+            document.getElementById('two-factor-setup').hidden = false;
+        } catch (error) {
+            console.error('2FA error:', error);
+            showNotification('Something went wrong!', 'error');
+        }
+    }
+
+    async function submitTwoFactorPage() {
+        const url = twoFactorEnabled ? '/api/v2/auth/2fa/disable' : '/api/v2/auth/2fa/confirm';
+        try {
+            showPreloader();
+            const response = await fetch(BASE_PATH + url, {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ code: document.getElementById('primary-field').value })
+            });
+            const data = await response.json();
+            hidePreloader();
+            if (!response.ok) {
+                showNotification(data.error || 'Action failed!', 'error');
+                return;
+            }
+            showNotification(data.message, 'success');
+            form.reset();
+            if (twoFactorEnabled) {
+                setTimeout(() => window.location.reload(), 1000);
+                return;
+            }
+            document.getElementById('two-factor-setup').hidden = true;
+            document.getElementById('two-factor-recovery-codes').textContent = data.recoveryCodes.join('\n');
+            document.getElementById('two-factor-recovery').hidden = false;
+            form.hidden = true;
+        } catch (error) {
+            hidePreloader();
+            console.error('2FA error:', error);
+            showNotification('Something went wrong!', 'error');
+        }
+    }
+
+    // Errors of a failed single sign-on are passed back to the login page, as is the challenge of a single sign-on that needs a code
+    const loginParams = new URLSearchParams(window.location.search);
+    const loginError = loginParams.get('error');
+    if (loginError) {
+        showNotification(loginError, 'error');
+    }
+    if (loginParams.get('challenge')) {
+        showTwoFactorStep(loginParams.get('challenge'));
+    }
+
     // Form submission
     const form = document.getElementById('two-box-form');
+    if (document.getElementById('mode').value === 'twofactor') {
+        loadTwoFactorPage();
+    }
     form.addEventListener('submit', async (e) => {
         e.preventDefault();
         const step = document.getElementById('step').value;
         const mode = document.getElementById('mode').value;
+
+        if (mode === 'twofactor') {
+            await submitTwoFactorPage();
+            return;
+        }
         const configField = document.getElementById('config-field').value;
         let nextStep = document.getElementById('next-step').value;
 
         if (step === "welcome" || step === "pls_read") {
-            window.location.href = `/setup?step=${nextStep}`;
+            window.location.href = `${BASE_PATH}/setup?step=${nextStep}`;
             return;
         }
 
         if (step === "finalize") {
             // Return to first setup step
-            window.location.href = `/setup?step=${nextStep}`;
+            window.location.href = `${BASE_PATH}/setup?step=${nextStep}`;
             return;
         }
 
@@ -114,11 +213,11 @@ document.addEventListener('DOMContentLoaded', () => {
             // No need to save this choice to config
             if (nextStep === 'admin_account') {
                 // Skip directly without saving
-                window.location.href = `/setup?step=${nextStep}`;
+                window.location.href = `${BASE_PATH}/setup?step=${nextStep}`;
                 return;
             } else if (nextStep === 'game_port') {
                 // Also skip without saving but go to game port config
-                window.location.href = `/setup?step=${nextStep}`;
+                window.location.href = `${BASE_PATH}/setup?step=${nextStep}`;
                 return;
             }
         }
@@ -167,6 +266,12 @@ document.addEventListener('DOMContentLoaded', () => {
                 username: document.getElementById('primary-field').value,
                 password: document.getElementById('secondary-field').value
             });
+        } else if (twoFactorChallenge) { // second login step
+            url = '/auth/login/2fa';
+            body = JSON.stringify({
+                challenge: twoFactorChallenge,
+                code: document.getElementById('two-factor-code').value
+            });
         } else { // Login or changeuser
             url = mode === 'changeuser' ? '/api/v2/auth/adduser' : '/auth/login';
             body = JSON.stringify({
@@ -180,16 +285,23 @@ document.addEventListener('DOMContentLoaded', () => {
             // If we're on a step that doesn't need to save data
             if (!url) {
                 hidePreloader();
-                window.location.href = `/setup?step=${nextStep}`;
+                window.location.href = `${BASE_PATH}/setup?step=${nextStep}`;
                 return;
             }
 
-            const response = await fetch(url, {
+            const response = await fetch(BASE_PATH + url, {
                 method: 'POST',
                 headers: { 'Content-Type': 'application/json' },
                 body: body
             });
             const data = await response.json();
+
+            if (mode === 'login' && response.status === 202 && data.twoFactorRequired) {
+                hidePreloader();
+                showNotification(data.message, 'success');
+                showTwoFactorStep(data.challenge);
+                return;
+            }
             
             if (response.ok || response.status === 201) {
                 if (configField || step === "admin_account") {
@@ -198,13 +310,13 @@ document.addEventListener('DOMContentLoaded', () => {
                     // Wait for backend response to complete before redirecting
                     try { await response.json(); } catch (e) {} // Ensure backend response is fully processed
                     setTimeout(() => {
-                        window.location.href = `/setup?step=${nextStep}`;
+                        window.location.href = `${BASE_PATH}/setup?step=${nextStep}`;
                     }, 1000);
                 } else if (mode === 'login') {
                     showNotification('Login Successful!', 'success');
                     await preloadNextPage();
                     hidePreloader();
-                    window.location.href = '/';
+                    window.location.href = BASE_PATH + '/';
                 } else { // changeuser
                     hidePreloader();
                     showNotification(data.message || 'User updated!', 'success');
@@ -229,14 +341,14 @@ document.addEventListener('DOMContentLoaded', () => {
             let nextStep = document.getElementById('next-step').value;
             
             if (step === "welcome") {
-                window.location.href = '/';
+                window.location.href = BASE_PATH + '/';
                 return;
             }
             
             if (step === "finalize") {
                 // Go to login page when skipping from finalize
                 showNotification('Setup completed, Auth disabled!', 'success');
-                setTimeout(() => window.location.href = '/', 1000);
+                setTimeout(() => window.location.href = BASE_PATH + '/', 1000);
                 return;
             }
             
@@ -247,7 +359,7 @@ document.addEventListener('DOMContentLoaded', () => {
                 nextStep = "admin_account"; // Skip all network config
             }
             
-            window.location.href = `/setup?step=${nextStep}`;
+            window.location.href = `${BASE_PATH}/setup?step=${nextStep}`;
         });
     }
 
@@ -256,7 +368,7 @@ document.addEventListener('DOMContentLoaded', () => {
         if (e.target && e.target.id === 'finalize-btn') {
             try {
                 showPreloader();
-                const finalizeResponse = await fetch('/api/v2/auth/setup/finalize', {
+                const finalizeResponse = await fetch(BASE_PATH + '/api/v2/auth/setup/finalize', {
                     method: 'POST',
                     headers: { 'Content-Type': 'application/json' }
                 });
@@ -265,7 +377,7 @@ document.addEventListener('DOMContentLoaded', () => {
                 if (finalizeResponse.ok) {
                     hidePreloader();
                     showNotification(`${data.message}\n${data.restart_hint}`, 'success');
-                    setTimeout(() => window.location.href = '/login', 2000);
+                    setTimeout(() => window.location.href = BASE_PATH + '/login', 2000);
                 } else {
                     hidePreloader();
                     showNotification(data.error || 'Finalize failed!', 'error');
@@ -285,7 +397,7 @@ document.addEventListener('DOMContentLoaded', () => {
             const lang = flag.dataset.lang;
             try {
                 showPreloader();
-                const response = await fetch('/api/v2/saveconfig', {
+                const response = await fetch(BASE_PATH + '/api/v2/saveconfig', {
                     method: 'POST',
                     headers: { 'Content-Type': 'application/json' },
                     body: JSON.stringify({ LanguageSetting: lang })
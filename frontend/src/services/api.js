@@ -29,10 +29,10 @@ export function getCurrentBackend() {
   return config.backends[config.active] || config.backends.default;
 }
 
-// Helper to get the current backend URL
+// Helper to get the current backend URL, the default backend is the current host under the base path the server set
 export function getCurrentBackendUrl() {
   const backend = getCurrentBackend();
-  return backend.url === '/' ? '' : backend.url;
+  return backend.url === '/' ? (window.BASE_PATH || '') : backend.url;
 }
 
 // Helper to get the current authentication token
@@ -251,8 +251,7 @@ export function apiSSE(endpoint, onMessage, onError = console.error) {
   const normalizedEndpoint = endpoint.startsWith('/') || endpoint === '' ? endpoint : `/${endpoint}`;
   
   // Construct the full URL 
-  const baseUrl = backendUrl || window.location.origin;
-  const url = new URL(`${baseUrl}${normalizedEndpoint}`);
+  const url = new URL(`${backendUrl}${normalizedEndpoint}`, window.location.origin);
   
   // Add token as query param as a fallback for EventSource which can't set headers
   if (token) {
@@ -322,7 +321,7 @@ export function apiSSE(endpoint, onMessage, onError = console.error) {
               // Get fresh URL and token from new backend
               const newBackendUrl = getCurrentBackendUrl();
               const newToken = getCurrentAuthToken();
-              const newUrl = new URL(`${newBackendUrl || window.location.origin}${normalizedEndpoint}`);
+              const newUrl = new URL(`${newBackendUrl}${normalizedEndpoint}`, window.location.origin);
               
               if (newToken) {
                 newUrl.searchParams.set('token', newToken);
@@ -4,6 +4,7 @@ import { svelte } from '@sveltejs/vite-plugin-svelte'
 // https://vite.dev/config/
 export default defineConfig({
   plugins: [svelte()],
+  base: './', // assets are loaded relative to /v2, so the UI also works under a reverse proxy base path
   build: {
     outDir: '../UIMod/onboard_bundled/v2', // Change output directory to ../dist
     rollupOptions: {
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+const apiKeysUsage = "usage: apikeys list | apikeys create <name> [scopes, comma separated: read,control,admin] [months] | apikeys revoke <id>"
+
+// apiKeysCommand lists, creates and revokes named API keys
+func apiKeysCommand(args []string) error {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list", "ls":
+		keys := security.ListAPIKeys()
+		if len(keys) == 0 {
+			logger.Core.Info("No API keys registered.")
+			return nil
+		}
+		logger.Core.Infof("API keys (%d):", len(keys))
+		for _, key := range keys {
+			lastUsed := "never"
+			if key.LastUsed != nil {
+				lastUsed = key.LastUsed.Format(time.RFC3339)
+			}
+			expires := "unknown"
+			if !key.ExpiresAt.IsZero() {
+				expires = key.ExpiresAt.Format(time.RFC3339)
+			}
+			logger.Core.Cleanf("  %s  %-20s scopes=%s expires=%s lastUsed=%s", key.ID, key.Name, strings.Join(key.Scopes, ","), expires, lastUsed)
+		}
+		return nil
+
+	case "create", "add":
+		if len(args) < 2 {
+			return errors.New(apiKeysUsage)
+		}
+		var scopes []string
+		if len(args) > 2 {
+			scopes = strings.Split(args[2], ",")
+		}
+		months := 1
+		if len(args) > 3 {
+			m, err := strconv.Atoi(args[3])
+			if err != nil {
+				return fmt.Errorf("invalid duration %q: %w", args[3], err)
+			}
+			months = m
+		}
+		key, apikey, err := security.CreateAPIKey(args[1], scopes, months)
+		if err != nil {
+			return err
+		}
+		logger.Core.Infof("API key %s created (scopes: %s, expires: %s)", key.ID, strings.Join(key.Scopes, ","), key.ExpiresAt.Format(time.RFC3339))
+		logger.Core.Cleanf("  %s", apikey)
+		logger.Core.Info("Store this key now, it cannot be shown again. Send it as 'Authorization: Bearer <key>'.")
+		return nil
+
+	case "revoke", "rm":
+		if len(args) < 2 {
+			return errors.New(apiKeysUsage)
+		}
+		return security.RevokeAPIKey(args[1])
+
+	default:
+		return errors.New(apiKeysUsage)
+	}
+}
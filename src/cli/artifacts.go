@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/artifacts"
+)
+
+const artifactsUsage = "usage: artifacts list | artifacts populate | artifacts export [zip] | artifacts import <zip>"
+
+// artifactsCommand manages the artifact cache installers download through, for offline and reproducible installs
+func artifactsCommand(args []string) error {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list", "ls":
+		entries, err := artifacts.List()
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			logger.Core.Info("The artifact cache in " + config.GetArtifactCacheFolder() + " is empty.")
+			return nil
+		}
+		logger.Core.Infof("Cached artifacts in %s (%d):", config.GetArtifactCacheFolder(), len(entries))
+		for _, entry := range entries {
+			checksum := "unverified, not in the index"
+			if entry.Indexed {
+				checksum = "sha256=" + entry.SHA256
+			}
+			logger.Core.Cleanf("  %-45s %10d bytes  %s  stored=%s", entry.Name, entry.Size, checksum, entry.StoredAt.Format(time.RFC3339))
+		}
+		return nil
+
+	case "populate", "fill":
+		list := setup.InstallerArtifacts()
+		slp, err := modding.SLPArtifacts()
+		if err != nil {
+			logger.Core.Warn("Skipping SLP: " + err.Error())
+		}
+		downloaded, err := artifacts.Populate(append(list, slp...))
+		logger.Core.Infof("Artifact cache populated, %d downloaded, %d already cached", downloaded, len(list)+len(slp)-downloaded)
+		return err
+
+	case "export":
+		target := fmt.Sprintf("artifacts_%s.zip", time.Now().Format("20060102_150405"))
+		if len(args) > 1 {
+			target = args[1]
+		}
+		file, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		count, err := artifacts.Export(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(target)
+			return err
+		}
+		logger.Core.Infof("Exported %d artifacts to %s. Import it on the offline host with 'artifacts import %s'", count, target, target)
+		return nil
+
+	case "import":
+		if len(args) < 2 {
+			return errors.New(artifactsUsage)
+		}
+		file, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		count, err := artifacts.Import(file, info.Size())
+		if err != nil {
+			return err
+		}
+		logger.Core.Infof("Imported %d artifacts into %s", count, config.GetArtifactCacheFolder())
+		return nil
+
+	default:
+		return errors.New(artifactsUsage)
+	}
+}
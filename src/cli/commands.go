@@ -14,6 +14,7 @@ import (
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/cli/dashboard"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/loader"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
@@ -40,6 +41,8 @@ func init() {
 	RegisterCommand("supportpackage", WrapNoReturn(supportPackage), "Create a support package", false, "sp")
 	RegisterCommand("exit", WrapNoReturn(exitfromcli), "Exit / Shutdown SSUI", false, "e")
 	RegisterCommand("runsteamcmd", WrapNoReturn(runSteamCMD), "Run SteamCMD to update the Gameserver", false, "steamcmd", "stcmd")
+	RegisterCommand("apikeys", apiKeysCommand, "List, create or revoke API keys (apikeys list|create|revoke)", false, "ak")
+	RegisterCommand("artifacts", artifactsCommand, "List, populate, export or import the offline installer cache (artifacts list|populate|export|import)", false, "art")
 	RegisterCommand("downloadworkshopupdates", WrapNoReturn(downloadWorkshopUpdates), "Download Steam workshop Mod updates", true, "dwu")
 
 	// Dev commands
@@ -67,9 +70,10 @@ func dashboardCommand(args []string) error {
 // COMMAND HANDLERS WITH COMMANDS USEFUL FOR USERS
 
 func downloadWorkshopUpdates() {
-	_, err := steamcmd.UpdateWorkshopItems()
-	if err != nil {
-		logger.Core.Error("Error downloading workshop updates: " + err.Error())
+	// queued like the web UI's workshop updates, so it never runs next to another SteamCMD job
+	job, _ := jobs.Wait(steamcmd.StartWorkshopUpdate(nil).ID)
+	if job.State != jobs.StateSucceeded {
+		logger.Core.Error("Error downloading workshop updates: " + job.Error)
 	}
 }
 
@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Jobs Panel
+
+var jobStateStyles = map[jobs.State]lipgloss.Style{
+	jobs.StateQueued:    lipgloss.NewStyle().Foreground(Gray400),
+	jobs.StateRunning:   lipgloss.NewStyle().Foreground(Cyan).Bold(true),
+	jobs.StateSucceeded: lipgloss.NewStyle().Foreground(Green),
+	jobs.StateFailed:    lipgloss.NewStyle().Foreground(Red),
+	jobs.StateCancelled: lipgloss.NewStyle().Foreground(Yellow),
+}
+
+func (m Model) renderJobsPanel() string {
+	rows := []string{RenderSectionTitle("Background Jobs"), ""}
+
+	if len(m.backgroundJobs) == 0 {
+		rows = append(rows, MutedStyle.Render("No jobs yet. SteamCMD runs, workshop downloads, SLP installs, mod imports and restores show up here."))
+		return m.renderPanelContainer(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	}
+
+	colHeader := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(12).Foreground(Gray500).Bold(true).Render("State"),
+		lipgloss.NewStyle().Width(20).Foreground(Gray500).Bold(true).Render("Type"),
+		lipgloss.NewStyle().Width(24).Foreground(Gray500).Bold(true).Render("Progress"),
+		lipgloss.NewStyle().Width(10).Foreground(Gray500).Bold(true).Render("Age"),
+		lipgloss.NewStyle().Foreground(Gray500).Bold(true).Render("Description"),
+	)
+	rows = append(rows, colHeader, DividerStyle.Render(BoxTeeRight+strings.Repeat(BoxHorizontal, 80)+BoxTeeLeft))
+
+	for _, job := range m.backgroundJobs {
+		progress := renderJobProgress(job)
+		description := job.Description
+		switch {
+		case job.Error != "":
+			description += ValueErrorStyle.Render(" (" + job.Error + ")")
+		case job.State == jobs.StateRunning && job.ProgressText != "":
+			description += MutedStyle.Render(" (" + job.ProgressText + ")")
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			jobStateStyles[job.State].Width(12).Render(string(job.State)),
+			lipgloss.NewStyle().Width(20).Foreground(Gray400).Render(job.Type),
+			lipgloss.NewStyle().Width(24).Render(progress),
+			lipgloss.NewStyle().Width(10).Foreground(Gray500).Render(formatUptime(time.Since(job.CreatedAt))),
+			description,
+		))
+	}
+
+	if m.jobsStatusMsg != "" {
+		rows = append(rows, "", InfoStyle.Render(m.jobsStatusMsg))
+	}
+	return m.renderPanelContainer(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// renderJobProgress shows a bar for running jobs and the queue position for queued ones
+func renderJobProgress(job jobs.Job) string {
+	const width = 15
+	switch job.State {
+	case jobs.StateQueued:
+		return MutedStyle.Render(fmt.Sprintf("#%d in queue", job.QueuePosition))
+	case jobs.StateRunning:
+		filled := min(int(job.Progress/100*width), width)
+		bar := strings.Repeat(ProgressFull, filled) + strings.Repeat(ProgressEmpty, width-filled)
+		return lipgloss.NewStyle().Foreground(Cyan).Render(bar) + NumberStyle.Render(fmt.Sprintf(" %3.0f%%", job.Progress))
+	case jobs.StateCancelled:
+		if job.StartedAt.IsZero() {
+			return MutedStyle.Render("never started")
+		}
+	}
+	return MutedStyle.Render("done in " + formatUptime(job.FinishedAt.Sub(job.StartedAt)))
+}
+
+// cancelNewestJob cancels the most recently submitted job that is still queued or running
+func cancelNewestJob(list []jobs.Job) string {
+	for _, job := range list {
+		if job.State != jobs.StateQueued && job.State != jobs.StateRunning {
+			continue
+		}
+		if err := jobs.Cancel(job.ID); err != nil {
+			return "✗ " + err.Error()
+		}
+		return "✓ Cancelled " + job.Description
+	}
+	return "No queued or running job to cancel"
+}
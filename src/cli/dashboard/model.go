@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -31,6 +32,7 @@ const (
 	PanelSSUILog              // SSUI backend logs
 	PanelPlayers              // Connected players list
 	PanelConfig               // Configuration editor
+	PanelJobs                 // Background jobs
 	PanelCount                // Used for cycling
 )
 
@@ -39,6 +41,7 @@ var panelNames = map[Panel]string{
 	PanelSSUILog: "Logs",
 	PanelPlayers: "Players",
 	PanelConfig:  "Config",
+	PanelJobs:    "Jobs",
 }
 
 var panelIcons = map[Panel]string{
@@ -46,6 +49,7 @@ var panelIcons = map[Panel]string{
 	PanelSSUILog: "📜",
 	PanelPlayers: "👥",
 	PanelConfig:  "⚙",
+	PanelJobs:    "⏳",
 }
 
 const (
@@ -127,6 +131,11 @@ type Model struct {
 	backupWeeklyFor  int
 	backupMonthlyFor int
 
+	// Background Jobs
+
+	backgroundJobs []jobs.Job // newest first
+	jobsStatusMsg  string     // result of the last cancel
+
 	// Config Panel State
 
 	configItems         []ConfigItem           // All config items
@@ -168,6 +177,7 @@ type keyMap struct {
 	Enter    key.Binding // For config editing
 	Space    key.Binding // Toggle sections/bools
 	Save     key.Binding // Save config changes
+	Cancel   key.Binding // Cancel the newest active job
 }
 
 // ShortHelp returns the short help text (displayed in footer)
@@ -181,7 +191,7 @@ func (k keyMap) FullHelp() [][]key.Binding {
 		{k.Tab, k.ShiftTab, k.Up, k.Down},
 		{k.PageUp, k.PageDown, k.Home, k.End},
 		{k.Start, k.Stop, k.Refresh},
-		{k.Enter, k.Space, k.Save},
+		{k.Enter, k.Space, k.Save, k.Cancel},
 		{k.Help, k.Quit},
 	}
 }
@@ -248,6 +258,10 @@ func defaultKeyMap() keyMap {
 			key.WithKeys("ctrl+s"),
 			key.WithHelp("ctrl+s", "save config"),
 		),
+		Cancel: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "cancel job"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "esc", "ctrl+c"),
 			key.WithHelp("q/esc", "exit"),
@@ -347,6 +361,9 @@ type statusUpdateMsg struct {
 	backupDailyFor   int
 	backupWeeklyFor  int
 	backupMonthlyFor int
+
+	// Jobs
+	backgroundJobs []jobs.Job
 }
 
 // logUpdateMsg is sent when new SSUI logs are available
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/detectionmgr"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
 	"github.com/charmbracelet/bubbles/key"
@@ -118,6 +119,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, stopServerCmd()
 			}
 
+		case key.Matches(msg, m.keys.Cancel):
+			if m.activePanel == PanelJobs {
+				m.jobsStatusMsg = cancelNewestJob(m.backgroundJobs)
+				return m, fetchStatusCmd()
+			}
+
 		case key.Matches(msg, m.keys.Refresh):
 			// Force refresh of all data
 			return m, tea.Batch(fetchStatusCmd(), fetchLogsCmd())
@@ -287,6 +294,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.backupWeeklyFor = msg.backupWeeklyFor
 		m.backupMonthlyFor = msg.backupMonthlyFor
 
+		m.backgroundJobs = msg.backgroundJobs
+
 		m.lastRefresh = msg.startTime
 
 	case logUpdateMsg:
@@ -364,6 +373,9 @@ func fetchStatusCmd() tea.Cmd {
 			backupDailyFor:   int(config.GetBackupKeepDailyFor().Hours() / 24),    // Convert duration to days
 			backupWeeklyFor:  int(config.GetBackupKeepWeeklyFor().Hours() / 168),  // Convert to weeks
 			backupMonthlyFor: int(config.GetBackupKeepMonthlyFor().Hours() / 720), // Convert to months (approx)
+
+			// Jobs
+			backgroundJobs: jobs.List(),
 		}
 	}
 }
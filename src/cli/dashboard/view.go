@@ -51,6 +51,8 @@ func (m Model) View() string {
 		b.WriteString(m.renderPlayersPanel())
 	case PanelConfig:
 		b.WriteString(m.renderConfigPanel())
+	case PanelJobs:
+		b.WriteString(m.renderJobsPanel())
 	}
 
 	b.WriteString("\n")
@@ -463,6 +465,9 @@ func (m Model) renderFooter() string {
 			KeyStyle.Render("ctrl+s")+KeyDescStyle.Render(" save"),
 		)
 	}
+	if m.activePanel == PanelJobs {
+		keyHints = append(keyHints, KeyStyle.Render("c")+KeyDescStyle.Render(" cancel job"))
+	}
 
 	keyHints = append(keyHints,
 		KeyStyle.Render("r")+KeyDescStyle.Render(" refresh"),
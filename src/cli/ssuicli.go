@@ -14,6 +14,7 @@ import (
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/cli/dashboard"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 )
 
@@ -114,9 +115,13 @@ func ProcessCommand(input string) {
 		return
 	}
 
-	if err := handler(args); err != nil {
+	err := handler(args)
+	if err != nil {
 		logger.Core.Error("Command " + commandName + " failed:" + err.Error())
 	}
+	if commandName != "help" && commandName != "h" {
+		audit.RecordError(audit.Entry{ActorType: audit.ActorCLI, Actor: "console", Action: "cli " + commandName, Params: map[string]any{"args": strings.Join(args, " ")}}, err)
+	}
 }
 
 // WrapNoReturn wraps a function with no return value to match CommandFunc.
@@ -55,6 +55,7 @@ type JsonConfig struct {
 	CreateSSUILogFile       *bool    `json:"CreateSSUILogFile"`
 	CreateGameServerLogFile *bool    `json:"CreateGameServerLogFile"`
 	LogLevel                int      `json:"LogLevel"`
+	SSEScrollbackLines      int      `json:"SSEScrollbackLines"` // lines of each live stream replayed to new clients
 	SubsystemFilters        []string `json:"subsystemFilters"`
 	AdvertiserOverride      string   `json:"AdvertiserOverride"`
 
@@ -64,45 +65,86 @@ type JsonConfig struct {
 	JwtKey            string            `json:"JwtKey"`
 	AuthTokenLifetime int               `json:"AuthTokenLifetime"`
 
+	// OpenID Connect login, see core/security/oidc.go
+	OIDCEnabled       *bool              `json:"oidcEnabled"`
+	OIDCIssuerURL     string             `json:"oidcIssuerURL"` // e.g. https://auth.example.com/realms/games, must serve /.well-known/openid-configuration
+	OIDCClientID      string             `json:"oidcClientID"`
+	OIDCClientSecret  string             `json:"oidcClientSecret"` // optional, public clients rely on PKCE only
+	OIDCRedirectURL   string             `json:"oidcRedirectURL"`  // required, e.g. https://ssui.example.com/auth/oidc/callback, never derived from the request Host
+	OIDCScopes        []string           `json:"oidcScopes"`
+	OIDCUsernameClaim string             `json:"oidcUsernameClaim"` // ID token claim used as SSUI username, prefixed with "oidc-" unless a mapping names the user
+	OIDCGroupsClaim   string             `json:"oidcGroupsClaim"`   // ID token claim holding the user's groups
+	OIDCGroupMappings []OIDCGroupMapping `json:"oidcGroupMappings"` // first mapping matching one of the user's groups decides the login, no match denies it
+
+	// ACME (Let's Encrypt) certificates, see core/security/acme.go
+	ACMEEnabled               *bool             `json:"acmeEnabled"`
+	ACMEDirectoryURL          string            `json:"acmeDirectoryURL"` // defaults to Let's Encrypt production, e.g. https://localhost:14000/dir for a local Pebble
+	ACMEEmail                 string            `json:"acmeEmail"`        // optional account contact for expiry notices of the CA
+	ACMEDomains               []string          `json:"acmeDomains"`      // first domain is the certificate's common name, wildcards need dns-01
+	ACMEChallenge             string            `json:"acmeChallenge"`    // "http-01" or "dns-01"
+	ACMEHTTPPort              string            `json:"acmeHTTPPort"`     // plain HTTP port answering http-01 challenges, the CA always connects to port 80
+	ACMEDNSProvider           string            `json:"acmeDNSProvider"`  // dns-01 provider, "exec" or "webhook"
+	ACMEDNSProviderSettings   map[string]string `json:"acmeDNSProviderSettings"`
+	ACMEDNSPropagationSeconds int               `json:"acmeDNSPropagationSeconds"` // wait after creating the TXT record before asking the CA to validate
+	ACMECACertPath            string            `json:"acmeCACertPath"`            // optional PEM root trusted for the ACME directory, e.g. Pebble's test CA
+	ACMERenewDays             int               `json:"acmeRenewDays"`             // renew this many days before expiry
+
 	// SSUI Settings
-	IsNewTerrainAndSaveSystem *bool  `json:"IsNewTerrainAndSaveSystem"` // Use new terrain and save system
-	ExePath                   string `json:"ExePath"`
-	LogClutterToConsole       *bool  `json:"LogClutterToConsole"`
-	IsSSCMEnabled             *bool  `json:"IsSSCMEnabled"`
-	AutoRestartServerTimer    string `json:"AutoRestartServerTimer"`
-	AutoRestartCountdown      string `json:"AutoRestartCountdown"`
-	IsConsoleEnabled          *bool  `json:"IsConsoleEnabled"`
-	IsCLIDashboardEnabled     *bool  `json:"IsCLIDashboardEnabled"`
-	LanguageSetting           string `json:"LanguageSetting"`
-	AutoStartServerOnStartup  *bool  `json:"AutoStartServerOnStartup"`
-	SSUIIdentifier            string `json:"SSUIIdentifier"`
-	SSUIWebPort               string `json:"SSUIWebPort"`
-	ShowExpertSettings        *bool  `json:"ShowExpertSettings"` // Show Expert Settings tab in web UI
+	IsNewTerrainAndSaveSystem *bool    `json:"IsNewTerrainAndSaveSystem"` // Use new terrain and save system
+	ExePath                   string   `json:"ExePath"`
+	LogClutterToConsole       *bool    `json:"LogClutterToConsole"`
+	IsSSCMEnabled             *bool    `json:"IsSSCMEnabled"`
+	AutoRestartServerTimer    string   `json:"AutoRestartServerTimer"`
+	AutoRestartCountdown      string   `json:"AutoRestartCountdown"`
+	IsConsoleEnabled          *bool    `json:"IsConsoleEnabled"`
+	IsCLIDashboardEnabled     *bool    `json:"IsCLIDashboardEnabled"`
+	LanguageSetting           string   `json:"LanguageSetting"`
+	AutoStartServerOnStartup  *bool    `json:"AutoStartServerOnStartup"`
+	SSUIIdentifier            string   `json:"SSUIIdentifier"`
+	SSUIWebPort               string   `json:"SSUIWebPort"`
+	SSUIWebBindAddress        string   `json:"SSUIWebBindAddress"` // IP address the web server listens on, e.g. 127.0.0.1 behind a local reverse proxy
+	SSUIWebPlainHTTP          *bool    `json:"SSUIWebPlainHTTP"`   // serve plain HTTP for a TLS terminating reverse proxy in front of SSUI
+	SSUIWebBasePath           string   `json:"SSUIWebBasePath"`    // URL prefix SSUI is served under, e.g. /ssui for https://tools.example/ssui/
+	TrustedProxies            []string `json:"trustedProxies"`     // IPs or CIDRs whose X-Forwarded-For/Proto headers are believed
+	ShowExpertSettings        *bool    `json:"ShowExpertSettings"` // Show Expert Settings tab in web UI
 
 	// Update Settings
-	IsUpdateEnabled            *bool `json:"IsUpdateEnabled"`
-	AllowPrereleaseUpdates     *bool `json:"AllowPrereleaseUpdates"`
-	AllowMajorUpdates          *bool `json:"AllowMajorUpdates"`
-	AllowAutoGameServerUpdates *bool `json:"AllowAutoGameServerUpdates"`
+	IsUpdateEnabled                  *bool  `json:"IsUpdateEnabled"`
+	AllowPrereleaseUpdates           *bool  `json:"AllowPrereleaseUpdates"`
+	AllowMajorUpdates                *bool  `json:"AllowMajorUpdates"`
+	AllowAutoGameServerUpdates       *bool  `json:"AllowAutoGameServerUpdates"`
+	AutoGameServerUpdateWaitForEmpty *bool  `json:"AutoGameServerUpdateWaitForEmpty"` // postpone automatic game updates until no player is connected
+	AutoGameServerUpdateWindow       string `json:"AutoGameServerUpdateWindow"`       // HH:MM-HH:MM maintenance window automatic game updates may run in, empty for none
 
 	// SLP Modding Settings
-	IsStationeersLaunchPadEnabled            *bool `json:"IsStationeersLaunchPadEnabled"`
-	IsStationeersLaunchPadAutoUpdatesEnabled *bool `json:"IsStationeersLaunchPadAutoUpdatesEnabled"`
+	IsStationeersLaunchPadEnabled            *bool  `json:"IsStationeersLaunchPadEnabled"`
+	IsStationeersLaunchPadAutoUpdatesEnabled *bool  `json:"IsStationeersLaunchPadAutoUpdatesEnabled"`
+	SLPVersion                               string `json:"SLPVersion"` // "latest", "prerelease" or a release tag to pin SLP installs to
+
+	// Artifact Cache Settings
+	ArtifactCacheFolder string `json:"ArtifactCacheFolder"` // installers look for their downloads here first and store what they download
+	ArtifactMirrorURL   string `json:"ArtifactMirrorURL"`   // serves artifacts by cache file name, tried before the upstream download
 
 	// Discord Settings
-	DiscordToken            string `json:"discordToken"`
-	ControlChannelID        string `json:"controlChannelID"`
-	EventLogChannelID       string `json:"eventLogChannelID"`
-	StatusChannelID         string `json:"statusChannelID,omitempty"`         // deprecated, migrated to EventLogChannelID
-	ConnectionListChannelID string `json:"connectionListChannelID,omitempty"` // deprecated, migrated to StatusPanelChannelID
-	StatusPanelChannelID    string `json:"statusPanelChannelID"`              // replaces ConnectionListChannelID and ServerInfoPanelChannelID
-	LogChannelID            string `json:"logChannelID"`
-	SaveChannelID           string `json:"saveChannelID,omitempty"` // deprecated, merged into EventLogChannelID
-	ControlPanelChannelID   string `json:"controlPanelChannelID"`
-	DiscordCharBufferSize   int    `json:"DiscordCharBufferSize"`
-	BlackListFilePath       string `json:"blackListFilePath"`
-	IsDiscordEnabled        *bool  `json:"isDiscordEnabled"`
-	RotateServerPassword    *bool  `json:"rotateServerPassword"`
+	DiscordToken              string               `json:"discordToken"`
+	ControlChannelID          string               `json:"controlChannelID"`
+	EventLogChannelID         string               `json:"eventLogChannelID"`
+	StatusChannelID           string               `json:"statusChannelID,omitempty"`         // deprecated, migrated to EventLogChannelID
+	ConnectionListChannelID   string               `json:"connectionListChannelID,omitempty"` // deprecated, migrated to StatusPanelChannelID
+	StatusPanelChannelID      string               `json:"statusPanelChannelID"`              // replaces ConnectionListChannelID and ServerInfoPanelChannelID
+	LogChannelID              string               `json:"logChannelID"`
+	SaveChannelID             string               `json:"saveChannelID,omitempty"` // deprecated, merged into EventLogChannelID
+	ControlPanelChannelID     string               `json:"controlPanelChannelID"`
+	ChatBridgeChannelID       string               `json:"chatBridgeChannelID"` // two-way in-game chat bridge, empty disables it
+	DiscordRoutingRules       []DiscordRoutingRule `json:"discordRoutingRules"` // per event type channel routing, unmatched events go to EventLogChannelID
+	DiscordCharBufferSize     int                  `json:"DiscordCharBufferSize"`
+	LogChannelIncludePatterns []string             `json:"logChannelIncludePatterns"` // regexes, if set only matching console lines are forwarded to LogChannelID
+	LogChannelExcludePatterns []string             `json:"logChannelExcludePatterns"` // regexes, matching console lines are never forwarded to LogChannelID
+	LogChannelDigestMinutes   int                  `json:"logChannelDigestMinutes"`   // if > 0, upload the log channel buffer as a compressed attachment every N minutes instead of streaming it
+	AuditDiscordMirror        *bool                `json:"auditDiscordMirror"`        // also send audit log entries to Discord as AUDIT events
+	BlackListFilePath         string               `json:"blackListFilePath"`
+	IsDiscordEnabled          *bool                `json:"isDiscordEnabled"`
+	RotateServerPassword      *bool                `json:"rotateServerPassword"`
 
 	//Backup Settings
 	BackupKeepLastN       int   `json:"backupKeepLastN"`       // Number of most recent backups to keep (default: 2000)
@@ -150,9 +192,18 @@ func applyConfig(cfg *JsonConfig) {
 	StatusPanelChannelID = getString(cfg.StatusPanelChannelID, "STATUS_PANEL_CHANNEL_ID", "")
 	LogChannelID = getString(cfg.LogChannelID, "LOG_CHANNEL_ID", "")
 	ControlPanelChannelID = getString(cfg.ControlPanelChannelID, "CONTROL_PANEL_CHANNEL_ID", "")
+	ChatBridgeChannelID = getString(cfg.ChatBridgeChannelID, "CHAT_BRIDGE_CHANNEL_ID", "")
+	DiscordRoutingRules = getRoutingRules(cfg.DiscordRoutingRules, "DISCORD_ROUTING_RULES", []DiscordRoutingRule{})
 	DiscordCharBufferSize = getInt(cfg.DiscordCharBufferSize, "DISCORD_CHAR_BUFFER_SIZE", 1000)
+	LogChannelIncludePatterns = getStringSlice(cfg.LogChannelIncludePatterns, "LOG_CHANNEL_INCLUDE_PATTERNS", []string{})
+	LogChannelExcludePatterns = getStringSlice(cfg.LogChannelExcludePatterns, "LOG_CHANNEL_EXCLUDE_PATTERNS", []string{})
+	LogChannelDigestMinutes = getInt(cfg.LogChannelDigestMinutes, "LOG_CHANNEL_DIGEST_MINUTES", 0)
 	BlackListFilePath = getString(cfg.BlackListFilePath, "BLACKLIST_FILE_PATH", "./Blacklist.txt")
 
+	auditDiscordMirrorVal := getBool(cfg.AuditDiscordMirror, "AUDIT_DISCORD_MIRROR", false)
+	AuditDiscordMirror = auditDiscordMirrorVal
+	cfg.AuditDiscordMirror = &auditDiscordMirrorVal
+
 	isDiscordEnabledVal := getBool(cfg.IsDiscordEnabled, "IS_DISCORD_ENABLED", false)
 	IsDiscordEnabled = isDiscordEnabledVal
 	cfg.IsDiscordEnabled = &isDiscordEnabledVal
@@ -194,6 +245,12 @@ func applyConfig(cfg *JsonConfig) {
 	LanguageSetting = getString(cfg.LanguageSetting, "LANGUAGE_SETTING", "en-US")
 	SSUIIdentifier = getString(cfg.SSUIIdentifier, "SSUI_IDENTIFIER", "")
 	SSUIWebPort = getString(cfg.SSUIWebPort, "SSUI_WEB_PORT", "8443")
+	SSUIWebBindAddress = getString(cfg.SSUIWebBindAddress, "SSUI_WEB_BIND_ADDRESS", "0.0.0.0")
+	ssuiWebPlainHTTPVal := getBool(cfg.SSUIWebPlainHTTP, "SSUI_WEB_PLAIN_HTTP", false)
+	SSUIWebPlainHTTP = ssuiWebPlainHTTPVal
+	cfg.SSUIWebPlainHTTP = &ssuiWebPlainHTTPVal
+	SSUIWebBasePath = normalizeBasePath(getString(cfg.SSUIWebBasePath, "SSUI_WEB_BASE_PATH", ""))
+	TrustedProxies = getStringSlice(cfg.TrustedProxies, "SSUI_TRUSTED_PROXIES", []string{})
 
 	upnpEnabledVal := getBool(cfg.UPNPEnabled, "UPNP_ENABLED", false)
 	UPNPEnabled = upnpEnabledVal
@@ -234,6 +291,32 @@ func applyConfig(cfg *JsonConfig) {
 	JwtKey = getString(cfg.JwtKey, "SSUI_JWT_KEY", generateJwtKey())
 	AuthTokenLifetime = getInt(cfg.AuthTokenLifetime, "SSUI_AUTH_TOKEN_LIFETIME", 1440)
 
+	oidcEnabledVal := getBool(cfg.OIDCEnabled, "SSUI_OIDC_ENABLED", false)
+	OIDCEnabled = oidcEnabledVal
+	cfg.OIDCEnabled = &oidcEnabledVal
+	OIDCIssuerURL = getString(cfg.OIDCIssuerURL, "SSUI_OIDC_ISSUER_URL", "")
+	OIDCClientID = getString(cfg.OIDCClientID, "SSUI_OIDC_CLIENT_ID", "")
+	OIDCClientSecret = getString(cfg.OIDCClientSecret, "SSUI_OIDC_CLIENT_SECRET", "")
+	OIDCRedirectURL = getString(cfg.OIDCRedirectURL, "SSUI_OIDC_REDIRECT_URL", "")
+	OIDCScopes = getStringSlice(cfg.OIDCScopes, "SSUI_OIDC_SCOPES", []string{"openid", "profile", "email", "groups"})
+	OIDCUsernameClaim = getString(cfg.OIDCUsernameClaim, "SSUI_OIDC_USERNAME_CLAIM", "preferred_username")
+	OIDCGroupsClaim = getString(cfg.OIDCGroupsClaim, "SSUI_OIDC_GROUPS_CLAIM", "groups")
+	OIDCGroupMappings = getOIDCGroupMappings(cfg.OIDCGroupMappings, "SSUI_OIDC_GROUP_MAPPINGS", []OIDCGroupMapping{})
+
+	acmeEnabledVal := getBool(cfg.ACMEEnabled, "SSUI_ACME_ENABLED", false)
+	ACMEEnabled = acmeEnabledVal
+	cfg.ACMEEnabled = &acmeEnabledVal
+	ACMEDirectoryURL = getString(cfg.ACMEDirectoryURL, "SSUI_ACME_DIRECTORY_URL", "https://acme-v02.api.letsencrypt.org/directory")
+	ACMEEmail = getString(cfg.ACMEEmail, "SSUI_ACME_EMAIL", "")
+	ACMEDomains = getStringSlice(cfg.ACMEDomains, "SSUI_ACME_DOMAINS", []string{})
+	ACMEChallenge = getString(cfg.ACMEChallenge, "SSUI_ACME_CHALLENGE", "http-01")
+	ACMEHTTPPort = getString(cfg.ACMEHTTPPort, "SSUI_ACME_HTTP_PORT", "80")
+	ACMEDNSProvider = getString(cfg.ACMEDNSProvider, "SSUI_ACME_DNS_PROVIDER", "")
+	ACMEDNSProviderSettings = getStringMap(cfg.ACMEDNSProviderSettings, "SSUI_ACME_DNS_PROVIDER_SETTINGS", map[string]string{})
+	ACMEDNSPropagationSeconds = getInt(cfg.ACMEDNSPropagationSeconds, "SSUI_ACME_DNS_PROPAGATION_SECONDS", 30)
+	ACMECACertPath = getString(cfg.ACMECACertPath, "SSUI_ACME_CA_CERT_PATH", "")
+	ACMERenewDays = getInt(cfg.ACMERenewDays, "SSUI_ACME_RENEW_DAYS", 30)
+
 	debugVal := getBool(cfg.Debug, "DEBUG", false)
 	IsDebugMode = debugVal
 	cfg.Debug = &debugVal
@@ -247,6 +330,7 @@ func applyConfig(cfg *JsonConfig) {
 	cfg.CreateGameServerLogFile = &createGameServerLogFileVal
 
 	LogLevel = getInt(cfg.LogLevel, "LOG_LEVEL", 20)
+	SSEScrollbackLines = getInt(cfg.SSEScrollbackLines, "SSE_SCROLLBACK_LINES", 500)
 
 	isUpdateEnabledVal := getBool(cfg.IsUpdateEnabled, "IS_UPDATE_ENABLED", true)
 	IsUpdateEnabled = isUpdateEnabledVal
@@ -264,6 +348,16 @@ func applyConfig(cfg *JsonConfig) {
 	AllowAutoGameServerUpdates = allowAutoGameServerUpdatesVal
 	cfg.AllowAutoGameServerUpdates = &allowAutoGameServerUpdatesVal
 
+	autoGameServerUpdateWaitForEmptyVal := getBool(cfg.AutoGameServerUpdateWaitForEmpty, "AUTO_GAME_SERVER_UPDATE_WAIT_FOR_EMPTY", true)
+	AutoGameServerUpdateWaitForEmpty = autoGameServerUpdateWaitForEmptyVal
+	cfg.AutoGameServerUpdateWaitForEmpty = &autoGameServerUpdateWaitForEmptyVal
+
+	AutoGameServerUpdateWindow = getString(cfg.AutoGameServerUpdateWindow, "AUTO_GAME_SERVER_UPDATE_WINDOW", "")
+	if _, _, err := ParseMaintenanceWindow(AutoGameServerUpdateWindow); err != nil {
+		fmt.Println("Ignoring AutoGameServerUpdateWindow: " + err.Error())
+		AutoGameServerUpdateWindow = ""
+	}
+
 	isStationeersLaunchPadEnabledVal := getBool(cfg.IsStationeersLaunchPadEnabled, "IS_SLP_MODDING_ENABLED", false)
 	IsStationeersLaunchPadEnabled = isStationeersLaunchPadEnabledVal
 	cfg.IsStationeersLaunchPadEnabled = &isStationeersLaunchPadEnabledVal
@@ -272,6 +366,15 @@ func applyConfig(cfg *JsonConfig) {
 	IsStationeersLaunchPadAutoUpdatesEnabled = isStationeersLaunchPadAutoUpdatesEnabledVal
 	cfg.IsStationeersLaunchPadAutoUpdatesEnabled = &isStationeersLaunchPadAutoUpdatesEnabledVal
 
+	SLPVersion = getString(cfg.SLPVersion, "SLP_VERSION", "latest")
+
+	ArtifactCacheFolder = getString(cfg.ArtifactCacheFolder, "ARTIFACT_CACHE_FOLDER", "./UIMod/artifacts/")
+	ArtifactMirrorURL = getString(cfg.ArtifactMirrorURL, "ARTIFACT_MIRROR_URL", "")
+	if err := ValidateArtifactMirrorURL(ArtifactMirrorURL); err != nil {
+		fmt.Println("Ignoring ArtifactMirrorURL: " + err.Error())
+		ArtifactMirrorURL = ""
+	}
+
 	SubsystemFilters = getStringSlice(cfg.SubsystemFilters, "SUBSYSTEM_FILTERS", []string{})
 	AutoRestartServerTimer = getString(cfg.AutoRestartServerTimer, "AUTO_RESTART_SERVER_TIMER", "0")
 	AutoRestartCountdown = getString(cfg.AutoRestartCountdown, "AUTO_RESTART_COUNTDOWN", "65")
@@ -383,7 +486,13 @@ func safeSaveConfig() error {
 		StatusPanelChannelID:                     StatusPanelChannelID,
 		LogChannelID:                             LogChannelID,
 		ControlPanelChannelID:                    ControlPanelChannelID,
+		ChatBridgeChannelID:                      ChatBridgeChannelID,
+		DiscordRoutingRules:                      DiscordRoutingRules,
 		DiscordCharBufferSize:                    DiscordCharBufferSize,
+		LogChannelIncludePatterns:                LogChannelIncludePatterns,
+		LogChannelExcludePatterns:                LogChannelExcludePatterns,
+		LogChannelDigestMinutes:                  LogChannelDigestMinutes,
+		AuditDiscordMirror:                       &AuditDiscordMirror,
 		BlackListFilePath:                        BlackListFilePath,
 		IsDiscordEnabled:                         &IsDiscordEnabled,
 		RotateServerPassword:                     &RotateServerPassword,
@@ -422,10 +531,31 @@ func safeSaveConfig() error {
 		AuthEnabled:                              &AuthEnabled,
 		JwtKey:                                   JwtKey,
 		AuthTokenLifetime:                        AuthTokenLifetime,
+		OIDCEnabled:                              &OIDCEnabled,
+		OIDCIssuerURL:                            OIDCIssuerURL,
+		OIDCClientID:                             OIDCClientID,
+		OIDCClientSecret:                         OIDCClientSecret,
+		OIDCRedirectURL:                          OIDCRedirectURL,
+		OIDCScopes:                               OIDCScopes,
+		OIDCUsernameClaim:                        OIDCUsernameClaim,
+		OIDCGroupsClaim:                          OIDCGroupsClaim,
+		OIDCGroupMappings:                        OIDCGroupMappings,
+		ACMEEnabled:                              &ACMEEnabled,
+		ACMEDirectoryURL:                         ACMEDirectoryURL,
+		ACMEEmail:                                ACMEEmail,
+		ACMEDomains:                              ACMEDomains,
+		ACMEChallenge:                            ACMEChallenge,
+		ACMEHTTPPort:                             ACMEHTTPPort,
+		ACMEDNSProvider:                          ACMEDNSProvider,
+		ACMEDNSProviderSettings:                  ACMEDNSProviderSettings,
+		ACMEDNSPropagationSeconds:                ACMEDNSPropagationSeconds,
+		ACMECACertPath:                           ACMECACertPath,
+		ACMERenewDays:                            ACMERenewDays,
 		Debug:                                    &IsDebugMode,
 		CreateSSUILogFile:                        &CreateSSUILogFile,
 		CreateGameServerLogFile:                  &CreateGameServerLogFile,
 		LogLevel:                                 LogLevel,
+		SSEScrollbackLines:                       SSEScrollbackLines,
 		LogClutterToConsole:                      &LogClutterToConsole,
 		SubsystemFilters:                         SubsystemFilters,
 		IsUpdateEnabled:                          &IsUpdateEnabled,
@@ -435,14 +565,23 @@ func safeSaveConfig() error {
 		AllowPrereleaseUpdates:                   &AllowPrereleaseUpdates,
 		AllowMajorUpdates:                        &AllowMajorUpdates,
 		AllowAutoGameServerUpdates:               &AllowAutoGameServerUpdates,
+		AutoGameServerUpdateWaitForEmpty:         &AutoGameServerUpdateWaitForEmpty,
+		AutoGameServerUpdateWindow:               AutoGameServerUpdateWindow,
 		IsStationeersLaunchPadEnabled:            &IsStationeersLaunchPadEnabled,
 		IsStationeersLaunchPadAutoUpdatesEnabled: &IsStationeersLaunchPadAutoUpdatesEnabled,
+		SLPVersion:                               SLPVersion,
+		ArtifactCacheFolder:                      ArtifactCacheFolder,
+		ArtifactMirrorURL:                        ArtifactMirrorURL,
 		IsConsoleEnabled:                         &IsConsoleEnabled,
 		IsCLIDashboardEnabled:                    &IsCLIDashboardEnabled,
 		LanguageSetting:                          LanguageSetting,
 		AutoStartServerOnStartup:                 &AutoStartServerOnStartup,
 		SSUIIdentifier:                           SSUIIdentifier,
 		SSUIWebPort:                              SSUIWebPort,
+		SSUIWebBindAddress:                       SSUIWebBindAddress,
+		SSUIWebPlainHTTP:                         &SSUIWebPlainHTTP,
+		SSUIWebBasePath:                          SSUIWebBasePath,
+		TrustedProxies:                           TrustedProxies,
 		AdvertiserOverride:                       AdvertiserOverride,
 		ShowExpertSettings:                       &ShowExpertSettings,
 	}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"maps"
+	"slices"
 	"time"
 )
 
@@ -40,6 +42,47 @@ func GetControlPanelChannelID() string {
 	return ControlPanelChannelID
 }
 
+func GetChatBridgeChannelID() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return ChatBridgeChannelID
+}
+
+// GetDiscordRoutingRules returns a copy of the Discord routing rules
+func GetDiscordRoutingRules() []DiscordRoutingRule {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	rules := make([]DiscordRoutingRule, len(DiscordRoutingRules))
+	copy(rules, DiscordRoutingRules)
+	return rules
+}
+
+// GetLogChannelIncludePatterns returns a copy of the log channel include regexes
+func GetLogChannelIncludePatterns() []string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return append([]string(nil), LogChannelIncludePatterns...)
+}
+
+// GetLogChannelExcludePatterns returns a copy of the log channel exclude regexes
+func GetLogChannelExcludePatterns() []string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return append([]string(nil), LogChannelExcludePatterns...)
+}
+
+func GetLogChannelDigestMinutes() int {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return LogChannelDigestMinutes
+}
+
+func GetAuditDiscordMirror() bool {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return AuditDiscordMirror
+}
+
 func GetDiscordCharBufferSize() int {
 	ConfigMu.RLock()
 	defer ConfigMu.RUnlock()
@@ -286,6 +329,88 @@ func GetAuthTokenLifetime() int {
 	return AuthTokenLifetime
 }
 
+func GetOIDCEnabled() bool {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return OIDCEnabled
+}
+
+func GetOIDCIssuerURL() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return OIDCIssuerURL
+}
+
+func GetOIDCClientID() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return OIDCClientID
+}
+
+func GetOIDCClientSecret() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return OIDCClientSecret
+}
+
+func GetOIDCRedirectURL() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return OIDCRedirectURL
+}
+
+func GetOIDCScopes() []string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return OIDCScopes
+}
+
+func GetOIDCUsernameClaim() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return OIDCUsernameClaim
+}
+
+func GetOIDCGroupsClaim() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return OIDCGroupsClaim
+}
+
+func GetACMEEnabled() bool {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return ACMEEnabled
+}
+
+// GetACMESettings returns a copy of the ACME certificate settings
+func GetACMESettings() ACMESettings {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return ACMESettings{
+		Enabled:               ACMEEnabled,
+		DirectoryURL:          ACMEDirectoryURL,
+		Email:                 ACMEEmail,
+		Domains:               slices.Clone(ACMEDomains),
+		Challenge:             ACMEChallenge,
+		HTTPPort:              ACMEHTTPPort,
+		DNSProvider:           ACMEDNSProvider,
+		DNSProviderSettings:   maps.Clone(ACMEDNSProviderSettings),
+		DNSPropagationSeconds: ACMEDNSPropagationSeconds,
+		CACertPath:            ACMECACertPath,
+		RenewDays:             ACMERenewDays,
+	}
+}
+
+// GetOIDCGroupMappings returns a copy of the OIDC group mappings
+func GetOIDCGroupMappings() []OIDCGroupMapping {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	mappings := make([]OIDCGroupMapping, len(OIDCGroupMappings))
+	copy(mappings, OIDCGroupMappings)
+	return mappings
+}
+
 func GetIsDebugMode() bool {
 	ConfigMu.RLock()
 	defer ConfigMu.RUnlock()
@@ -412,6 +537,32 @@ func GetSSUIIdentifier() string {
 	return SSUIIdentifier
 }
 
+func GetSSUIWebBindAddress() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return SSUIWebBindAddress
+}
+
+func GetSSUIWebPlainHTTP() bool {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return SSUIWebPlainHTTP
+}
+
+// GetSSUIWebBasePath returns the URL prefix SSUI is served under, "" or e.g. "/ssui" without a trailing slash
+func GetSSUIWebBasePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return SSUIWebBasePath
+}
+
+// GetTrustedProxies returns a copy of the trusted reverse proxy IPs and CIDRs
+func GetTrustedProxies() []string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return slices.Clone(TrustedProxies)
+}
+
 func GetSSUIWebPort() string {
 	ConfigMu.RLock()
 	defer ConfigMu.RUnlock()
@@ -479,6 +630,12 @@ func GetSSEMessageBufferSize() int {
 	return SSEMessageBufferSize
 }
 
+func GetSSEScrollbackLines() int {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return SSEScrollbackLines
+}
+
 func GetLogFolder() string {
 	ConfigMu.RLock()
 	defer ConfigMu.RUnlock()
@@ -503,6 +660,72 @@ func GetCustomDetectionsFilePath() string {
 	return CustomDetectionsFilePath
 }
 
+func GetAPIKeysFilePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return APIKeysFilePath
+}
+
+func GetSessionsFilePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return SessionsFilePath
+}
+
+func GetTwoFactorFilePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return TwoFactorFilePath
+}
+
+func GetACMEAccountKeyPath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return ACMEAccountKeyPath
+}
+
+func GetAuditLogFilePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return AuditLogFilePath
+}
+
+func GetInstallHistoryFilePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return InstallHistoryFilePath
+}
+
+func GetInstallSnapshotsFolder() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return InstallSnapshotsFolder
+}
+
+func GetModManifestFilePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return ModManifestFilePath
+}
+
+func GetModCompatFilePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return ModCompatFilePath
+}
+
+func GetSLPInstallFilePath() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return SLPInstallFilePath
+}
+
+func GetSLPPreviousFolder() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return SLPPreviousFolder
+}
+
 func GetGameServerAppID() string {
 	ConfigMu.RLock()
 	defer ConfigMu.RUnlock()
@@ -521,6 +744,18 @@ func GetAllowAutoGameServerUpdates() bool {
 	return AllowAutoGameServerUpdates
 }
 
+func GetAutoGameServerUpdateWaitForEmpty() bool {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return AutoGameServerUpdateWaitForEmpty
+}
+
+func GetAutoGameServerUpdateWindow() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return AutoGameServerUpdateWindow
+}
+
 func GetExtractedGameVersion() string {
 	ConfigMu.RLock()
 	defer ConfigMu.RUnlock()
@@ -573,3 +808,21 @@ func GetIsStationeersLaunchPadAutoUpdatesEnabled() bool {
 	defer ConfigMu.RUnlock()
 	return IsStationeersLaunchPadAutoUpdatesEnabled
 }
+
+func GetSLPVersion() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return SLPVersion
+}
+
+func GetArtifactCacheFolder() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return ArtifactCacheFolder
+}
+
+func GetArtifactMirrorURL() string {
+	ConfigMu.RLock()
+	defer ConfigMu.RUnlock()
+	return ArtifactMirrorURL
+}
@@ -6,11 +6,14 @@ import (
 	"crypto/rand"
 	"embed"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func getString(jsonVal, envKey, defaultVal string) string {
@@ -89,6 +92,101 @@ func getUsers(jsonValue map[string]string, envKey string, defaultValue map[strin
 	return defaultValue
 }
 
+// getRoutingRules retrieves the Discord routing rules with JSON -> env -> default hierarchy
+func getRoutingRules(jsonValue []DiscordRoutingRule, envKey string, defaultValue []DiscordRoutingRule) []DiscordRoutingRule {
+	if jsonValue != nil {
+		return jsonValue
+	}
+	if envValue := os.Getenv(envKey); envValue != "" {
+		// Expect env var as the same JSON array that is used in config.json
+		var rules []DiscordRoutingRule
+		if err := json.Unmarshal([]byte(envValue), &rules); err == nil {
+			return rules
+		}
+		fmt.Println("Failed to parse " + envKey + ", expected a JSON array of routing rules")
+	}
+	return defaultValue
+}
+
+// getOIDCGroupMappings retrieves the OIDC group mappings with JSON -> env -> default hierarchy
+func getOIDCGroupMappings(jsonValue []OIDCGroupMapping, envKey string, defaultValue []OIDCGroupMapping) []OIDCGroupMapping {
+	if jsonValue != nil {
+		return jsonValue
+	}
+	if envValue := os.Getenv(envKey); envValue != "" {
+		// Expect env var as the same JSON array that is used in config.json
+		var mappings []OIDCGroupMapping
+		if err := json.Unmarshal([]byte(envValue), &mappings); err == nil {
+			return mappings
+		}
+		fmt.Println("Failed to parse " + envKey + ", expected a JSON array of group mappings")
+	}
+	return defaultValue
+}
+
+// getStringMap retrieves a string map with JSON -> env -> default hierarchy
+func getStringMap(jsonValue map[string]string, envKey string, defaultValue map[string]string) map[string]string {
+	if jsonValue != nil {
+		return jsonValue
+	}
+	if envValue := os.Getenv(envKey); envValue != "" {
+		// Expect env var as the same JSON object that is used in config.json
+		var values map[string]string
+		if err := json.Unmarshal([]byte(envValue), &values); err == nil {
+			return values
+		}
+		fmt.Println("Failed to parse " + envKey + ", expected a JSON object of strings")
+	}
+	return defaultValue
+}
+
+// normalizeBasePath turns "ssui/", "/ssui/" and "/ssui" into "/ssui", and "/" into ""
+func normalizeBasePath(value string) string {
+	value = strings.Trim(strings.TrimSpace(value), "/")
+	if value == "" {
+		return ""
+	}
+	return "/" + value
+}
+
+// ParseMaintenanceWindow parses an HH:MM-HH:MM window into minutes after midnight. The window may wrap past midnight, e.g. 23:00-02:00.
+// An empty window is valid and returns -1, -1.
+func ParseMaintenanceWindow(window string) (start, end int, err error) {
+	if window == "" {
+		return -1, -1, nil
+	}
+	from, to, ok := strings.Cut(window, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("maintenance window %q must look like HH:MM-HH:MM", window)
+	}
+	for _, part := range []struct {
+		value  string
+		target *int
+	}{{from, &start}, {to, &end}} {
+		t, err := time.Parse("15:04", strings.TrimSpace(part.value))
+		if err != nil {
+			return 0, 0, fmt.Errorf("maintenance window %q must look like HH:MM-HH:MM", window)
+		}
+		*part.target = t.Hour()*60 + t.Minute()
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("maintenance window %q is empty", window)
+	}
+	return start, end, nil
+}
+
+// ValidateArtifactMirrorURL checks that a mirror is an http(s) URL. An empty mirror is valid and means none.
+func ValidateArtifactMirrorURL(mirror string) error {
+	if mirror == "" {
+		return nil
+	}
+	parsed, err := url.Parse(mirror)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("artifact mirror %q must be an http or https URL", mirror)
+	}
+	return nil
+}
+
 func getDefaultExePath() string {
 	if runtime.GOOS == "windows" {
 		return "./rocketstation_DedicatedServer.exe"
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -166,6 +168,19 @@ func SetSSEMessageBufferSize(value int) error {
 	return safeSaveConfig()
 }
 
+// SetSSEScrollbackLines sets how many lines of each live stream are replayed to new clients
+func SetSSEScrollbackLines(value int) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	if value <= 0 {
+		return fmt.Errorf("SSE scrollback lines must be positive")
+	}
+
+	SSEScrollbackLines = value
+	return safeSaveConfig()
+}
+
 // SetMaxSSEConnections sets the MaxSSEConnections with validation
 func SetMaxSSEConnections(value int) error {
 	ConfigMu.Lock()
@@ -571,6 +586,78 @@ func SetControlPanelChannelID(value string) error {
 	return safeSaveConfig()
 }
 
+// SetChatBridgeChannelID sets the ChatBridgeChannelID
+func SetChatBridgeChannelID(value string) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	ChatBridgeChannelID = value
+	return safeSaveConfig()
+}
+
+// SetDiscordRoutingRules replaces the Discord routing rules with validation
+func SetDiscordRoutingRules(value []DiscordRoutingRule) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	for i, rule := range value {
+		if len(rule.EventTypes) == 0 {
+			return fmt.Errorf("routing rule %d must match at least one event type", i)
+		}
+		if len(rule.ChannelIDs) == 0 {
+			return fmt.Errorf("routing rule %d must have at least one channel ID", i)
+		}
+		for _, id := range rule.ChannelIDs {
+			if id == "" || strings.Trim(id, "0123456789") != "" {
+				return fmt.Errorf("routing rule %d contains an invalid channel ID: %q", i, id)
+			}
+		}
+		if strings.Trim(rule.MentionRoleID, "0123456789") != "" {
+			return fmt.Errorf("routing rule %d contains an invalid role ID: %q", i, rule.MentionRoleID)
+		}
+	}
+
+	DiscordRoutingRules = value
+	return safeSaveConfig()
+}
+
+// SetLogChannelFilters sets the log channel include and exclude regexes with validation
+func SetLogChannelFilters(include, exclude []string) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid log channel filter %q: %w", pattern, err)
+		}
+	}
+
+	LogChannelIncludePatterns = include
+	LogChannelExcludePatterns = exclude
+	return safeSaveConfig()
+}
+
+// SetLogChannelDigestMinutes sets the log channel digest interval, 0 streams the log instead
+func SetLogChannelDigestMinutes(value int) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	if value < 0 {
+		return fmt.Errorf("log channel digest interval cannot be negative")
+	}
+
+	LogChannelDigestMinutes = value
+	return safeSaveConfig()
+}
+
+func SetAuditDiscordMirror(value bool) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	AuditDiscordMirror = value
+	return safeSaveConfig()
+}
+
 // SetDiscordCharBufferSize sets the DiscordCharBufferSize with validation
 func SetDiscordCharBufferSize(value int) error {
 	ConfigMu.Lock()
@@ -639,6 +726,103 @@ func SetAuthTokenLifetime(value int) error {
 	return safeSaveConfig()
 }
 
+// SetOIDCSettings sets the OpenID Connect login settings with validation
+func SetOIDCSettings(enabled bool, issuerURL, clientID, clientSecret, redirectURL string) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	issuerURL = strings.TrimRight(strings.TrimSpace(issuerURL), "/")
+	redirectURL = strings.TrimSpace(redirectURL)
+	if enabled && (issuerURL == "" || clientID == "" || redirectURL == "") {
+		return fmt.Errorf("OIDC login requires an issuer URL, a client ID and a redirect URL")
+	}
+	if issuerURL != "" && !strings.HasPrefix(issuerURL, "https://") && !strings.HasPrefix(issuerURL, "http://") {
+		return fmt.Errorf("OIDC issuer URL must start with https:// or http://")
+	}
+	if redirectURL != "" && !strings.HasPrefix(redirectURL, "https://") && !strings.HasPrefix(redirectURL, "http://") {
+		return fmt.Errorf("OIDC redirect URL must start with https:// or http://")
+	}
+
+	OIDCEnabled = enabled
+	OIDCIssuerURL = issuerURL
+	OIDCClientID = strings.TrimSpace(clientID)
+	OIDCClientSecret = clientSecret
+	OIDCRedirectURL = redirectURL
+	return safeSaveConfig()
+}
+
+// SetOIDCGroupMappings replaces the OIDC group mappings with validation
+func SetOIDCGroupMappings(value []OIDCGroupMapping) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	for i, mapping := range value {
+		if strings.TrimSpace(mapping.Group) == "" {
+			return fmt.Errorf("group mapping %d must name a group", i)
+		}
+		switch mapping.Role {
+		case "", "read", "control", "admin":
+		default:
+			return fmt.Errorf("group mapping %d has an unknown role %q, valid roles are read, control, admin", i, mapping.Role)
+		}
+	}
+
+	OIDCGroupMappings = value
+	return safeSaveConfig()
+}
+
+// SetACMESettings replaces the ACME certificate settings with validation
+func SetACMESettings(value ACMESettings) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	var domains []string
+	for _, domain := range value.Domains {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	if value.Enabled && len(domains) == 0 {
+		return fmt.Errorf("ACME certificates require at least one domain")
+	}
+	switch value.Challenge {
+	case "http-01":
+		for _, domain := range domains {
+			if strings.HasPrefix(domain, "*.") {
+				return fmt.Errorf("wildcard domain %s requires the dns-01 challenge", domain)
+			}
+		}
+	case "dns-01":
+		if value.Enabled && value.DNSProvider == "" {
+			return fmt.Errorf("the dns-01 challenge requires a DNS provider")
+		}
+	default:
+		return fmt.Errorf("unknown ACME challenge %q, valid challenges are http-01, dns-01", value.Challenge)
+	}
+	if !strings.HasPrefix(value.DirectoryURL, "https://") && !strings.HasPrefix(value.DirectoryURL, "http://") {
+		return fmt.Errorf("ACME directory URL must start with https:// or http://")
+	}
+	if port, err := strconv.Atoi(value.HTTPPort); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("ACME HTTP port must be a number between 1 and 65535")
+	}
+	if value.RenewDays < 1 || value.DNSPropagationSeconds < 0 {
+		return fmt.Errorf("ACME renewal days must be positive and the DNS propagation delay must not be negative")
+	}
+
+	ACMEEnabled = value.Enabled
+	ACMEDirectoryURL = strings.TrimSpace(value.DirectoryURL)
+	ACMEEmail = strings.TrimSpace(value.Email)
+	ACMEDomains = domains
+	ACMEChallenge = value.Challenge
+	ACMEHTTPPort = value.HTTPPort
+	ACMEDNSProvider = value.DNSProvider
+	ACMEDNSProviderSettings = value.DNSProviderSettings
+	ACMEDNSPropagationSeconds = value.DNSPropagationSeconds
+	ACMECACertPath = strings.TrimSpace(value.CACertPath)
+	ACMERenewDays = value.RenewDays
+	return safeSaveConfig()
+}
+
 // SetUsers merges the provided key-value pairs into the existing Users map with validation
 func SetUsers(value map[string]string) error {
 	ConfigMu.Lock()
@@ -660,6 +844,18 @@ func SetUsers(value map[string]string) error {
 	return safeSaveConfig()
 }
 
+// DeleteUser removes a user from the users map
+func DeleteUser(username string) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	if _, ok := Users[username]; !ok {
+		return fmt.Errorf("user %s does not exist", username)
+	}
+	delete(Users, username)
+	return safeSaveConfig()
+}
+
 // Update Settings
 func SetIsUpdateEnabled(value bool) error {
 	ConfigMu.Lock()
@@ -709,6 +905,28 @@ func SetAllowAutoGameServerUpdates(value bool) error {
 	return safeSaveConfig()
 }
 
+func SetAutoGameServerUpdateWaitForEmpty(value bool) error {
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	AutoGameServerUpdateWaitForEmpty = value
+	return safeSaveConfig()
+}
+
+// SetAutoGameServerUpdateWindow sets the HH:MM-HH:MM maintenance window for automatic game updates, empty removes it
+func SetAutoGameServerUpdateWindow(value string) error {
+	value = strings.TrimSpace(value)
+	if _, _, err := ParseMaintenanceWindow(value); err != nil {
+		return err
+	}
+
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	AutoGameServerUpdateWindow = value
+	return safeSaveConfig()
+}
+
 func SetAdvertiserOverride(value string) error {
 	ConfigMu.Lock()
 	defer ConfigMu.Unlock()
@@ -732,3 +950,45 @@ func SetIsStationeersLaunchPadAutoUpdatesEnabled(value bool) error {
 	IsStationeersLaunchPadAutoUpdatesEnabled = value
 	return safeSaveConfig()
 }
+
+// SetArtifactCacheFolder sets the folder installers cache their downloads in
+func SetArtifactCacheFolder(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("artifact cache folder cannot be empty")
+	}
+
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	ArtifactCacheFolder = value
+	return safeSaveConfig()
+}
+
+// SetArtifactMirrorURL sets the mirror installers try before the upstream download, empty for none
+func SetArtifactMirrorURL(value string) error {
+	value = strings.TrimSpace(value)
+	if err := ValidateArtifactMirrorURL(value); err != nil {
+		return err
+	}
+
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	ArtifactMirrorURL = value
+	return safeSaveConfig()
+}
+
+// SetSLPVersion sets the SLP release installs use: "latest", "prerelease" or a release tag
+func SetSLPVersion(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("SLP version cannot be empty, use \"latest\", \"prerelease\" or a release tag")
+	}
+
+	ConfigMu.Lock()
+	defer ConfigMu.Unlock()
+
+	SLPVersion = value
+	return safeSaveConfig()
+}
@@ -52,6 +52,7 @@ var (
 	IsFirstTimeSetup                         bool
 	SSEMessageBufferSize                     = 2000
 	MaxSSEConnections                        = 20
+	SSEScrollbackLines                       = 500
 	GameServerAppID                          = "600760"
 	ExePath                                  string
 	GameBranch                               string
@@ -67,6 +68,9 @@ var (
 	AdvertiserOverride                       string
 	IsStationeersLaunchPadEnabled            bool
 	IsStationeersLaunchPadAutoUpdatesEnabled bool
+	SLPVersion                               string
+	ArtifactCacheFolder                      string
+	ArtifactMirrorURL                        string
 	ShowExpertSettings                       bool
 )
 
@@ -84,20 +88,34 @@ var (
 
 // Discord integration
 var (
-	DiscordToken          string
-	DiscordSession        *discordgo.Session
-	IsDiscordEnabled      bool
-	RotateServerPassword  bool
-	ControlChannelID      string
-	EventLogChannelID     string
-	LogChannelID          string
-	StatusPanelChannelID  string
-	ControlPanelChannelID string
-	DiscordCharBufferSize int
-	ExceptionMessageID    string
-	BlackListFilePath     string
+	DiscordToken              string
+	DiscordSession            *discordgo.Session
+	IsDiscordEnabled          bool
+	RotateServerPassword      bool
+	ControlChannelID          string
+	EventLogChannelID         string
+	LogChannelID              string
+	StatusPanelChannelID      string
+	ControlPanelChannelID     string
+	ChatBridgeChannelID       string
+	DiscordRoutingRules       []DiscordRoutingRule
+	DiscordCharBufferSize     int
+	LogChannelIncludePatterns []string
+	LogChannelExcludePatterns []string
+	LogChannelDigestMinutes   int
+	AuditDiscordMirror        bool
+	ExceptionMessageID        string
+	BlackListFilePath         string
 )
 
+// DiscordRoutingRule sends detection events of the listed types to one or more channels.
+// Channel IDs are global in Discord, so a rule can target channels in any guild the bot is a member of.
+type DiscordRoutingRule struct {
+	EventTypes    []string `json:"eventTypes"`              // e.g. "SERVER_ERROR", a custom detection event type, or "*" for all events
+	ChannelIDs    []string `json:"channelIDs"`              // channels the matching events are posted to
+	MentionRoleID string   `json:"mentionRoleID,omitempty"` // optional role to ping, e.g. @admins on SERVER_ERROR
+}
+
 // Backup and cleanup settings
 var (
 	IsCleanupEnabled          bool
@@ -114,19 +132,73 @@ var (
 
 // Authentication and security
 var (
-	AuthEnabled       bool
-	JwtKey            string
-	AuthTokenLifetime int
-	Users             map[string]string
-	SSUIWebPort       string
+	AuthEnabled        bool
+	JwtKey             string
+	AuthTokenLifetime  int
+	Users              map[string]string
+	SSUIWebPort        string
+	SSUIWebBindAddress string
+	SSUIWebPlainHTTP   bool
+	SSUIWebBasePath    string
+	TrustedProxies     []string
+	OIDCEnabled        bool
+	OIDCIssuerURL      string
+	OIDCClientID       string
+	OIDCClientSecret   string
+	OIDCRedirectURL    string
+	OIDCScopes         []string
+	OIDCUsernameClaim  string
+	OIDCGroupsClaim    string
+	OIDCGroupMappings  []OIDCGroupMapping
+)
+
+// ACME certificate settings
+
+var (
+	ACMEEnabled               bool
+	ACMEDirectoryURL          string
+	ACMEEmail                 string
+	ACMEDomains               []string
+	ACMEChallenge             string
+	ACMEHTTPPort              string
+	ACMEDNSProvider           string
+	ACMEDNSProviderSettings   map[string]string
+	ACMEDNSPropagationSeconds int
+	ACMECACertPath            string
+	ACMERenewDays             int
 )
 
+// ACMESettings is a snapshot of the ACME certificate settings
+type ACMESettings struct {
+	Enabled               bool              `json:"enabled"`
+	DirectoryURL          string            `json:"directoryURL"`
+	Email                 string            `json:"email"`
+	Domains               []string          `json:"domains"`
+	Challenge             string            `json:"challenge"`
+	HTTPPort              string            `json:"httpPort"`
+	DNSProvider           string            `json:"dnsProvider"`
+	DNSProviderSettings   map[string]string `json:"dnsProviderSettings"`
+	DNSPropagationSeconds int               `json:"dnsPropagationSeconds"`
+	CACertPath            string            `json:"caCertPath"`
+	RenewDays             int               `json:"renewDays"`
+}
+
+// OIDCGroupMapping lets members of an identity provider group log in.
+// With Username set, members log in as that SSUI user (never "recovery"), otherwise as "oidc-" + their OIDCUsernameClaim.
+type OIDCGroupMapping struct {
+	Group    string `json:"group"`
+	Username string `json:"username,omitempty"`
+	Role     string `json:"role,omitempty"` // read, control or admin (default), same meaning as API key scopes
+}
+
 // SSUI Updates and Game Server Updates
 var (
-	IsUpdateEnabled            bool
-	AllowPrereleaseUpdates     bool
-	AllowMajorUpdates          bool
-	AllowAutoGameServerUpdates bool
+	IsUpdateEnabled                  bool
+	AllowPrereleaseUpdates           bool
+	AllowMajorUpdates                bool
+	AllowAutoGameServerUpdates       bool
+	AutoGameServerUpdateWaitForEmpty bool
+	AutoGameServerUpdateWindow       string
 )
 
 // SSCM (Stationeers Server Command Manager) settings
@@ -139,8 +211,19 @@ var (
 var (
 	TLSCertPath                   = "./UIMod/tls/cert.pem"
 	TLSKeyPath                    = "./UIMod/tls/key.pem"
+	ACMEAccountKeyPath            = "./UIMod/tls/acme-account.key"
 	ConfigPath                    = "./UIMod/config/config.json"
 	CustomDetectionsFilePath      = "./UIMod/config/customdetections.json"
+	APIKeysFilePath               = "./UIMod/config/apikeys.json"
+	SessionsFilePath              = "./UIMod/config/sessions.json"
+	TwoFactorFilePath             = "./UIMod/config/twofactor.json"
+	AuditLogFilePath              = "./UIMod/config/audit.jsonl"
+	InstallHistoryFilePath        = "./UIMod/config/installhistory.json"
+	InstallSnapshotsFolder        = "./UIMod/installsnapshots/"
+	SLPPreviousFolder             = "./UIMod/slpprevious/" // the SLP install replaced by the last update, outside BepInEx/plugins so it is not loaded
+	ModManifestFilePath           = "./UIMod/config/modmanifest.json"
+	ModCompatFilePath             = "./UIMod/config/modcompat.json"
+	SLPInstallFilePath            = "./UIMod/config/slpinstall.json"
 	LogFolder                     = "./UIMod/logs/"
 	UIModFolder                   = "./UIMod/"
 	TwoBoxFormFolder              = "./UIMod/twoboxform/"
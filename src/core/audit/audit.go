@@ -0,0 +1,231 @@
+// Package audit keeps an append-only record of who changed what, across the web UI, API keys, Discord and the CLI.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+/*
+Audit log
+- Every state-changing operation is appended as one JSON line to AuditLogFilePath, entries are never rewritten or deleted by SSUI
+- Record never fails the operation it describes; write errors are logged
+- With AuditDiscordMirror enabled, entries are also sent to Discord as "AUDIT" events (see discordbot.SendEventMessage routing)
+*/
+
+// Actor types
+const (
+	ActorWeb     = "web"     // logged in web UI user, or "anonymous" with auth disabled
+	ActorAPIKey  = "apikey"  // named API key
+	ActorDiscord = "discord" // Discord user
+	ActorCLI     = "cli"     // SSUICLI runtime console
+	ActorSystem  = "system"  // SSUI itself, e.g. scheduled tasks
+)
+
+// Results
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Entry is one audit record
+type Entry struct {
+	Time      time.Time      `json:"time"`
+	ActorType string         `json:"actorType"`
+	Actor     string         `json:"actor"`
+	IP        string         `json:"ip,omitempty"`
+	Action    string         `json:"action"`
+	Params    map[string]any `json:"params,omitempty"`
+	Result    string         `json:"result"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// Filter selects entries in Query. Zero values match everything.
+type Filter struct {
+	ActorType string
+	Actor     string // case-insensitive substring
+	Action    string // case-insensitive substring
+	Result    string
+	Since     time.Time
+	Until     time.Time
+	Limit     int // newest entries first, 0 for no limit
+}
+
+var (
+	fileMu     sync.Mutex
+	mirrorMu   sync.Mutex
+	mirrorFunc func(Entry)
+)
+
+// RegisterMirror lets the Discord bot receive entries without this package importing it (import cycles)
+func RegisterMirror(fn func(Entry)) {
+	mirrorMu.Lock()
+	mirrorFunc = fn
+	mirrorMu.Unlock()
+}
+
+// Record appends an entry to the audit log. Time and Result are filled in if empty.
+func Record(e Entry) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.Result == "" {
+		e.Result = ResultSuccess
+	}
+	e.Params = redact(e.Params)
+
+	if err := appendEntry(e); err != nil {
+		logger.Security.Error("Failed to write audit log: " + err.Error())
+	}
+	logger.Security.Debugf("Audit: %s %s %s -> %s", e.ActorType, e.Actor, e.Action, e.Result)
+
+	if !config.GetAuditDiscordMirror() {
+		return
+	}
+	mirrorMu.Lock()
+	fn := mirrorFunc
+	mirrorMu.Unlock()
+	if fn != nil {
+		go fn(e)
+	}
+}
+
+// RecordError is Record with the result set from err
+func RecordError(e Entry, err error) {
+	if err != nil {
+		e.Result, e.Error = ResultError, err.Error()
+	}
+	Record(e)
+}
+
+func appendEntry(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	path := config.GetAuditLogFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Query returns the entries matching the filter, newest first
+func Query(filter Filter) ([]Entry, error) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	entries := []Entry{}
+	f, err := os.Open(config.GetAuditLogFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // a torn line from a crash must not hide the rest of the log
+		}
+		if filter.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	slices.Reverse(entries)
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
+
+func (f Filter) matches(e Entry) bool {
+	switch {
+	case f.ActorType != "" && !strings.EqualFold(e.ActorType, f.ActorType):
+		return false
+	case f.Actor != "" && !strings.Contains(strings.ToLower(e.Actor), strings.ToLower(f.Actor)):
+		return false
+	case f.Action != "" && !strings.Contains(strings.ToLower(e.Action), strings.ToLower(f.Action)):
+		return false
+	case f.Result != "" && !strings.EqualFold(e.Result, f.Result):
+		return false
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && e.Time.After(f.Until):
+		return false
+	}
+	return true
+}
+
+// sensitiveParamWords mark parameters whose values must never end up in the audit log
+var sensitiveParamWords = []string{"password", "secret", "token", "jwtkey", "code"}
+
+// redact masks credentials in params, including nested objects
+func redact(params map[string]any) map[string]any {
+	if len(params) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(params))
+	for key, value := range params {
+		lower := strings.ToLower(key)
+		if slices.ContainsFunc(sensitiveParamWords, func(word string) bool { return strings.Contains(lower, word) }) {
+			out[key] = "***"
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			value = redact(nested)
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// Summary is a one-line, human readable form of an entry, e.g. for Discord
+func (e Entry) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: %s", e.ActorType, e.Actor, e.Action)
+	if len(e.Params) > 0 {
+		if params, err := json.Marshal(e.Params); err == nil {
+			text := string(params)
+			if len(text) > 300 {
+				text = text[:300] + "…"
+			}
+			b.WriteString(" " + text)
+		}
+	}
+	if e.Result != ResultSuccess {
+		fmt.Fprintf(&b, " -> %s", e.Result)
+		if e.Error != "" {
+			b.WriteString(": " + e.Error)
+		}
+	}
+	return b.String()
+}
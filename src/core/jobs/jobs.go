@@ -0,0 +1,314 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+/*
+Background jobs for long-running operations (SteamCMD, workshop downloads, SLP installs, mod imports, restores, ...)
+- Every job runs in a lane. A lane runs one job at a time, later jobs wait in the lane's queue in order.
+  Operations that must not overlap, like everything driving SteamCMD, share a lane.
+- A job reports progress and log lines while it runs and keeps its result when done
+- Cancel stops a queued job right away and cancels the context of a running one
+- The last maxHistory finished jobs are kept for /api/v2/jobs and the CLI dashboard
+*/
+
+// State of a job
+type State string
+
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCancelled State = "cancelled"
+)
+
+// Lanes, jobs in the same lane never run at the same time
+const (
+	LaneSteamCMD = "steamcmd" // game updates and workshop downloads
+	LaneInstall  = "install"  // branch switches and rollbacks; a switch queues its SteamCMD run in LaneSteamCMD, both refuse to copy files while SteamCMD runs
+	LaneModding  = "modding"  // SLP and mod package installs
+	LaneBackup   = "backup"   // backup restores
+)
+
+const (
+	maxHistory = 100 // finished jobs kept
+	maxLogs    = 500 // log lines kept per job
+)
+
+// RunFunc does the work of a job. It should return soon after ctx is cancelled, the result is kept with the job.
+type RunFunc func(ctx context.Context, job *Job) (any, error)
+
+// Job is a long-running operation. Read it through Get, List or Wait, which return copies.
+type Job struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"` // e.g. "steamcmd-update", "workshop-update", "slp-install"
+	Lane          string    `json:"lane"`
+	Description   string    `json:"description"`
+	State         State     `json:"state"`
+	QueuePosition int       `json:"queuePosition,omitempty"` // jobs ahead in the lane while queued
+	Progress      float64   `json:"progress"`                // percent, 0 if the job cannot tell
+	ProgressText  string    `json:"progressText,omitempty"`
+	Logs          []string  `json:"logs,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	Result        any       `json:"result,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	StartedAt     time.Time `json:"startedAt,omitzero"`
+	FinishedAt    time.Time `json:"finishedAt,omitzero"`
+
+	run    RunFunc
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Accepted is the 202 response of an endpoint that submitted a job
+type Accepted struct {
+	Status        string `json:"status"` // "accepted" if the job started, "queued" if it waits behind other jobs
+	Message       string `json:"message"`
+	JobID         string `json:"jobId"`
+	State         State  `json:"state"`
+	QueuePosition int    `json:"queuePosition,omitempty"`
+}
+
+// NewAccepted describes a submitted job, follow it on /api/v2/jobs?id=
+func NewAccepted(job Job, message string) Accepted {
+	accepted := Accepted{Status: "accepted", Message: message, JobID: job.ID, State: job.State}
+	if job.State == StateQueued {
+		accepted.Status, accepted.QueuePosition = "queued", job.QueuePosition
+		accepted.Message = fmt.Sprintf("%s, queued behind %d other job(s)", message, job.QueuePosition)
+	}
+	return accepted
+}
+
+// ErrNotFound is returned for unknown job IDs
+var ErrNotFound = errors.New("job not found")
+
+var (
+	mu    sync.Mutex
+	all   []*Job                // every known job, oldest first
+	lanes = map[string][]*Job{} // running job first, then the queue
+)
+
+// Submit queues a job and returns a copy of it, its state tells whether it started right away or is queued
+func Submit(jobType, lane, description string, run RunFunc) Job {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &Job{
+		ID:          uuid.NewString(),
+		Type:        jobType,
+		Lane:        lane,
+		Description: description,
+		State:       StateQueued,
+		CreatedAt:   time.Now(),
+		run:         run,
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	all = append(all, job)
+	lanes[lane] = append(lanes[lane], job)
+	if len(lanes[lane]) == 1 {
+		start(job)
+	} else {
+		job.QueuePosition = len(lanes[lane]) - 1
+		logger.Core.Infof("🕑 Job %s (%s) queued behind %d other job(s)", job.ID, description, job.QueuePosition)
+	}
+	prune()
+	return job.copy()
+}
+
+// Run submits a job and waits for it, for callers that need the outcome before they go on
+func Run(jobType, lane, description string, run RunFunc) (any, error) {
+	job, _ := Wait(Submit(jobType, lane, description, run).ID)
+	if job.State != StateSucceeded {
+		return job.Result, errors.New(job.Error)
+	}
+	return job.Result, nil
+}
+
+// Get returns a copy of the job
+func Get(id string) (Job, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if job := find(id); job != nil {
+		return job.copy(), true
+	}
+	return Job{}, false
+}
+
+// List returns copies of all jobs, newest first, without their logs
+func List() []Job {
+	mu.Lock()
+	defer mu.Unlock()
+	list := make([]Job, 0, len(all))
+	for _, job := range slices.Backward(all) {
+		c := job.copy()
+		c.Logs = nil
+		list = append(list, c)
+	}
+	return list
+}
+
+// Wait blocks until the job is finished and returns it
+func Wait(id string) (Job, bool) {
+	mu.Lock()
+	job := find(id)
+	mu.Unlock()
+	if job == nil {
+		return Job{}, false
+	}
+	<-job.done
+	return Get(id)
+}
+
+// Cancel cancels a queued or running job
+func Cancel(id string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	job := find(id)
+	switch {
+	case job == nil:
+		return ErrNotFound
+	case job.State == StateQueued:
+		lanes[job.Lane] = slices.DeleteFunc(lanes[job.Lane], func(j *Job) bool { return j == job })
+		finish(job, StateCancelled, nil, context.Canceled)
+		updateQueuePositions(job.Lane)
+	case job.State == StateRunning:
+		job.cancel() // the job ends itself
+	default:
+		return fmt.Errorf("job %s already %s", id, job.State)
+	}
+	return nil
+}
+
+// Busy reports whether a job is running in the lane
+func Busy(lane string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return len(lanes[lane]) > 0
+}
+
+// SetProgress reports progress in percent with a short description of the current step
+func (job *Job) SetProgress(percent float64, text string) {
+	mu.Lock()
+	defer mu.Unlock()
+	job.Progress, job.ProgressText = percent, text
+}
+
+// Log adds a line to the job's log
+func (job *Job) Log(line string) {
+	mu.Lock()
+	defer mu.Unlock()
+	job.Logs = append(job.Logs, line)
+	if excess := len(job.Logs) - maxLogs; excess > 0 {
+		job.Logs = slices.Delete(job.Logs, 0, excess)
+	}
+}
+
+// start runs a job in the background. Caller must hold mu.
+func start(job *Job) {
+	job.State, job.QueuePosition, job.StartedAt = StateRunning, 0, time.Now()
+	logger.Core.Infof("▶️ Job %s started: %s", job.ID, job.Description)
+	go func() {
+		result, err := runSafely(job)
+		mu.Lock()
+		defer mu.Unlock()
+		state := StateSucceeded
+		if err != nil {
+			state = StateFailed
+			if job.ctx.Err() != nil {
+				state = StateCancelled
+			}
+		}
+		finish(job, state, result, err)
+
+		queue := slices.DeleteFunc(lanes[job.Lane], func(j *Job) bool { return j == job })
+		lanes[job.Lane] = queue
+		if len(queue) > 0 {
+			start(queue[0])
+			updateQueuePositions(job.Lane)
+		}
+	}()
+}
+
+// runSafely keeps a panicking job from taking SSUI down
+func runSafely(job *Job) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return job.run(job.ctx, job)
+}
+
+// finish records the outcome. Caller must hold mu.
+func finish(job *Job, state State, result any, err error) {
+	job.State, job.Result, job.FinishedAt = state, result, time.Now()
+	if err != nil {
+		job.Error = err.Error()
+	}
+	if state == StateSucceeded {
+		job.Progress = 100
+	}
+	job.cancel()
+	close(job.done)
+	logger.Core.Infof("⏹️ Job %s %s: %s", job.ID, state, job.Description)
+}
+
+func updateQueuePositions(lane string) {
+	for i, job := range lanes[lane] {
+		if job.State == StateQueued {
+			job.QueuePosition = i
+		}
+	}
+}
+
+// prune drops the oldest finished jobs beyond maxHistory. Caller must hold mu.
+func prune() {
+	finished := 0
+	for _, job := range all {
+		if job.FinishedAt.IsZero() {
+			continue
+		}
+		finished++
+	}
+	for i := 0; finished > maxHistory && i < len(all); {
+		if all[i].FinishedAt.IsZero() {
+			i++
+			continue
+		}
+		all = slices.Delete(all, i, i+1)
+		finished--
+	}
+}
+
+// find returns the job with the given ID. Caller must hold mu.
+func find(id string) *Job {
+	for _, job := range all {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// copy returns a snapshot of the job that is safe to hand out. Caller must hold mu.
+func (job *Job) copy() Job {
+	c := *job
+	c.Logs = slices.Clone(job.Logs)
+	c.run, c.ctx, c.cancel, c.done = nil, nil, nil, nil
+	return c
+}
@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLaneQueueAndCancel(t *testing.T) {
+	release := make(chan struct{})
+	first := Submit("test", "test-lane", "first", func(ctx context.Context, job *Job) (any, error) {
+		job.SetProgress(50, "waiting")
+		<-release
+		return "done", nil
+	})
+	second := Submit("test", "test-lane", "second", func(ctx context.Context, job *Job) (any, error) {
+		t.Error("cancelled job ran")
+		return nil, nil
+	})
+	third := Submit("test", "test-lane", "third", func(ctx context.Context, job *Job) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if first.State != StateRunning || second.State != StateQueued || second.QueuePosition != 1 || third.QueuePosition != 2 {
+		t.Fatalf("unexpected states: %s, %s (%d), %s (%d)", first.State, second.State, second.QueuePosition, third.State, third.QueuePosition)
+	}
+	if !Busy("test-lane") || Busy("other-lane") {
+		t.Error("Busy does not match the running jobs")
+	}
+
+	if err := Cancel(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job, _ := Get(third.ID); job.QueuePosition != 1 {
+		t.Errorf("third job should have moved up the queue, got position %d", job.QueuePosition)
+	}
+
+	close(release)
+	if job, _ := Wait(first.ID); job.State != StateSucceeded || job.Result != "done" || job.Progress != 100 {
+		t.Errorf("first job: %+v", job)
+	}
+	if job, _ := Wait(second.ID); job.State != StateCancelled || !job.StartedAt.IsZero() {
+		t.Errorf("second job: %+v", job)
+	}
+
+	// the third job runs now and ends when cancelled
+	if err := Cancel(third.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job, _ := Wait(third.ID); job.State != StateCancelled {
+		t.Errorf("third job: %+v", job)
+	}
+	if err := Cancel(third.ID); err == nil {
+		t.Error("cancelling a finished job should fail")
+	}
+	if err := Cancel("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown job: %v", err)
+	}
+}
+
+func TestRunRecoversPanics(t *testing.T) {
+	_, err := Run("test", "panic-lane", "panics", func(ctx context.Context, job *Job) (any, error) {
+		panic("boom")
+	})
+	if err == nil {
+		t.Fatal("expected the panic as error")
+	}
+	if Busy("panic-lane") {
+		t.Error("lane still busy after the job panicked")
+	}
+}
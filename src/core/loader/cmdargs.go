@@ -23,6 +23,7 @@ var devModeFlag bool
 var skipSteamCMDFlag bool
 var sanityCheckFlag bool
 var advertiserOverrideFlag string
+var resetTwoFactorFlag string
 
 // ParseFlags parses command-line arguments ONCE at startup (called from func main)
 func ParseFlags() {
@@ -33,6 +34,7 @@ func ParseFlags() {
 	flag.StringVar(&gameBranchFlag, "b", "", "(Alias) Override the game branch (e.g., beta)")
 	flag.StringVar(&recoveryPasswordFlag, "RecoveryPassword", "", "Adds a 'recovery' user (expects password as argument)")
 	flag.StringVar(&recoveryPasswordFlag, "r", "", "(Alias) Adds a 'recovery' user (expects password as argument)")
+	flag.StringVar(&resetTwoFactorFlag, "ResetTwoFactor", "", "Disables two-factor authentication for a user who lost their authenticator (expects username as argument)")
 	flag.BoolVar(&devModeFlag, "dev", false, "Enable dev mode: Auth, and enables cli-console. For development only.")
 	flag.IntVar(&logLevelFlag, "LogLevel", 0, "Override the log level (e.g., 10)")
 	flag.IntVar(&logLevelFlag, "ll", 0, "(Alias) Override the log level (e.g., 10)")
@@ -91,11 +93,25 @@ func HandleFlags() {
 				logger.Main.Error(fmt.Sprintf("Failed to hash recovery password: %v", err))
 				return
 			}
-			config.SetUsers(map[string]string{"recovery": hashedPassword})
+			config.SetUsers(map[string]string{security.RecoveryUsername: hashedPassword})
+			if _, err := security.RevokeUserSessions(security.RecoveryUsername); err != nil {
+				logger.Main.Warn(fmt.Sprintf("Failed to end previous recovery sessions: %v", err))
+			}
+			if err := security.ResetTwoFactor(security.RecoveryUsername); err != nil {
+				logger.Main.Warn(fmt.Sprintf("Failed to reset two-factor authentication of the recovery user: %v", err))
+			}
 			logger.Main.Warn(fmt.Sprintf("Recovery user added with access level superadmin. Login with username 'recovery' and password '%s'", recoveryPasswordFlag))
 		}
 	}
 
+	if resetTwoFactorFlag = strings.TrimSpace(resetTwoFactorFlag); resetTwoFactorFlag != "" {
+		if err := security.ResetTwoFactor(resetTwoFactorFlag); err != nil {
+			logger.Main.Error(fmt.Sprintf("Failed to reset two-factor authentication of %s: %v", resetTwoFactorFlag, err))
+		} else {
+			logger.Main.Warn(fmt.Sprintf("Two-factor authentication of %s reset from command line", resetTwoFactorFlag))
+		}
+	}
+
 	if logLevelFlag != 0 {
 		oldLevel := config.GetLogLevel()
 		config.SetLogLevel(logLevelFlag)
@@ -82,7 +82,12 @@ func PrintConfigDetails(logLevel ...string) {
 		"StatusPanelChannelID":  config.GetStatusPanelChannelID(),
 		"LogChannelID":          config.GetLogChannelID(),
 		"ControlPanelChannelID": config.GetControlPanelChannelID(),
+		"ChatBridgeChannelID":   config.GetChatBridgeChannelID(),
+		"DiscordRoutingRules":   fmt.Sprintf("%d", len(config.GetDiscordRoutingRules())),
 		"DiscordCharBufferSize": fmt.Sprintf("%d", config.GetDiscordCharBufferSize()),
+		"LogChannelFilters":     fmt.Sprintf("%d include, %d exclude", len(config.GetLogChannelIncludePatterns()), len(config.GetLogChannelExcludePatterns())),
+		"LogChannelDigestMin":   fmt.Sprintf("%d", config.GetLogChannelDigestMinutes()),
+		"AuditDiscordMirror":    fmt.Sprintf("%v", config.GetAuditDiscordMirror()),
 		"BlackListFilePath":     config.GetBlackListFilePath(),
 	}
 	printSection("Discord Configuration", discord)
@@ -98,9 +103,29 @@ func PrintConfigDetails(logLevel ...string) {
 	auth := map[string]string{
 		"AuthEnabled":       fmt.Sprintf("%v", config.GetAuthEnabled()),
 		"AuthTokenLifetime": fmt.Sprintf("%d", config.GetAuthTokenLifetime()),
+		"OIDCEnabled":       fmt.Sprintf("%v", config.GetOIDCEnabled()),
+		"OIDCIssuerURL":     config.GetOIDCIssuerURL(),
 	}
 	printSection("Authentication Configuration", auth)
 
+	// Web Server Configuration
+	web := map[string]string{
+		"SSUIWebBindAddress": config.GetSSUIWebBindAddress(),
+		"SSUIWebPlainHTTP":   fmt.Sprintf("%v", config.GetSSUIWebPlainHTTP()),
+		"SSUIWebBasePath":    config.GetSSUIWebBasePath(),
+		"TrustedProxies":     strings.Join(config.GetTrustedProxies(), ", "),
+	}
+	printSection("Web Server Configuration", web)
+
+	// ACME Configuration
+	acmeSettings := config.GetACMESettings()
+	acme := map[string]string{
+		"ACMEEnabled":   fmt.Sprintf("%v", acmeSettings.Enabled),
+		"ACMEDomains":   strings.Join(acmeSettings.Domains, ", "),
+		"ACMEChallenge": acmeSettings.Challenge,
+	}
+	printSection("ACME Configuration", acme)
+
 	// Logging Configuration
 	logging := map[string]string{
 		"CreateSSUILogFile":   fmt.Sprintf("%v", config.GetCreateSSUILogFile()),
@@ -119,10 +144,28 @@ func PrintConfigDetails(logLevel ...string) {
 		"AutoRestartServerTimer": config.GetAutoRestartServerTimer(),
 		"AutoRestartCountdown":   config.GetAutoRestartCountdown(),
 		"AutoGameServerUpdates":  fmt.Sprintf("%v", config.GetAllowAutoGameServerUpdates()),
+		"UpdateWaitForEmpty":     fmt.Sprintf("%v", config.GetAutoGameServerUpdateWaitForEmpty()),
+		"UpdateWindow":           config.GetAutoGameServerUpdateWindow(),
 		"CurrentBranchBuildID":   config.GetCurrentBranchBuildID(),
 	}
 	printSection("Updater Configuration", updater)
 
+	// SLP Configuration
+	slp := map[string]string{
+		"IsSLPEnabled":     fmt.Sprintf("%v", config.GetIsStationeersLaunchPadEnabled()),
+		"SLPAutoUpdates":   fmt.Sprintf("%v", config.GetIsStationeersLaunchPadAutoUpdatesEnabled()),
+		"SLPVersion":       config.GetSLPVersion(),
+		"SLPInstallRecord": config.GetSLPInstallFilePath(),
+	}
+	printSection("SLP Configuration", slp)
+
+	// Artifact Cache Configuration
+	artifactCache := map[string]string{
+		"ArtifactCacheFolder": config.GetArtifactCacheFolder(),
+		"ArtifactMirrorURL":   config.GetArtifactMirrorURL(),
+	}
+	printSection("Artifact Cache Configuration", artifactCache)
+
 	// SSCM Configuration
 	sscm := map[string]string{
 		"IsSSCMEnabled": fmt.Sprintf("%v", config.GetIsSSCMEnabled()),
@@ -139,6 +182,7 @@ func PrintConfigDetails(logLevel ...string) {
 		"UIModFolder":          config.GetUIModFolder(),
 		"MaxSSEConnections":    fmt.Sprintf("%d", config.GetMaxSSEConnections()),
 		"SSEMessageBufferSize": fmt.Sprintf("%d", config.GetSSEMessageBufferSize()),
+		"SSEScrollbackLines":   fmt.Sprintf("%d", config.GetSSEScrollbackLines()),
 	}
 	printSection("UI Configuration", ui)
 
@@ -3,17 +3,21 @@ package loader
 
 import (
 	"embed"
+	"errors"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/advertiser"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/ssestream"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/discordbot"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/localization"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/backupmgr"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/detectionmgr"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gameupdatemgr"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/update"
@@ -30,9 +34,11 @@ func InitBackend() {
 	ReloadSSCM()
 	ReloadBackupManager()
 	ReloadLocalizer()
+	InitGameUpdateManager()
 	ReloadAppInfoPoller()
 	ReloadDiscordBot()
 	EnsureSLPAutoUpdates()
+	InitSLPStartupCheck()
 	InitDetector()
 	StartIsGameServerRunningCheck()
 	StartUpdateCheckLoop()
@@ -108,6 +114,10 @@ func InitDetector() {
 			gamemgr.SetServerState(gamemgr.ServerStateHostingSession)
 		case detectionmgr.EventSessionRegistered:
 			gamemgr.SetServerState(gamemgr.ServerStateRunning)
+		case detectionmgr.EventServerReady:
+			if version, ok := modding.ConfirmSLPInstall(); ok {
+				logger.Install.Infof("✅ Server reached ready with SLP %s, keeping it", version)
+			}
 		}
 	})
 	go detectionmgr.StreamLogs(detector)
@@ -126,6 +136,11 @@ func StartIsGameServerRunningCheck() {
 	gamemgr.StartIsGameServerRunningCheck()
 }
 
+// InitGameUpdateManager must run before the first AppInfoPoller check so no available update is missed
+func InitGameUpdateManager() {
+	gameupdatemgr.Init()
+}
+
 func ReloadAppInfoPoller() {
 	steamcmd.AppInfoPoller()
 }
@@ -168,6 +183,48 @@ func EnsureSLPAutoUpdates() {
 	}
 }
 
+// InitSLPStartupCheck rolls back an SLP update if the process exits before the first server start with it reached ready.
+// A slow startup (the state turns uncertain after the startup timeout) keeps the check open, ServerReady still confirms it.
+func InitSLPStartupCheck() {
+	gamemgr.AddServerStateListener(func(previous, state gamemgr.ServerState) {
+		switch state {
+		case gamemgr.ServerStateStarting:
+			if version, ok := modding.BeginSLPStartupCheck(); ok {
+				logger.Install.Infof("Checking that the server reaches ready with SLP %s", version)
+			}
+		case gamemgr.ServerStateStopping:
+			modding.CancelSLPStartupCheck() // stopped by hand, not a failed start
+		case gamemgr.ServerStateStopped:
+			switch previous {
+			case gamemgr.ServerStateStarting, gamemgr.ServerStateLoadingMap, gamemgr.ServerStateHostingSession, gamemgr.ServerStateUncertain:
+				go rollbackFailedSLP() // the process exited during startup, a no-op unless a start is being checked
+			}
+		}
+	})
+}
+
+func rollbackFailedSLP() {
+	if gamemgr.InternalIsServerRunning() {
+		return // never swap the SLP folders under a running server
+	}
+	failed, restored, err := modding.FailSLPStartupCheck()
+	if failed == "" && err == nil {
+		return
+	}
+	var message string
+	switch {
+	case errors.Is(err, modding.ErrNoPreviousSLP):
+		message = fmt.Sprintf("⚠️ The server did not reach ready with SLP %s and there is no previous SLP install to roll back to", failed)
+	case err != nil:
+		message = fmt.Sprintf("❌ The server did not reach ready with SLP %s, rolling back failed: %v", failed, err)
+	default:
+		message = fmt.Sprintf("⏪ The server did not reach ready with SLP %s, rolled back to SLP %s. Start the server again to use it", failed, restored)
+	}
+	logger.Install.Warn(message)
+	ssestream.BroadcastDetectionEvent(message)
+	discordbot.SendEventMessage("SLP_ROLLBACK", message)
+}
+
 func SanityCheck() {
 	err := runSanityCheck()
 	if err != nil {
@@ -0,0 +1,390 @@
+package security
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+
+	"golang.org/x/crypto/acme"
+)
+
+/*
+ACME certificates (Let's Encrypt or any RFC 8555 CA)
+- ObtainACMECertificate orders a certificate for config ACMEDomains and installs it with InstallCertificate, the running server picks it up without a restart
+- http-01 challenges are answered by HTTP01ChallengeHandler, which the web server mounts on the plain HTTP challenge port and the main listener
+- dns-01 challenges create TXT records through a DNSProvider (see acmedns.go), required for wildcard domains
+- StartCertificateMonitor renews ACMERenewDays before expiry, reloads certificates replaced on disk and warns about expiring ones
+- For local tests, point ACMEDirectoryURL at Pebble (https://localhost:14000/dir) and ACMECACertPath at Pebble's test root
+*/
+
+// ACME challenge types, as in config ACMEChallenge
+const (
+	ChallengeHTTP01 = "http-01"
+	ChallengeDNS01  = "dns-01"
+)
+
+const (
+	certificateCheckInterval = time.Hour
+	certificateWarnDays      = 14
+	acmeRetryInterval        = 6 * time.Hour
+	acmeOrderTimeout         = 10 * time.Minute
+	acmeChallengePath        = "/.well-known/acme-challenge/"
+)
+
+// ACMEStatus describes the last certificate order
+type ACMEStatus struct {
+	Running     bool      `json:"running"`
+	LastAttempt time.Time `json:"lastAttempt,omitzero"`
+	LastSuccess time.Time `json:"lastSuccess,omitzero"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+var (
+	acmeOrderMu  sync.Mutex // held while an order runs
+	acmeStatusMu sync.Mutex
+	acmeStatus   ACMEStatus
+
+	http01Mu     sync.Mutex
+	http01Tokens = make(map[string]string) // token -> key authorization of pending http-01 challenges
+)
+
+// GetACMEStatus returns the state of the last certificate order
+func GetACMEStatus() ACMEStatus {
+	acmeStatusMu.Lock()
+	defer acmeStatusMu.Unlock()
+	return acmeStatus
+}
+
+// ObtainACMECertificate orders a certificate for the configured domains and installs it. Only one order runs at a time.
+func ObtainACMECertificate(ctx context.Context) error {
+	if !acmeOrderMu.TryLock() {
+		return errors.New("a certificate order is already running")
+	}
+	defer acmeOrderMu.Unlock()
+
+	acmeStatusMu.Lock()
+	acmeStatus.Running, acmeStatus.LastAttempt = true, time.Now()
+	acmeStatusMu.Unlock()
+
+	settings := config.GetACMESettings()
+	logger.Security.Infof("Requesting a certificate for %s from %s using %s", strings.Join(settings.Domains, ", "), settings.DirectoryURL, settings.Challenge)
+	err := obtainACMECertificate(ctx, settings)
+
+	acmeStatusMu.Lock()
+	acmeStatus.Running = false
+	if err != nil {
+		acmeStatus.LastError = err.Error()
+	} else {
+		acmeStatus.LastError, acmeStatus.LastSuccess = "", time.Now()
+	}
+	acmeStatusMu.Unlock()
+
+	if err != nil {
+		logger.Security.Error("Certificate order failed: " + err.Error())
+		return err
+	}
+	logger.Security.Info("Installed a new certificate for " + strings.Join(settings.Domains, ", "))
+	return nil
+}
+
+func obtainACMECertificate(ctx context.Context, settings config.ACMESettings) error {
+	if len(settings.Domains) == 0 {
+		return errors.New("no ACME domains configured")
+	}
+	var provider DNSProvider
+	if settings.Challenge == ChallengeDNS01 {
+		var err error
+		if provider, err = NewDNSProvider(settings.DNSProvider, settings.DNSProviderSettings); err != nil {
+			return err
+		}
+	}
+
+	client, err := newACMEClient(settings)
+	if err != nil {
+		return err
+	}
+	account := &acme.Account{}
+	if settings.Email != "" {
+		account.Contact = []string{"mailto:" + settings.Email}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("failed to register ACME account: %w", err)
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(settings.Domains...))
+	if err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err := completeAuthorization(ctx, client, authzURL, settings, provider); err != nil {
+			return err
+		}
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return fmt.Errorf("order did not become ready: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: settings.Domains[0]},
+		DNSNames: settings.Domains,
+	}, key)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate request: %w", err)
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("failed to finalize order: %w", err)
+	}
+
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return InstallCertificate(certPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+// completeAuthorization proves control of one domain of an order with the configured challenge type
+func completeAuthorization(ctx context.Context, client *acme.Client, authzURL string, settings config.ACMESettings, provider DNSProvider) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil // validated recently, CAs reuse authorizations
+	}
+	domain := authz.Identifier.Value
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == settings.Challenge {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("the CA offers no %s challenge for %s", settings.Challenge, domain)
+	}
+
+	switch challenge.Type {
+	case ChallengeHTTP01:
+		keyAuth, err := client.HTTP01ChallengeResponse(challenge.Token)
+		if err != nil {
+			return err
+		}
+		http01Mu.Lock()
+		http01Tokens[challenge.Token] = keyAuth
+		http01Mu.Unlock()
+		defer func() {
+			http01Mu.Lock()
+			delete(http01Tokens, challenge.Token)
+			http01Mu.Unlock()
+		}()
+	case ChallengeDNS01:
+		value, err := client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return err
+		}
+		fqdn := "_acme-challenge." + domain + "."
+		if err := provider.Present(ctx, fqdn, value); err != nil {
+			return fmt.Errorf("failed to create TXT record %s: %w", fqdn, err)
+		}
+		defer func() {
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			if err := provider.CleanUp(cleanupCtx, fqdn, value); err != nil {
+				logger.Security.Warn("Failed to remove TXT record " + fqdn + ": " + err.Error())
+			}
+		}()
+		select {
+		case <-time.After(time.Duration(settings.DNSPropagationSeconds) * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("failed to accept %s challenge for %s: %w", challenge.Type, domain, err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("validation of %s failed: %w", domain, err)
+	}
+	logger.Security.Debug("ACME authorization of " + domain + " is valid")
+	return nil
+}
+
+// newACMEClient returns a client for the configured directory, trusting ACMECACertPath in addition to the system roots
+func newACMEClient(settings config.ACMESettings) (*acme.Client, error) {
+	key, err := loadACMEAccountKey()
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if settings.CACertPath != "" {
+		rootPEM, err := os.ReadFile(settings.CACertPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ACME CA certificate: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(rootPEM) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", settings.CACertPath)
+		}
+		httpClient.Transport = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: &tls.Config{RootCAs: roots}}
+	}
+	return &acme.Client{
+		Key:          key,
+		DirectoryURL: settings.DirectoryURL,
+		HTTPClient:   httpClient,
+		UserAgent:    "StationeersServerUI/" + config.GetVersion(),
+	}, nil
+}
+
+// loadACMEAccountKey returns the ACME account key, creating it on first use
+func loadACMEAccountKey() (crypto.Signer, error) {
+	path := config.GetACMEAccountKeyPath()
+	if data, err := os.ReadFile(path); err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("ACME account key %s is not PEM encoded", path)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, err
+	}
+	logger.Security.Info("Created a new ACME account key at " + path)
+	return key, nil
+}
+
+// HTTP01ChallengeHandler answers pending http-01 challenges and passes every other request to next
+func HTTP01ChallengeHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.URL.Path, acmeChallengePath)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		http01Mu.Lock()
+		keyAuth, known := http01Tokens[token]
+		http01Mu.Unlock()
+		if !known {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(keyAuth))
+	})
+}
+
+// needsACMECertificate reports whether the served certificate must be replaced: missing, self-signed,
+// not covering every configured domain, or within renewDays of expiry
+func needsACMECertificate(leaf *x509.Certificate, domains []string, renewDays int, now time.Time) bool {
+	if leaf == nil || isSelfSigned(leaf) {
+		return true
+	}
+	for _, domain := range domains {
+		if !slices.Contains(leaf.DNSNames, domain) {
+			return true
+		}
+	}
+	return now.Add(time.Duration(renewDays) * 24 * time.Hour).After(leaf.NotAfter)
+}
+
+// StartCertificateMonitor periodically reloads certificates replaced on disk, renews ACME certificates and
+// warns about expiring ones. notify receives messages that need an admin's attention, e.g. to forward them to Discord.
+func StartCertificateMonitor(notify func(message string)) {
+	go func() {
+		var lastWarning time.Time
+		for {
+			checkCertificate(notify, &lastWarning)
+			time.Sleep(certificateCheckInterval)
+		}
+	}()
+}
+
+func checkCertificate(notify func(message string), lastWarning *time.Time) {
+	reloadChangedCertificate()
+	now := time.Now()
+	leaf := servingLeaf()
+	settings := config.GetACMESettings()
+
+	switch {
+	case settings.Enabled && needsACMECertificate(leaf, settings.Domains, settings.RenewDays, now):
+		status := GetACMEStatus()
+		if status.LastError != "" && now.Sub(status.LastAttempt) < acmeRetryInterval {
+			break // do not hammer the CA, its rate limits are strict
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), acmeOrderTimeout)
+		err := ObtainACMECertificate(ctx)
+		cancel()
+		if err != nil {
+			notify(fmt.Sprintf("🔐 Renewing the HTTPS certificate for %s failed: %v", strings.Join(settings.Domains, ", "), err))
+		}
+		leaf = servingLeaf()
+	case !settings.Enabled && leaf != nil && isSelfSigned(leaf):
+		// EnsureTLSCerts replaces an expiring self-signed certificate
+		if err := EnsureTLSCerts(); err == nil {
+			reloadChangedCertificate()
+		}
+		return
+	}
+
+	if leaf == nil || isSelfSigned(leaf) || now.Sub(*lastWarning) < 24*time.Hour {
+		return
+	}
+	left := leaf.NotAfter.Sub(now)
+	if left > certificateWarnDays*24*time.Hour {
+		return
+	}
+	*lastWarning = now
+	var message string
+	if left <= 0 {
+		message = fmt.Sprintf("⚠️ The HTTPS certificate for %s expired on %s", strings.Join(leaf.DNSNames, ", "), leaf.NotAfter.Format("2006-01-02"))
+	} else {
+		message = fmt.Sprintf("⚠️ The HTTPS certificate for %s expires in %d days (%s)", strings.Join(leaf.DNSNames, ", "), int(left.Hours()/24), leaf.NotAfter.Format("2006-01-02"))
+	}
+	logger.Security.Warn(message)
+	notify(message)
+}
@@ -0,0 +1,161 @@
+package security
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+// testCertificate returns a PEM certificate for dnsNames signed by a throwaway CA, and its key
+func testCertificate(t *testing.T, notAfter time.Time, dnsNames ...string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ca := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "Test CA"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: notAfter, IsCA: true, BasicConstraintsValid: true}
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leaf := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: dnsNames[0]}, DNSNames: dnsNames, NotBefore: time.Now().Add(-time.Hour), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, leaf, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestInstallCertificateHotReload(t *testing.T) {
+	dir := t.TempDir()
+	config.ConfigMu.Lock()
+	oldCert, oldKey := config.TLSCertPath, config.TLSKeyPath
+	config.TLSCertPath, config.TLSKeyPath = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	config.ConfigMu.Unlock()
+	t.Cleanup(func() {
+		config.ConfigMu.Lock()
+		config.TLSCertPath, config.TLSKeyPath = oldCert, oldKey
+		config.ConfigMu.Unlock()
+	})
+
+	for _, domain := range []string{"one.example.com", "two.example.com"} {
+		certPEM, keyPEM := testCertificate(t, time.Now().Add(90*24*time.Hour), domain)
+		if err := InstallCertificate(certPEM, keyPEM); err != nil {
+			t.Fatal(err)
+		}
+		served, err := GetServingCertificate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if served.Leaf.DNSNames[0] != domain {
+			t.Fatalf("expected the server to present %s right after installing it, got %v", domain, served.Leaf.DNSNames)
+		}
+	}
+
+	certPEM, _ := testCertificate(t, time.Now().Add(time.Hour), "three.example.com")
+	_, otherKey := testCertificate(t, time.Now().Add(time.Hour), "three.example.com")
+	if err := InstallCertificate(certPEM, otherKey); err == nil {
+		t.Fatal("a certificate with a key that does not match must be rejected")
+	}
+	if served, _ := GetServingCertificate(nil); served.Leaf.DNSNames[0] != "two.example.com" {
+		t.Fatal("a rejected certificate must not replace the served one")
+	}
+}
+
+func TestNeedsACMECertificate(t *testing.T) {
+	now := time.Now()
+	leaf := &x509.Certificate{RawIssuer: []byte("ca"), RawSubject: []byte("leaf"), DNSNames: []string{"ssui.example.com", "*.example.com"}, NotAfter: now.Add(60 * 24 * time.Hour)}
+	selfSigned := &x509.Certificate{RawIssuer: []byte("same"), RawSubject: []byte("same"), DNSNames: []string{"ssui.example.com"}, NotAfter: now.Add(60 * 24 * time.Hour)}
+
+	tests := []struct {
+		name    string
+		leaf    *x509.Certificate
+		domains []string
+		want    bool
+	}{
+		{"no certificate", nil, []string{"ssui.example.com"}, true},
+		{"self-signed", selfSigned, []string{"ssui.example.com"}, true},
+		{"covered and valid", leaf, []string{"ssui.example.com", "*.example.com"}, false},
+		{"domain added", leaf, []string{"ssui.example.com", "games.example.org"}, true},
+	}
+	for _, tt := range tests {
+		if got := needsACMECertificate(tt.leaf, tt.domains, 30, now); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !needsACMECertificate(leaf, []string{"ssui.example.com"}, 61, now) {
+		t.Error("a certificate within the renewal window must be renewed")
+	}
+}
+
+func TestHTTP01ChallengeHandler(t *testing.T) {
+	http01Mu.Lock()
+	http01Tokens["known-token"] = "known-token.thumbprint"
+	http01Mu.Unlock()
+	t.Cleanup(func() {
+		http01Mu.Lock()
+		delete(http01Tokens, "known-token")
+		http01Mu.Unlock()
+	})
+	handler := HTTP01ChallengeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	for path, want := range map[string]int{
+		"/.well-known/acme-challenge/known-token":   http.StatusOK,
+		"/.well-known/acme-challenge/unknown-token": http.StatusNotFound,
+		"/config": http.StatusTeapot,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: got status %d, want %d", path, rec.Code, want)
+		}
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/acme-challenge/known-token", nil))
+	if rec.Body.String() != "known-token.thumbprint" {
+		t.Errorf("unexpected key authorization %q", rec.Body.String())
+	}
+}
+
+func TestWebhookDNSProvider(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		calls = append(calls, r.URL.Path+" "+body["fqdn"]+" "+body["value"])
+	}))
+	defer server.Close()
+
+	provider, err := NewDNSProvider("webhook", map[string]string{"url": server.URL + "/", "token": "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Present(context.Background(), "_acme-challenge.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.CleanUp(context.Background(), "_acme-challenge.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/present _acme-challenge.example.com. abc", "/cleanup _acme-challenge.example.com. abc"}
+	if len(calls) != 2 || calls[0] != want[0] || calls[1] != want[1] {
+		t.Fatalf("unexpected webhook calls %v", calls)
+	}
+
+	if _, err := NewDNSProvider("route53", nil); err == nil {
+		t.Error("unknown providers must be rejected")
+	}
+}
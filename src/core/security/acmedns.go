@@ -0,0 +1,161 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+/*
+DNS providers for ACME dns-01 challenges.
+- A provider creates and removes the _acme-challenge TXT record, configured by name (ACMEDNSProvider) and free-form settings (ACMEDNSProviderSettings)
+- Built in: "exec" runs a script, "webhook" calls an HTTP endpoint. Both follow the conventions of lego's exec and httpreq providers,
+  so existing hook scripts and DNS API bridges work unchanged
+- RegisterDNSProvider adds providers for specific DNS APIs
+*/
+
+// DNSProvider creates and removes the TXT records of dns-01 challenges. fqdn ends with a dot, e.g. "_acme-challenge.example.com."
+type DNSProvider interface {
+	Present(ctx context.Context, fqdn, value string) error
+	CleanUp(ctx context.Context, fqdn, value string) error
+}
+
+// DNSProviderFactory creates a provider from its settings
+type DNSProviderFactory func(settings map[string]string) (DNSProvider, error)
+
+var (
+	dnsProvidersMu sync.RWMutex
+	dnsProviders   = map[string]DNSProviderFactory{
+		"exec":    newExecDNSProvider,
+		"webhook": newWebhookDNSProvider,
+	}
+)
+
+// RegisterDNSProvider makes a DNS provider available under name
+func RegisterDNSProvider(name string, factory DNSProviderFactory) {
+	dnsProvidersMu.Lock()
+	dnsProviders[name] = factory
+	dnsProvidersMu.Unlock()
+}
+
+// DNSProviderNames returns the names of the available DNS providers, sorted
+func DNSProviderNames() []string {
+	dnsProvidersMu.RLock()
+	defer dnsProvidersMu.RUnlock()
+	names := make([]string, 0, len(dnsProviders))
+	for name := range dnsProviders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// NewDNSProvider creates the DNS provider registered under name
+func NewDNSProvider(name string, settings map[string]string) (DNSProvider, error) {
+	dnsProvidersMu.RLock()
+	factory, ok := dnsProviders[name]
+	dnsProvidersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown DNS provider %q, available providers are %s", name, strings.Join(DNSProviderNames(), ", "))
+	}
+	return factory(settings)
+}
+
+// execDNSProvider runs `<command> present|cleanup <fqdn> <value>`
+type execDNSProvider struct {
+	command string
+}
+
+func newExecDNSProvider(settings map[string]string) (DNSProvider, error) {
+	if settings["command"] == "" {
+		return nil, fmt.Errorf("the exec DNS provider needs a \"command\" setting")
+	}
+	return &execDNSProvider{command: settings["command"]}, nil
+}
+
+func (p *execDNSProvider) Present(ctx context.Context, fqdn, value string) error {
+	return p.run(ctx, "present", fqdn, value)
+}
+
+func (p *execDNSProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	return p.run(ctx, "cleanup", fqdn, value)
+}
+
+func (p *execDNSProvider) run(ctx context.Context, action, fqdn, value string) error {
+	output, err := exec.CommandContext(ctx, p.command, action, fqdn, value).CombinedOutput()
+	if len(output) > 0 {
+		logger.Security.Debug("DNS provider script: " + strings.TrimSpace(string(output)))
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", p.command, action, err)
+	}
+	return nil
+}
+
+// webhookDNSProvider POSTs {"fqdn": ..., "value": ...} to <url>/present and <url>/cleanup,
+// with optional basic auth ("username", "password") or a bearer token ("token")
+type webhookDNSProvider struct {
+	url                       string
+	username, password, token string
+	client                    *http.Client
+}
+
+func newWebhookDNSProvider(settings map[string]string) (DNSProvider, error) {
+	url := strings.TrimRight(settings["url"], "/")
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, fmt.Errorf("the webhook DNS provider needs a \"url\" setting starting with https:// or http://")
+	}
+	return &webhookDNSProvider{
+		url:      url,
+		username: settings["username"],
+		password: settings["password"],
+		token:    settings["token"],
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (p *webhookDNSProvider) Present(ctx context.Context, fqdn, value string) error {
+	return p.call(ctx, "present", fqdn, value)
+}
+
+func (p *webhookDNSProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	return p.call(ctx, "cleanup", fqdn, value)
+}
+
+func (p *webhookDNSProvider) call(ctx context.Context, action, fqdn, value string) error {
+	body, err := json.Marshal(map[string]string{"fqdn": fqdn, "value": value})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"/"+action, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch {
+	case p.token != "":
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	case p.username != "":
+		req.SetBasicAuth(p.username, p.password)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook %s returned %s: %s", action, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
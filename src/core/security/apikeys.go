@@ -0,0 +1,317 @@
+// apikeys.go
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+
+	"github.com/google/uuid"
+)
+
+/*
+Named API keys
+- An API key is a long-lived JWT issued for the subject "apikey-<uuid>", its metadata lives in APIKeysFilePath
+- Scopes limit what a key may do: read (GET/HEAD only), control (server, backups, mods...), admin (everything incl. auth and config)
+- Revoked key IDs are kept on a revocation list until the key would have expired anyway; ValidateJWT rejects them
+- Keys minted before named keys existed have no metadata and are treated as legacy admin keys until revoked
+*/
+
+const (
+	APIKeyPrefix = "apikey-"
+
+	ScopeRead    = "read"
+	ScopeControl = "control"
+	ScopeAdmin   = "admin"
+
+	apiKeyLastUsedPersistInterval = time.Minute // lastUsed is tracked in memory and written to disk at most this often per key
+)
+
+// APIKeyScopes lists all valid scopes, from least to most privileged
+var APIKeyScopes = []string{ScopeRead, ScopeControl, ScopeAdmin}
+
+// adminOnlyPaths can only be called with the admin scope, regardless of the HTTP method
+var adminOnlyPaths = []string{
+	"/api/v2/auth/",
+	"/api/v2/saveconfig",
+	"/saveconfigasjson",
+	"/api/v2/loader/",
+	"/api/v2/advertiser/",
+	"/api/v2/tls/",
+	"/api/v2/discord/",
+	"/api/v2/update/",
+	"/api/v2/custom-detections",
+	"/api/v2/audit",
+	"/changeuser",
+	"/setup",
+	"/config",
+}
+
+// controlPaths change the server state, they need the control scope regardless of the HTTP method
+var controlPaths = []string{"/start", "/stop"}
+
+// openReadPaths can be read by every authenticated caller, e.g. for the UI to find out whether it is logged in
+var openReadPaths = []string{"/api/v2/auth/check"}
+
+// APIKey is the metadata of a named API key. The key itself is only returned once, on creation.
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	LastUsed  *time.Time `json:"lastUsed,omitempty"`
+	Legacy    bool       `json:"legacy,omitempty"` // minted before named keys, no metadata stored
+}
+
+// RevokedAPIKey is an entry of the revocation list
+type RevokedAPIKey struct {
+	ID        string    `json:"id"`
+	RevokedAt time.Time `json:"revokedAt"`
+	ExpiresAt time.Time `json:"expiresAt"` // zero for legacy keys, which are then kept on the list forever
+}
+
+type apiKeyFile struct {
+	Keys    []APIKey        `json:"keys"`
+	Revoked []RevokedAPIKey `json:"revoked"`
+}
+
+var (
+	apiKeysMu        sync.Mutex
+	apiKeys          *apiKeyFile // nil until loaded
+	apiKeysPersisted = make(map[string]time.Time)
+)
+
+// loadAPIKeys reads the API key file once. Caller must hold apiKeysMu.
+func loadAPIKeys() *apiKeyFile {
+	if apiKeys != nil {
+		return apiKeys
+	}
+	apiKeys = &apiKeyFile{}
+	data, err := os.ReadFile(config.GetAPIKeysFilePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Security.Error("Failed to read API keys: " + err.Error())
+		}
+		return apiKeys
+	}
+	if err := json.Unmarshal(data, apiKeys); err != nil {
+		logger.Security.Error("Failed to parse API keys: " + err.Error())
+	}
+	// drop revocation entries of keys that expired anyway
+	now := time.Now()
+	apiKeys.Revoked = slices.DeleteFunc(apiKeys.Revoked, func(r RevokedAPIKey) bool {
+		return !r.ExpiresAt.IsZero() && now.After(r.ExpiresAt)
+	})
+	return apiKeys
+}
+
+// saveAPIKeys writes the API key file. Caller must hold apiKeysMu.
+func saveAPIKeys() error {
+	path := config.GetAPIKeysFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create API key directory: %w", err)
+	}
+	data, err := json.MarshalIndent(apiKeys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode API keys: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write API keys: %w", err)
+	}
+	return nil
+}
+
+// NormalizeScopes validates scopes and removes duplicates. An empty list defaults to read.
+func NormalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return []string{ScopeRead}, nil
+	}
+	normalized := []string{}
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(APIKeyScopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q, valid scopes are %s", scope, strings.Join(APIKeyScopes, ", "))
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+// CreateAPIKey mints a named API key and returns its metadata and the key itself
+func CreateAPIKey(name string, scopes []string, durationMonths int) (APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return APIKey{}, "", fmt.Errorf("name is required")
+	}
+	if durationMonths <= 0 {
+		return APIKey{}, "", fmt.Errorf("duration must be positive")
+	}
+	scopes, err := NormalizeScopes(scopes)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	key := APIKey{
+		ID:        APIKeyPrefix + uuid.NewString(),
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().AddDate(0, durationMonths, 0),
+	}
+	token, err := GenerateJWT(key.ID, durationMonths)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+	store := loadAPIKeys()
+	store.Keys = append(store.Keys, key)
+	if err := saveAPIKeys(); err != nil {
+		store.Keys = store.Keys[:len(store.Keys)-1]
+		return APIKey{}, "", err
+	}
+	logger.Security.Infof("API key %s (%s) created with scopes %s, expires %s", key.Name, key.ID, strings.Join(scopes, ","), key.ExpiresAt.Format(time.RFC3339))
+	return key, token, nil
+}
+
+// ListAPIKeys returns all active API keys, including legacy keys found in the users list
+func ListAPIKeys() []APIKey {
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+	store := loadAPIKeys()
+
+	keys := make([]APIKey, 0, len(store.Keys))
+	known := make(map[string]bool)
+	for _, key := range store.Keys {
+		keys = append(keys, key)
+		known[key.ID] = true
+	}
+	for username := range config.GetUsers() {
+		if strings.HasPrefix(username, APIKeyPrefix) && !known[username] && !isRevokedLocked(username) {
+			keys = append(keys, APIKey{ID: username, Name: "legacy key", Scopes: []string{ScopeAdmin}, Legacy: true})
+		}
+	}
+	slices.SortFunc(keys, func(a, b APIKey) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return keys
+}
+
+// RevokeAPIKey puts a key on the revocation list, it stops working immediately
+func RevokeAPIKey(id string) error {
+	if !strings.HasPrefix(id, APIKeyPrefix) {
+		return fmt.Errorf("%q is not an API key ID", id)
+	}
+
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+	store := loadAPIKeys()
+	if isRevokedLocked(id) {
+		return fmt.Errorf("API key %s is already revoked", id)
+	}
+
+	revoked := RevokedAPIKey{ID: id, RevokedAt: time.Now()}
+	n := slices.IndexFunc(store.Keys, func(k APIKey) bool { return k.ID == id })
+	_, isLegacy := config.GetUsers()[id]
+	switch {
+	case n >= 0:
+		revoked.ExpiresAt = store.Keys[n].ExpiresAt
+		store.Keys = slices.Delete(store.Keys, n, n+1)
+	case !isLegacy:
+		return fmt.Errorf("API key %s not found", id)
+	}
+	store.Revoked = append(store.Revoked, revoked)
+	if err := saveAPIKeys(); err != nil {
+		return err
+	}
+
+	if isLegacy {
+		if err := config.DeleteUser(id); err != nil {
+			logger.Security.Warn("Failed to remove legacy API key user " + id + ": " + err.Error())
+		}
+	}
+	logger.Security.Infof("API key %s revoked", id)
+	return nil
+}
+
+// IsAPIKeyRevoked reports whether the key ID is on the revocation list
+func IsAPIKeyRevoked(id string) bool {
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+	loadAPIKeys()
+	return isRevokedLocked(id)
+}
+
+func isRevokedLocked(id string) bool {
+	return slices.ContainsFunc(apiKeys.Revoked, func(r RevokedAPIKey) bool { return r.ID == id })
+}
+
+// apiKeyScopes returns the scopes of a key, legacy keys without metadata have the admin scope
+func apiKeyScopes(id string) []string {
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+	store := loadAPIKeys()
+	if n := slices.IndexFunc(store.Keys, func(k APIKey) bool { return k.ID == id }); n >= 0 {
+		return store.Keys[n].Scopes
+	}
+	return []string{ScopeAdmin}
+}
+
+// APIKeyAllows reports whether the key may perform the request
+func APIKeyAllows(id, method, path string) bool {
+	return scopesAllow(apiKeyScopes(id), method, path)
+}
+
+// RoleAllows reports whether a user session with a role (a scope name, e.g. from an OIDC group mapping) may perform the request
+func RoleAllows(role, method, path string) bool {
+	return scopesAllow([]string{role}, method, path)
+}
+
+func scopesAllow(scopes []string, method, path string) bool {
+	if slices.Contains(scopes, ScopeAdmin) {
+		return true
+	}
+	if (method == http.MethodGet || method == http.MethodHead) && slices.Contains(openReadPaths, path) {
+		return true
+	}
+	for _, prefix := range adminOnlyPaths {
+		if strings.HasPrefix(path, prefix) {
+			return false
+		}
+	}
+	if (method == http.MethodGet || method == http.MethodHead) && !slices.Contains(controlPaths, path) {
+		return slices.Contains(scopes, ScopeRead) || slices.Contains(scopes, ScopeControl)
+	}
+	return slices.Contains(scopes, ScopeControl)
+}
+
+// TouchAPIKey records the use of a key
+func TouchAPIKey(id string) {
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+	store := loadAPIKeys()
+	n := slices.IndexFunc(store.Keys, func(k APIKey) bool { return k.ID == id })
+	if n < 0 {
+		return
+	}
+	now := time.Now()
+	store.Keys[n].LastUsed = &now
+	if now.Sub(apiKeysPersisted[id]) < apiKeyLastUsedPersistInterval {
+		return
+	}
+	apiKeysPersisted[id] = now
+	if err := saveAPIKeys(); err != nil {
+		logger.Security.Warn("Failed to persist API key usage: " + err.Error())
+	}
+}
@@ -0,0 +1,30 @@
+package security
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestScopesAllow(t *testing.T) {
+	tests := []struct {
+		scope, method, path string
+		want                bool
+	}{
+		{ScopeRead, http.MethodGet, "/api/v2/server/status", true},
+		{ScopeRead, http.MethodPost, "/api/v2/server/start", false},
+		{ScopeRead, http.MethodGet, "/start", false},
+		{ScopeRead, http.MethodGet, "/stop", false},
+		{ScopeRead, http.MethodPost, "/stop", false},
+		{ScopeRead, http.MethodGet, "/api/v2/auth/check", true},
+		{ScopeRead, http.MethodGet, "/api/v2/auth/apikeys", false},
+		{ScopeControl, http.MethodPost, "/start", true},
+		{ScopeControl, http.MethodGet, "/stop", true},
+		{ScopeControl, http.MethodPost, "/api/v2/saveconfig", false},
+		{ScopeAdmin, http.MethodPost, "/api/v2/saveconfig", true},
+	}
+	for _, tt := range tests {
+		if got := RoleAllows(tt.scope, tt.method, tt.path); got != tt.want {
+			t.Errorf("%s %s %s: got %v, want %v", tt.scope, tt.method, tt.path, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package security
 //repurposed from a Jacksonthemaster private repo
 
 import (
+	"errors"
 	"strings"
 	"time"
 
@@ -13,6 +14,9 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// RecoveryUsername is the user the -RecoveryPassword flag creates, only someone with access to the host can log in with it
+const RecoveryUsername = "recovery"
+
 // UserCredentials for login JSON
 type UserCredentials struct {
 	Username string `json:"username"`
@@ -22,7 +26,7 @@ type UserCredentials struct {
 // GenerateJWT creates a JWT for a given username
 func GenerateJWT(username string, apikeyduration ...int) (string, error) {
 	expirationTime := time.Now().Add(time.Duration(config.GetAuthTokenLifetime()) * time.Minute)
-	if strings.HasPrefix(username, "apikey-") {
+	if strings.HasPrefix(username, APIKeyPrefix) {
 		durationMonths := 1
 		if len(apikeyduration) > 0 {
 			durationMonths = apikeyduration[0]
@@ -63,14 +67,52 @@ func HashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
-// ValidateJWT checks if a JWT token is valid
+// TokenInfo describes who a validated token was issued for
+type TokenInfo struct {
+	Subject   string // username or API key ID
+	SessionID string // empty for API keys
+	Role      string // role of the session, empty for full access; API keys use their scopes instead
+}
+
+// ValidateJWT checks if a JWT token is valid and was not revoked
 func ValidateJWT(tokenString string) (bool, error) {
+	if _, err := ValidateToken(tokenString); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ValidateToken validates a token like ValidateJWT and returns who it was issued for.
+// API keys must not be on the revocation list, user tokens must belong to an active session.
+func ValidateToken(tokenString string) (TokenInfo, error) {
 	claims := &jwt.MapClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(config.GetJwtKey()), nil
 	})
-	if err != nil || !token.Valid {
-		return false, err
+	if err != nil {
+		return TokenInfo{}, err
 	}
-	return true, nil
+	if !token.Valid {
+		return TokenInfo{}, errors.New("invalid token")
+	}
+
+	var info TokenInfo
+	info.Subject, _ = (*claims)["id"].(string)
+	info.SessionID, _ = (*claims)["sid"].(string)
+
+	if strings.HasPrefix(info.Subject, APIKeyPrefix) {
+		if IsAPIKeyRevoked(info.Subject) {
+			return TokenInfo{}, errors.New("API key has been revoked")
+		}
+		return info, nil
+	}
+	if info.SessionID == "" {
+		return TokenInfo{}, errors.New("session has ended")
+	}
+	role, ok := touchSession(info.SessionID, info.Subject)
+	if !ok {
+		return TokenInfo{}, errors.New("session has ended")
+	}
+	info.Role = role
+	return info, nil
 }
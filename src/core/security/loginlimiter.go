@@ -0,0 +1,228 @@
+// loginlimiter.go
+package security
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Login brute-force protection
+- Failed logins are counted per client IP and per username
+- After loginFreeAttempts failures, every further attempt has to wait an exponentially growing delay
+- After loginLockoutThreshold failures, the IP or username is locked out for loginLockoutDuration
+- A successful login resets the counters of its IP and username
+- BeginLoginAttempt checks the delay and reserves the attempt under one lock: a running attempt counts like a failure
+  until it ends, so parallel attempts cannot all pass the check before the first failure is recorded
+*/
+
+const (
+	loginFreeAttempts        = 3
+	loginBaseDelay           = time.Second
+	loginMaxDelay            = 30 * time.Second
+	loginUserLockoutAttempts = 10
+	loginIPLockoutAttempts   = 20 // higher than per user, several users may share an IP (NAT, proxies)
+	loginLockoutDuration     = 15 * time.Minute
+	loginFailureWindow       = time.Hour // failures older than this are forgotten
+)
+
+type loginFailures struct {
+	count       int
+	lastFailure time.Time
+	lockedUntil time.Time
+	pending     int       // attempts begun but not finished yet
+	lastAttempt time.Time // start of the latest pending attempt
+}
+
+var (
+	loginLimiterMu sync.Mutex
+	ipFailures     = make(map[string]*loginFailures)
+	userFailures   = make(map[string]*loginFailures)
+	lastCleanup    time.Time
+)
+
+// LoginLockout is returned by RecordLoginFailure when a failure locked out an IP or user
+type LoginLockout struct {
+	Key   string // the IP or username
+	IsIP  bool
+	Until time.Time
+}
+
+// LoginRetryAfter returns how long a login from ip for username has to wait, 0 if it may proceed
+func LoginRetryAfter(ip, username string) time.Duration {
+	loginLimiterMu.Lock()
+	defer loginLimiterMu.Unlock()
+	now := time.Now()
+	return max(retryAfter(ipFailures[ip], now), retryAfter(userFailures[username], now))
+}
+
+func retryAfter(f *loginFailures, now time.Time) time.Duration {
+	if f == nil {
+		return 0
+	}
+	if now.Before(f.lockedUntil) {
+		return f.lockedUntil.Sub(now)
+	}
+	count, last := f.count, f.lastFailure
+	if now.Sub(last) > loginFailureWindow {
+		count = 0
+	}
+	if f.pending > 0 {
+		count += f.pending
+		if f.lastAttempt.After(last) {
+			last = f.lastAttempt
+		}
+	}
+	if count < loginFreeAttempts {
+		return 0
+	}
+	delay := min(loginBaseDelay<<min(count-loginFreeAttempts, 10), loginMaxDelay)
+	if wait := last.Add(delay).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// LoginAttempt is a login reserved by BeginLoginAttempt, it must be ended with Failed, Succeeded or End
+type LoginAttempt struct {
+	ip, username string
+	done         bool
+}
+
+// BeginLoginAttempt returns how long a login from ip for username has to wait, or, if it may proceed, reserves the attempt.
+// username may be empty if it is not known yet, e.g. for the second login step.
+func BeginLoginAttempt(ip, username string) (*LoginAttempt, time.Duration) {
+	loginLimiterMu.Lock()
+	defer loginLimiterMu.Unlock()
+	now := time.Now()
+	forgetStaleLoginFailures(now)
+
+	if wait := max(retryAfter(ipFailures[ip], now), retryAfter(userFailures[username], now)); wait > 0 {
+		return nil, wait
+	}
+	reserveAttempt(ipFailures, ip, now)
+	if username != "" {
+		reserveAttempt(userFailures, username, now)
+	}
+	return &LoginAttempt{ip: ip, username: username}, 0
+}
+
+// Failed records the attempt as a failed login of username and returns the lockouts it caused, if any
+func (a *LoginAttempt) Failed(username string) []LoginLockout {
+	if !a.finish() {
+		return nil
+	}
+	return RecordLoginFailure(a.ip, username)
+}
+
+// Succeeded resets the failure counters of the attempt's IP and of username
+func (a *LoginAttempt) Succeeded(username string) {
+	if a.finish() {
+		RecordLoginSuccess(a.ip, username)
+	}
+}
+
+// End releases an attempt that neither failed nor succeeded, e.g. after an internal error. It does nothing after Failed or Succeeded.
+func (a *LoginAttempt) End() {
+	a.finish()
+}
+
+// finish releases the reservation, false if the attempt was already finished
+func (a *LoginAttempt) finish() bool {
+	loginLimiterMu.Lock()
+	defer loginLimiterMu.Unlock()
+	if a.done {
+		return false
+	}
+	a.done = true
+	releaseAttempt(ipFailures[a.ip])
+	if a.username != "" {
+		releaseAttempt(userFailures[a.username])
+	}
+	return true
+}
+
+func reserveAttempt(failures map[string]*loginFailures, key string, now time.Time) {
+	f, ok := failures[key]
+	if !ok {
+		f = &loginFailures{}
+		failures[key] = f
+	}
+	f.pending++
+	f.lastAttempt = now
+}
+
+func releaseAttempt(f *loginFailures) {
+	if f != nil && f.pending > 0 {
+		f.pending--
+	}
+}
+
+// RecordLoginFailure counts a failed login and returns the lockouts it caused, if any
+func RecordLoginFailure(ip, username string) []LoginLockout {
+	loginLimiterMu.Lock()
+	defer loginLimiterMu.Unlock()
+	now := time.Now()
+	forgetStaleLoginFailures(now)
+
+	var lockouts []LoginLockout
+	if until, locked := recordFailure(ipFailures, ip, loginIPLockoutAttempts, now); locked {
+		lockouts = append(lockouts, LoginLockout{Key: ip, IsIP: true, Until: until})
+	}
+	if username != "" {
+		if until, locked := recordFailure(userFailures, username, loginUserLockoutAttempts, now); locked {
+			lockouts = append(lockouts, LoginLockout{Key: username, Until: until})
+		}
+	}
+	return lockouts
+}
+
+func recordFailure(failures map[string]*loginFailures, key string, lockoutAttempts int, now time.Time) (time.Time, bool) {
+	f, ok := failures[key]
+	if !ok {
+		f = &loginFailures{}
+		failures[key] = f
+	}
+	if now.Sub(f.lastFailure) > loginFailureWindow {
+		f.count = 0
+	}
+	f.count++
+	f.lastFailure = now
+	if f.count >= lockoutAttempts && !now.Before(f.lockedUntil) {
+		f.lockedUntil = now.Add(loginLockoutDuration)
+		f.count = 0 // start over once the lockout ends
+		return f.lockedUntil, true
+	}
+	return time.Time{}, false
+}
+
+// RecordLoginSuccess resets the failure counters of an IP and username, attempts still running stay reserved
+func RecordLoginSuccess(ip, username string) {
+	loginLimiterMu.Lock()
+	defer loginLimiterMu.Unlock()
+	resetFailures(ipFailures, ip)
+	resetFailures(userFailures, username)
+}
+
+func resetFailures(failures map[string]*loginFailures, key string) {
+	if f, ok := failures[key]; ok && f.pending > 0 {
+		failures[key] = &loginFailures{pending: f.pending, lastAttempt: f.lastAttempt}
+		return
+	}
+	delete(failures, key)
+}
+
+// forgetStaleLoginFailures drops entries that no longer delay or lock anything, so the maps cannot grow forever. Caller must hold loginLimiterMu.
+func forgetStaleLoginFailures(now time.Time) {
+	if now.Sub(lastCleanup) < time.Minute {
+		return
+	}
+	lastCleanup = now
+	for _, m := range []map[string]*loginFailures{ipFailures, userFailures} {
+		for key, f := range m {
+			if f.pending == 0 && now.Sub(f.lastFailure) > loginFailureWindow && now.After(f.lockedUntil) {
+				delete(m, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package security
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginLimiterLocksOutUser(t *testing.T) {
+	const ip, user = "198.51.100.7", "admin"
+	defer RecordLoginSuccess(ip, user)
+
+	for n := 1; n < loginFreeAttempts; n++ {
+		RecordLoginFailure(ip, user)
+		if wait := LoginRetryAfter(ip, user); wait != 0 {
+			t.Fatalf("attempt %d: expected no delay within the free attempts, got %s", n, wait)
+		}
+	}
+
+	RecordLoginFailure(ip, user)
+	if wait := LoginRetryAfter(ip, user); wait <= 0 || wait > loginBaseDelay {
+		t.Fatalf("expected a delay of up to %s after %d failures, got %s", loginBaseDelay, loginFreeAttempts, wait)
+	}
+
+	var lockouts []LoginLockout
+	for n := loginFreeAttempts; n < loginUserLockoutAttempts; n++ {
+		lockouts = RecordLoginFailure(ip, user)
+	}
+	if len(lockouts) != 1 || lockouts[0].IsIP || lockouts[0].Key != user {
+		t.Fatalf("expected a lockout of user %q, got %+v", user, lockouts)
+	}
+	if wait := LoginRetryAfter("203.0.113.1", user); wait < loginLockoutDuration-time.Minute {
+		t.Fatalf("expected the user to be locked out from any IP, got a delay of %s", wait)
+	}
+
+	RecordLoginSuccess(ip, user)
+	if wait := LoginRetryAfter(ip, user); wait != 0 {
+		t.Fatalf("expected a successful login to reset the counters, got %s", wait)
+	}
+}
+
+func TestLoginAttemptsReserveBeforeTheCheckPasses(t *testing.T) {
+	const ip, user = "198.51.100.8", "parallel"
+	defer RecordLoginSuccess(ip, user)
+
+	// attempts still checking their password count like failures, so parallel requests cannot skip the delay
+	var attempts []*LoginAttempt
+	for n := 0; n < 10; n++ {
+		if attempt, wait := BeginLoginAttempt(ip, user); wait == 0 {
+			attempts = append(attempts, attempt)
+		}
+	}
+	if len(attempts) != loginFreeAttempts {
+		t.Fatalf("expected %d parallel attempts to pass, got %d", loginFreeAttempts, len(attempts))
+	}
+	for _, attempt := range attempts {
+		attempt.Failed(user)
+		attempt.End() // no-op after Failed
+	}
+	if wait := LoginRetryAfter(ip, user); wait <= 0 {
+		t.Fatal("expected a delay after the failed attempts")
+	}
+
+	RecordLoginSuccess(ip, user)
+	attempt, wait := BeginLoginAttempt(ip, user)
+	if wait != 0 {
+		t.Fatalf("expected no delay after a successful login, got %s", wait)
+	}
+	attempt.End()
+	if f := userFailures[user]; f != nil && f.pending != 0 {
+		t.Fatalf("an ended attempt must release its reservation, %d pending", f.pending)
+	}
+}
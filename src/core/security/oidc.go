@@ -0,0 +1,439 @@
+// oidc.go
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+/*
+OpenID Connect login (authorization code flow with PKCE)
+- BeginOIDCLogin returns the identity provider URL to send the browser to, CompleteOIDCLogin exchanges the returned code
+- BeginOIDCLogin also returns the state, the web handlers bind it to the browser with a cookie against login CSRF
+- The ID token is verified against the provider's JWKS (RS*, PS* and ES* keys), issuer, audience, expiry and nonce
+- OIDCGroupMappings decide who may log in: the first mapping matching one of the user's groups wins, no match denies the login
+- Provider users get the subject OIDCUserPrefix + their name, so they never share a local account or its 2FA by accident.
+  A mapping with a Username logs them in as that local account on purpose, the recovery account is never allowed
+- The result is a normal SSUI session, so the AuthToken cookie and AuthMiddleware work as for password logins
+*/
+
+// OIDCUserPrefix namespaces provider usernames that no mapping replaces, local users cannot be registered with it
+const OIDCUserPrefix = "oidc-"
+
+const (
+	OIDCLoginTTL          = 10 * time.Minute // time the user has to log in at the provider
+	oidcDiscoveryTTL      = time.Hour
+	oidcJWKSRefreshMinGap = time.Minute // unknown key IDs trigger a JWKS refresh at most this often
+)
+
+var oidcHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcProvider struct {
+	issuerURL   string // as configured, to notice config changes
+	discovery   oidcDiscovery
+	fetched     time.Time
+	keys        map[string]any // kid -> *rsa.PublicKey or *ecdsa.PublicKey
+	keysFetched time.Time
+}
+
+type oidcPendingLogin struct {
+	verifier    string
+	nonce       string
+	redirectURL string
+	expires     time.Time
+}
+
+// OIDCIdentity is a verified identity provider login, mapped to an SSUI user
+type OIDCIdentity struct {
+	Subject  string   // "sub" claim, stable ID at the provider
+	Username string   // SSUI username the session is created for
+	Role     string   // session role, empty for full access
+	Groups   []string // groups claimed by the provider
+}
+
+var (
+	oidcMu       sync.Mutex
+	oidcCache    *oidcProvider
+	oidcPending  = make(map[string]*oidcPendingLogin)
+	errNoOIDCKey = errors.New("no matching key in the provider's JWKS")
+)
+
+// provider returns the discovery document and keys of the configured issuer, fetching them if needed. Caller must hold oidcMu.
+func provider() (*oidcProvider, error) {
+	issuer := strings.TrimRight(config.GetOIDCIssuerURL(), "/")
+	if issuer == "" {
+		return nil, errors.New("no OIDC issuer URL configured")
+	}
+	if oidcCache != nil && oidcCache.issuerURL == issuer && time.Since(oidcCache.fetched) < oidcDiscoveryTTL {
+		return oidcCache, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := getJSON(issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("OIDC discovery returned issuer %q, expected %q", discovery.Issuer, issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document is missing endpoints")
+	}
+	oidcCache = &oidcProvider{issuerURL: issuer, discovery: discovery, fetched: time.Now()}
+	if err := oidcCache.refreshKeys(); err != nil {
+		return nil, err
+	}
+	return oidcCache, nil
+}
+
+func (p *oidcProvider) refreshKeys() error {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := getJSON(p.discovery.JWKSURI, &set); err != nil {
+		return fmt.Errorf("failed to fetch OIDC signing keys: %w", err)
+	}
+	keys := make(map[string]any)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(k.Kty, k.N, k.E, k.Crv, k.X, k.Y)
+		if err != nil {
+			logger.Security.Debug("Skipping OIDC signing key " + k.Kid + ": " + err.Error())
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	p.keys, p.keysFetched = keys, time.Now()
+	return nil
+}
+
+func parseJWK(kty, n, e, crv, x, y string) (any, error) {
+	switch kty {
+	case "RSA":
+		nb, err := base64.RawURLEncoding.DecodeString(n)
+		if err != nil {
+			return nil, err
+		}
+		eb, err := base64.RawURLEncoding.DecodeString(e)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(nb), E: int(new(big.Int).SetBytes(eb).Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", crv)
+		}
+		xb, err := base64.RawURLEncoding.DecodeString(x)
+		if err != nil {
+			return nil, err
+		}
+		yb, err := base64.RawURLEncoding.DecodeString(y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(xb) > size || len(yb) > size {
+			return nil, errors.New("invalid EC point")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4 // uncompressed
+		copy(point[1+size-len(xb):1+size], xb)
+		copy(point[1+2*size-len(yb):], yb)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", kty)
+	}
+}
+
+// key returns the signing key for a token, refreshing the JWKS once if the key ID is unknown (key rotation). Caller must hold oidcMu.
+func (p *oidcProvider) key(kid string) (any, error) {
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetched) < oidcJWKSRefreshMinGap {
+		return nil, errNoOIDCKey
+	}
+	if err := p.refreshKeys(); err != nil {
+		return nil, err
+	}
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, errNoOIDCKey
+}
+
+func (p *oidcProvider) lookupKey(kid string) (any, bool) {
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func getJSON(target string, v any) error {
+	resp, err := oidcHTTPClient.Get(target)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", target, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+func randomURLString(n int) (string, error) {
+	raw := make([]byte, n)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// BeginOIDCLogin starts a login and returns the authorization URL to redirect the browser to and the state of the login.
+// redirectURL is the callback the provider sends the browser back to, it must be registered at the provider.
+func BeginOIDCLogin(redirectURL string) (authURL, state string, err error) {
+	if !config.GetOIDCEnabled() {
+		return "", "", errors.New("OIDC login is disabled")
+	}
+	state, err = randomURLString(24)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomURLString(24)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomURLString(48)
+	if err != nil {
+		return "", "", err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	oidcMu.Lock()
+	defer oidcMu.Unlock()
+	p, err := provider()
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	for id, pending := range oidcPending {
+		if now.After(pending.expires) {
+			delete(oidcPending, id)
+		}
+	}
+	oidcPending[state] = &oidcPendingLogin{verifier: verifier, nonce: nonce, redirectURL: redirectURL, expires: now.Add(OIDCLoginTTL)}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {config.GetOIDCClientID()},
+		"redirect_uri":          {redirectURL},
+		"scope":                 {strings.Join(config.GetOIDCScopes(), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(p.discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return p.discovery.AuthorizationEndpoint + separator + query.Encode(), state, nil
+}
+
+// CompleteOIDCLogin exchanges the authorization code of the callback for an ID token, verifies it and maps the user.
+func CompleteOIDCLogin(state, code string) (OIDCIdentity, error) {
+	oidcMu.Lock()
+	pending, ok := oidcPending[state]
+	delete(oidcPending, state)
+	oidcMu.Unlock()
+	if !ok || time.Now().After(pending.expires) {
+		return OIDCIdentity{}, errors.New("login expired or was already used, please try again")
+	}
+
+	oidcMu.Lock()
+	p, err := provider()
+	oidcMu.Unlock()
+	if err != nil {
+		return OIDCIdentity{}, err
+	}
+
+	idToken, err := exchangeOIDCCode(p.discovery.TokenEndpoint, code, pending)
+	if err != nil {
+		return OIDCIdentity{}, err
+	}
+	claims, err := verifyIDToken(p.discovery.Issuer, idToken, pending.nonce)
+	if err != nil {
+		return OIDCIdentity{}, err
+	}
+
+	identity := OIDCIdentity{Groups: claimStrings(claims[config.GetOIDCGroupsClaim()])}
+	identity.Subject, _ = claims["sub"].(string)
+	username, _ := claims[config.GetOIDCUsernameClaim()].(string)
+	if username == "" {
+		username = identity.Subject
+	}
+
+	mappedUser, role, ok := resolveOIDCMapping(username, identity.Groups, config.GetOIDCGroupMappings())
+	if !ok {
+		logger.Security.Warnf("OIDC login of %q denied, none of the groups %v is mapped", username, identity.Groups)
+		return OIDCIdentity{}, fmt.Errorf("%s is not a member of any group allowed to log in", username)
+	}
+	identity.Username, identity.Role = mappedUser, role
+	return identity, nil
+}
+
+func exchangeOIDCCode(tokenEndpoint, code string, pending *oidcPendingLogin) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {pending.redirectURL},
+		"client_id":     {config.GetOIDCClientID()},
+		"code_verifier": {pending.verifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if secret := config.GetOIDCClientSecret(); secret != "" {
+		req.SetBasicAuth(url.QueryEscape(config.GetOIDCClientID()), url.QueryEscape(secret))
+	}
+
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("OIDC token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("OIDC token response is invalid: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("OIDC token request rejected: %s %s", body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("OIDC token response contains no ID token, is the openid scope requested?")
+	}
+	return body.IDToken, nil
+}
+
+func verifyIDToken(issuer, idToken, nonce string) (jwt.MapClaims, error) {
+	clientID := config.GetOIDCClientID()
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		oidcMu.Lock()
+		defer oidcMu.Unlock()
+		p, err := provider()
+		if err != nil {
+			return nil, err
+		}
+		return p.key(kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, errors.New("invalid ID token: nonce mismatch")
+	}
+	if azp, ok := claims["azp"].(string); ok && azp != clientID {
+		return nil, errors.New("invalid ID token: issued to another client")
+	}
+	return claims, nil
+}
+
+// claimStrings returns a claim that is a string or a list of strings as a list
+func claimStrings(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// resolveOIDCMapping returns the SSUI username and role for a provider user, ok is false if no mapping matches
+func resolveOIDCMapping(username string, groups []string, mappings []config.OIDCGroupMapping) (string, string, bool) {
+	for _, mapping := range mappings {
+		if !slices.Contains(groups, mapping.Group) {
+			continue
+		}
+		switch {
+		case mapping.Username != "":
+			username = mapping.Username
+		case username != "":
+			username = OIDCUserPrefix + username
+		}
+		role := mapping.Role
+		if role == ScopeAdmin {
+			role = "" // full access, like a password login
+		}
+		// API key subjects skip the session check, a provider user must never be mistaken for one,
+		// and the recovery account may reset everyone's 2FA, so it is only for logins on the host
+		return username, role, username != "" && !strings.HasPrefix(username, APIKeyPrefix) && username != RecoveryUsername
+	}
+	return "", "", false
+}
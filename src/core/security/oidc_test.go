@@ -0,0 +1,202 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockIdP is a minimal OpenID provider: discovery, JWKS and a token endpoint that checks PKCE
+type mockIdP struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims // extra claims of issued ID tokens
+
+	mu         sync.Mutex
+	challenges map[string][2]string // code -> code_challenge, nonce
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key, challenges: make(map[string][2]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "test-key", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		idp.mu.Lock()
+		pending, ok := idp.challenges[r.PostForm.Get("code")]
+		idp.mu.Unlock()
+		verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(verifier[:]) != pending[0] {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss":   idp.URL,
+			"aud":   r.PostForm.Get("client_id"),
+			"sub":   "user-1234",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": pending[1],
+		}
+		for k, v := range idp.claims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test-key"
+		signed, _ := token.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// authorize plays the browser and the provider's login page: it returns the state and an authorization code
+func (idp *mockIdP) authorize(t *testing.T, authURL string) (state, code string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "ssui" {
+		t.Fatalf("unexpected authorization request %s", authURL)
+	}
+	code = "code-" + q.Get("state")
+	idp.mu.Lock()
+	idp.challenges[code] = [2]string{q.Get("code_challenge"), q.Get("nonce")}
+	idp.mu.Unlock()
+	return q.Get("state"), code
+}
+
+func setupOIDCConfig(t *testing.T, issuer string, mappings []config.OIDCGroupMapping) {
+	t.Helper()
+	config.ConfigMu.Lock()
+	config.OIDCEnabled, config.OIDCIssuerURL, config.OIDCClientID = true, issuer, "ssui"
+	config.OIDCScopes = []string{"openid", "groups"}
+	config.OIDCUsernameClaim, config.OIDCGroupsClaim = "preferred_username", "groups"
+	config.OIDCGroupMappings = mappings
+	config.ConfigMu.Unlock()
+	t.Cleanup(func() {
+		config.ConfigMu.Lock()
+		config.OIDCEnabled, config.OIDCIssuerURL, config.OIDCGroupMappings = false, "", nil
+		config.ConfigMu.Unlock()
+	})
+}
+
+func TestOIDCLoginWithMockIdP(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = jwt.MapClaims{"preferred_username": "alice", "groups": []string{"players", "ssui-mods"}}
+	setupOIDCConfig(t, idp.URL, []config.OIDCGroupMapping{
+		{Group: "ssui-admins", Username: "admin"},
+		{Group: "ssui-mods", Role: ScopeControl},
+	})
+
+	authURL, _, err := BeginOIDCLogin("https://ssui.example/auth/oidc/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := idp.authorize(t, authURL)
+
+	identity, err := CompleteOIDCLogin(state, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Username != OIDCUserPrefix+"alice" || identity.Role != ScopeControl || identity.Subject != "user-1234" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+
+	if _, err := CompleteOIDCLogin(state, code); err == nil {
+		t.Fatal("a state must only be usable once")
+	}
+}
+
+func TestOIDCLoginDeniedWithoutMappedGroup(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = jwt.MapClaims{"preferred_username": "mallory", "groups": []string{"players"}}
+	setupOIDCConfig(t, idp.URL, []config.OIDCGroupMapping{{Group: "ssui-admins", Username: "admin"}})
+
+	authURL, _, err := BeginOIDCLogin("https://ssui.example/auth/oidc/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := idp.authorize(t, authURL)
+	if identity, err := CompleteOIDCLogin(state, code); err == nil {
+		t.Fatalf("login without a mapped group must be denied, got %+v", identity)
+	}
+}
+
+func TestOIDCLoginRejectsWrongVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	idp.claims = jwt.MapClaims{"preferred_username": "alice", "groups": []string{"ssui-admins"}}
+	setupOIDCConfig(t, idp.URL, []config.OIDCGroupMapping{{Group: "ssui-admins"}})
+
+	authURL, _, err := BeginOIDCLogin("https://ssui.example/auth/oidc/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := idp.authorize(t, authURL)
+	idp.mu.Lock()
+	idp.challenges[code] = [2]string{"not-the-challenge", ""}
+	idp.mu.Unlock()
+	if _, err := CompleteOIDCLogin(state, code); err == nil {
+		t.Fatal("token exchange with a wrong PKCE verifier must fail")
+	}
+}
+
+func TestResolveOIDCMapping(t *testing.T) {
+	mappings := []config.OIDCGroupMapping{
+		{Group: "ssui-admins", Username: "admin", Role: ScopeAdmin},
+		{Group: "viewers", Role: ScopeRead},
+		{Group: "bots", Username: APIKeyPrefix + "1234"},
+		{Group: "helpdesk", Username: RecoveryUsername},
+	}
+	if user, role, ok := resolveOIDCMapping("bob", []string{"viewers", "ssui-admins"}, mappings); !ok || user != "admin" || role != "" {
+		t.Errorf("first matching mapping should win, got %q %q %v", user, role, ok)
+	}
+	if user, role, ok := resolveOIDCMapping("bob", []string{"viewers"}, mappings); !ok || user != OIDCUserPrefix+"bob" || role != ScopeRead {
+		t.Errorf("expected oidc-bob with read role, got %q %q %v", user, role, ok)
+	}
+	if _, _, ok := resolveOIDCMapping("bot", []string{"bots"}, mappings); ok {
+		t.Error("mapping to an API key subject must be refused")
+	}
+	if _, _, ok := resolveOIDCMapping("carol", []string{"helpdesk"}, mappings); ok {
+		t.Error("mapping to the recovery account must be refused")
+	}
+	// provider users named like local accounts do not get their sessions
+	if user, _, _ := resolveOIDCMapping(RecoveryUsername, []string{"viewers"}, mappings); user != OIDCUserPrefix+RecoveryUsername {
+		t.Errorf("unmapped provider users must be prefixed, got %q", user)
+	}
+}
@@ -0,0 +1,191 @@
+// sessions.go
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+/*
+Server-side session table
+- Every login creates a session, its ID is the "sid" claim of the user's JWT
+- ValidateJWT only accepts user tokens whose session still exists, so sessions can be ended before AuthTokenLifetime runs out
+- Sessions are persisted to SessionsFilePath so a backend restart does not log everyone out
+*/
+
+const sessionLastSeenPersistInterval = 5 * time.Minute
+
+// Session is a logged in browser or client of a user
+type Session struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	Role      string    `json:"role,omitempty"` // limits the session like an API key scope, empty for full access
+}
+
+var (
+	sessionsMu        sync.Mutex
+	sessions          map[string]*Session // nil until loaded
+	sessionsPersisted time.Time
+)
+
+// loadSessions reads the session table once and drops expired sessions. Caller must hold sessionsMu.
+func loadSessions() map[string]*Session {
+	if sessions == nil {
+		sessions = make(map[string]*Session)
+		data, err := os.ReadFile(config.GetSessionsFilePath())
+		if err == nil {
+			var list []*Session
+			if err := json.Unmarshal(data, &list); err != nil {
+				logger.Security.Error("Failed to parse sessions: " + err.Error())
+			}
+			for _, s := range list {
+				sessions[s.ID] = s
+			}
+		} else if !os.IsNotExist(err) {
+			logger.Security.Error("Failed to read sessions: " + err.Error())
+		}
+	}
+	now := time.Now()
+	for id, s := range sessions {
+		if now.After(s.ExpiresAt) {
+			delete(sessions, id)
+		}
+	}
+	return sessions
+}
+
+// saveSessions writes the session table. Caller must hold sessionsMu.
+func saveSessions() error {
+	list := make([]*Session, 0, len(sessions))
+	for _, s := range sessions {
+		list = append(list, s)
+	}
+	slices.SortFunc(list, func(a, b *Session) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	path := config.GetSessionsFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sessions: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write sessions: %w", err)
+	}
+	sessionsPersisted = time.Now()
+	return nil
+}
+
+// CreateSession starts a session for a user and returns it with a JWT bound to it. Password logins pass an empty role.
+func CreateSession(username, role, ip, userAgent string) (Session, string, error) {
+	now := time.Now()
+	session := &Session{
+		ID:        uuid.NewString(),
+		Username:  username,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(config.GetAuthTokenLifetime()) * time.Minute),
+		LastSeen:  now,
+		IP:        ip,
+		UserAgent: userAgent,
+		Role:      role,
+	}
+
+	claims := &jwt.MapClaims{
+		"exp": session.ExpiresAt.Unix(),
+		"iss": "StationeersServerUI",
+		"id":  username,
+		"sid": session.ID,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.GetJwtKey()))
+	if err != nil {
+		return Session{}, "", err
+	}
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	loadSessions()[session.ID] = session
+	if err := saveSessions(); err != nil {
+		delete(sessions, session.ID)
+		return Session{}, "", err
+	}
+	return *session, token, nil
+}
+
+// touchSession returns the role of a session if it exists for the user and records its use
+func touchSession(id, username string) (string, bool) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	session, ok := loadSessions()[id]
+	if !ok || session.Username != username {
+		return "", false
+	}
+	session.LastSeen = time.Now()
+	if time.Since(sessionsPersisted) > sessionLastSeenPersistInterval {
+		if err := saveSessions(); err != nil {
+			logger.Security.Warn("Failed to persist session activity: " + err.Error())
+		}
+	}
+	return session.Role, true
+}
+
+// ListSessions returns the active sessions of a user, or of all users if username is empty, oldest first
+func ListSessions(username string) []Session {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	list := []Session{}
+	for _, s := range loadSessions() {
+		if username == "" || s.Username == username {
+			list = append(list, *s)
+		}
+	}
+	slices.SortFunc(list, func(a, b Session) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return list
+}
+
+// RevokeSession ends a single session
+func RevokeSession(id string) error {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	session, ok := loadSessions()[id]
+	if !ok {
+		return fmt.Errorf("session %s not found", id)
+	}
+	delete(sessions, id)
+	logger.Security.Infof("Session %s of %s ended", id, session.Username)
+	return saveSessions()
+}
+
+// RevokeUserSessions ends all sessions of a user, e.g. on "log out everywhere" or a password change, and returns how many were ended
+func RevokeUserSessions(username string) (int, error) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	count := 0
+	for id, s := range loadSessions() {
+		if s.Username == username {
+			delete(sessions, id)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	logger.Security.Infof("Ended %d session(s) of %s", count, username)
+	return count, saveSessions()
+}
@@ -1,8 +1,10 @@
 package security
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -11,6 +13,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
@@ -75,6 +78,10 @@ func EnsureTLSCerts() error {
 
 		if now.After(cert.NotAfter) {
 			logger.Security.Warn("Certificate is expired, regenerating...")
+		} else if nearExpiry.After(cert.NotAfter) && !isSelfSigned(cert) {
+			// CA issued certificates stay until they expire, ACME renews them and the certificate monitor warns about them
+			logger.Security.Warn("Certificate is near expiry (within 10 days), keeping it until it is renewed or replaced")
+			return nil
 		} else if nearExpiry.After(cert.NotAfter) {
 			logger.Security.Warn("Certificate is near expiry (within 10 days), regenerating...")
 		} else {
@@ -172,6 +179,124 @@ func generateSelfSignedCert() error {
 	return nil
 }
 
+// servingCertificate is the certificate the HTTPS server presents, swapped on reload without restarting the server
+var (
+	servingCertificate atomic.Pointer[tls.Certificate]
+	servingModTime     atomic.Int64 // modification time of the loaded certificate file, in Unix nanoseconds
+)
+
+// LoadServingCertificate loads the certificate at config.GetTLSCertPath() and config.GetTLSKeyPath() into the running HTTPS server.
+// Connections made afterwards use the new certificate, open connections keep the old one.
+func LoadServingCertificate() error {
+	certPath := config.GetTLSCertPath()
+	info, err := os.Stat(certPath)
+	if err != nil {
+		return fmt.Errorf("failed to read certificate: %w", err)
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, config.GetTLSKeyPath())
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+	servingCertificate.Store(&cert)
+	servingModTime.Store(info.ModTime().UnixNano())
+	logger.Security.Debug(fmt.Sprintf("Serving TLS certificate for %v, valid until %s", cert.Leaf.DNSNames, cert.Leaf.NotAfter.Format(time.RFC3339)))
+	return nil
+}
+
+// reloadChangedCertificate reloads the certificate if its file was replaced on disk, e.g. by an external tool like certbot
+func reloadChangedCertificate() {
+	info, err := os.Stat(config.GetTLSCertPath())
+	if err != nil || info.ModTime().UnixNano() == servingModTime.Load() {
+		return
+	}
+	if err := LoadServingCertificate(); err != nil {
+		logger.Security.Error("TLS certificate changed on disk but could not be loaded: " + err.Error())
+		return
+	}
+	logger.Security.Info("TLS certificate changed on disk and was reloaded")
+}
+
+// GetServingCertificate is the tls.Config GetCertificate callback of the HTTPS server
+func GetServingCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert := servingCertificate.Load()
+	if cert == nil {
+		return nil, fmt.Errorf("no TLS certificate loaded")
+	}
+	return cert, nil
+}
+
+// servingLeaf returns the parsed certificate the HTTPS server presents, or nil before one was loaded
+func servingLeaf() *x509.Certificate {
+	if cert := servingCertificate.Load(); cert != nil {
+		return cert.Leaf
+	}
+	return nil
+}
+
+// CertificateInfo describes the certificate the HTTPS server presents
+type CertificateInfo struct {
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	DNSNames   []string  `json:"dnsNames"`
+	NotBefore  time.Time `json:"notBefore"`
+	NotAfter   time.Time `json:"notAfter"`
+	DaysLeft   int       `json:"daysLeft"`
+	SelfSigned bool      `json:"selfSigned"`
+}
+
+// ServingCertificateInfo returns details of the certificate the HTTPS server presents
+func ServingCertificateInfo() (CertificateInfo, bool) {
+	leaf := servingLeaf()
+	if leaf == nil {
+		return CertificateInfo{}, false
+	}
+	return CertificateInfo{
+		Subject:    leaf.Subject.String(),
+		Issuer:     leaf.Issuer.String(),
+		DNSNames:   leaf.DNSNames,
+		NotBefore:  leaf.NotBefore,
+		NotAfter:   leaf.NotAfter,
+		DaysLeft:   int(time.Until(leaf.NotAfter).Hours() / 24),
+		SelfSigned: isSelfSigned(leaf),
+	}, true
+}
+
+// InstallCertificate validates a PEM certificate chain and private key, replaces the TLS files and loads them into the running HTTPS server
+func InstallCertificate(certPEM, keyPEM []byte) error {
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return fmt.Errorf("certificate and private key do not form a valid matching pair: %w", err)
+	}
+	certPath, keyPath := config.GetTLSCertPath(), config.GetTLSKeyPath()
+	if filepath.Dir(certPath) != filepath.Dir(keyPath) {
+		return fmt.Errorf("certificate and key paths must share a directory")
+	}
+	if err := os.MkdirAll(filepath.Dir(certPath), 0755); err != nil {
+		return err
+	}
+	oldCert, certReadErr := os.ReadFile(certPath)
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		return err
+	}
+	if err := os.Chmod(certPath, 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		if certReadErr == nil {
+			_ = os.WriteFile(certPath, oldCert, 0644)
+		}
+		return err
+	}
+	if err := os.Chmod(keyPath, 0600); err != nil {
+		return err
+	}
+	return LoadServingCertificate()
+}
+
+// isSelfSigned reports whether a certificate was issued by itself, like the ones generateSelfSignedCert creates
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject)
+}
+
 // fileExists checks if a file exists at the given path.
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
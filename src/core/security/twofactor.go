@@ -0,0 +1,327 @@
+// twofactor.go
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+/*
+Optional TOTP two-factor authentication (RFC 6238: SHA1, 6 digits, 30 second steps)
+- Enrolment is two-step: BeginTwoFactorEnrolment stores a pending secret, ConfirmTwoFactorEnrolment enables it once a valid code was entered
+- Confirming returns single-use recovery codes, only their bcrypt hashes are stored
+- With 2FA enabled, a correct password only yields a short-lived login challenge that must be completed with a code
+- Secrets live in TwoFactorFilePath; ResetTwoFactor (used by the -RecoveryPassword and -ResetTwoFactor flags) removes them
+*/
+
+const (
+	totpDigits         = 6
+	totpStep           = 30 * time.Second
+	totpSkewSteps      = 1 // accept codes of the previous and next step to tolerate clock drift
+	totpIssuer         = "StationeersServerUI"
+	recoveryCodeCount  = 10
+	loginChallengeTTL  = 5 * time.Minute
+	loginChallengeMaxN = 5 // wrong codes before a challenge is discarded
+)
+
+type twoFactorUser struct {
+	Secret        string    `json:"secret"`
+	Enabled       bool      `json:"enabled"` // false while the enrolment is not confirmed
+	EnabledAt     time.Time `json:"enabledAt,omitzero"`
+	RecoveryCodes []string  `json:"recoveryCodes"` // bcrypt hashes, removed when used
+	LastStep      int64     `json:"lastStep"`      // last accepted TOTP step, codes cannot be replayed
+}
+
+// PendingLogin is a login that passed its first step and waits for the second factor
+type PendingLogin struct {
+	Username string
+	Role     string // session role, see CreateSession
+	Method   string // first login step for the audit log, "password" or "oidc"
+}
+
+type loginChallenge struct {
+	login    PendingLogin
+	ip       string
+	expires  time.Time
+	failures int
+}
+
+var (
+	twoFactorMu    sync.Mutex
+	twoFactorUsers map[string]*twoFactorUser // nil until loaded
+
+	loginChallengesMu sync.Mutex
+	loginChallenges   = make(map[string]*loginChallenge)
+)
+
+// loadTwoFactor reads the 2FA file once. Caller must hold twoFactorMu.
+func loadTwoFactor() map[string]*twoFactorUser {
+	if twoFactorUsers != nil {
+		return twoFactorUsers
+	}
+	twoFactorUsers = make(map[string]*twoFactorUser)
+	data, err := os.ReadFile(config.GetTwoFactorFilePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Security.Error("Failed to read two-factor settings: " + err.Error())
+		}
+		return twoFactorUsers
+	}
+	if err := json.Unmarshal(data, &twoFactorUsers); err != nil {
+		logger.Security.Error("Failed to parse two-factor settings: " + err.Error())
+	}
+	return twoFactorUsers
+}
+
+// saveTwoFactor writes the 2FA file. Caller must hold twoFactorMu.
+func saveTwoFactor() error {
+	path := config.GetTwoFactorFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create two-factor directory: %w", err)
+	}
+	data, err := json.MarshalIndent(twoFactorUsers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode two-factor settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write two-factor settings: %w", err)
+	}
+	return nil
+}
+
+// totpCode computes the code of a base32 secret for a time step
+func totpCode(secret string, step int64) (string, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// matchTOTP returns the step a code is valid for around now, or -1
+func matchTOTP(secret, code string, now time.Time) int64 {
+	current := now.Unix() / int64(totpStep/time.Second)
+	for step := current - totpSkewSteps; step <= current+totpSkewSteps; step++ {
+		expected, err := totpCode(secret, step)
+		if err != nil {
+			return -1
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step
+		}
+	}
+	return -1
+}
+
+// IsTwoFactorEnabled reports whether a user has a confirmed 2FA enrolment
+func IsTwoFactorEnabled(username string) bool {
+	twoFactorMu.Lock()
+	defer twoFactorMu.Unlock()
+	u, ok := loadTwoFactor()[username]
+	return ok && u.Enabled
+}
+
+// BeginTwoFactorEnrolment creates a new pending secret for a user and returns it with its otpauth:// URI.
+// An already enabled 2FA stays active until the new secret is confirmed.
+func BeginTwoFactorEnrolment(username string) (secret, uri string, err error) {
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+
+	twoFactorMu.Lock()
+	defer twoFactorMu.Unlock()
+	users := loadTwoFactor()
+	if u, ok := users[username]; ok && u.Enabled {
+		return "", "", fmt.Errorf("two-factor authentication is already enabled for %s, disable it first", username)
+	}
+	users[username] = &twoFactorUser{Secret: secret}
+	if err := saveTwoFactor(); err != nil {
+		return "", "", err
+	}
+
+	label := url.PathEscape(totpIssuer + ":" + username)
+	if id := config.GetSSUIIdentifier(); id != "" {
+		label = url.PathEscape(totpIssuer + " " + strings.TrimSpace(id) + ":" + username)
+	}
+	query := url.Values{"secret": {secret}, "issuer": {totpIssuer}, "digits": {fmt.Sprint(totpDigits)}, "period": {"30"}}
+	return secret, "otpauth://totp/" + label + "?" + query.Encode(), nil
+}
+
+// ConfirmTwoFactorEnrolment enables a pending enrolment if code is valid and returns the recovery codes
+func ConfirmTwoFactorEnrolment(username, code string) ([]string, error) {
+	twoFactorMu.Lock()
+	defer twoFactorMu.Unlock()
+	u, ok := loadTwoFactor()[username]
+	if !ok || u.Secret == "" {
+		return nil, fmt.Errorf("no two-factor enrolment in progress for %s", username)
+	}
+	if u.Enabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled for %s", username)
+	}
+	step := matchTOTP(u.Secret, normalizeCode(code), time.Now())
+	if step < 0 {
+		return nil, fmt.Errorf("invalid code")
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for n := range codes {
+		codes[n] = newRecoveryCode()
+		hash, err := bcrypt.GenerateFromPassword([]byte(codes[n]), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		hashes[n] = string(hash)
+	}
+
+	u.Enabled, u.EnabledAt, u.RecoveryCodes, u.LastStep = true, time.Now(), hashes, step
+	if err := saveTwoFactor(); err != nil {
+		u.Enabled = false
+		return nil, err
+	}
+	logger.Security.Infof("Two-factor authentication enabled for %s", username)
+	return codes, nil
+}
+
+// recoveryCodeAlphabet is lowercase base32, normalizeCode lowercases what users type
+const recoveryCodeAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+
+// newRecoveryCode returns a random code of 10 base32 characters (50 bits) like "k3xqa-9tbmz"
+func newRecoveryCode() string {
+	raw := make([]byte, 10)
+	rand.Read(raw)
+	for n, b := range raw {
+		raw[n] = recoveryCodeAlphabet[b%byte(len(recoveryCodeAlphabet))]
+	}
+	return string(raw[:5]) + "-" + string(raw[5:])
+}
+
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
+
+// VerifyTwoFactorCode checks a TOTP code or an unused recovery code of a user. Recovery codes are consumed.
+func VerifyTwoFactorCode(username, code string) bool {
+	code = normalizeCode(code)
+	twoFactorMu.Lock()
+	defer twoFactorMu.Unlock()
+	u, ok := loadTwoFactor()[username]
+	if !ok || !u.Enabled {
+		return false
+	}
+
+	if len(code) == totpDigits {
+		if step := matchTOTP(u.Secret, code, time.Now()); step > u.LastStep {
+			u.LastStep = step
+			if err := saveTwoFactor(); err != nil {
+				logger.Security.Warn("Failed to persist two-factor state: " + err.Error())
+			}
+			return true
+		}
+		return false
+	}
+
+	for n, hash := range u.RecoveryCodes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil {
+			u.RecoveryCodes = append(u.RecoveryCodes[:n], u.RecoveryCodes[n+1:]...)
+			if err := saveTwoFactor(); err != nil {
+				logger.Security.Warn("Failed to persist used recovery code: " + err.Error())
+			}
+			logger.Security.Warnf("Recovery code used by %s, %d left", username, len(u.RecoveryCodes))
+			return true
+		}
+	}
+	return false
+}
+
+// RemainingRecoveryCodes returns how many unused recovery codes a user has
+func RemainingRecoveryCodes(username string) int {
+	twoFactorMu.Lock()
+	defer twoFactorMu.Unlock()
+	if u, ok := loadTwoFactor()[username]; ok && u.Enabled {
+		return len(u.RecoveryCodes)
+	}
+	return 0
+}
+
+// ResetTwoFactor disables 2FA for a user and deletes the secret and recovery codes
+func ResetTwoFactor(username string) error {
+	twoFactorMu.Lock()
+	defer twoFactorMu.Unlock()
+	users := loadTwoFactor()
+	if _, ok := users[username]; !ok {
+		return nil
+	}
+	delete(users, username)
+	if err := saveTwoFactor(); err != nil {
+		return err
+	}
+	logger.Security.Warnf("Two-factor authentication disabled for %s", username)
+	return nil
+}
+
+// NewLoginChallenge is called after a correct password or OIDC login of a user with 2FA and returns the challenge ID for the second login step
+func NewLoginChallenge(login PendingLogin, ip string) string {
+	loginChallengesMu.Lock()
+	defer loginChallengesMu.Unlock()
+	now := time.Now()
+	for id, c := range loginChallenges {
+		if now.After(c.expires) {
+			delete(loginChallenges, id)
+		}
+	}
+	id := uuid.NewString()
+	loginChallenges[id] = &loginChallenge{login: login, ip: ip, expires: now.Add(loginChallengeTTL)}
+	return id
+}
+
+// CompleteLoginChallenge verifies the code for a challenge and returns the login it was issued for.
+// The challenge is taken out before the code is checked, so parallel requests cannot complete it twice.
+// A wrong code puts it back until there were too many.
+func CompleteLoginChallenge(id, ip, code string) (PendingLogin, error) {
+	loginChallengesMu.Lock()
+	c, ok := loginChallenges[id]
+	delete(loginChallenges, id)
+	loginChallengesMu.Unlock()
+	if !ok || time.Now().After(c.expires) || c.ip != ip {
+		return PendingLogin{}, fmt.Errorf("login challenge expired, log in again")
+	}
+
+	if !VerifyTwoFactorCode(c.login.Username, code) {
+		c.failures++
+		if c.failures < loginChallengeMaxN {
+			loginChallengesMu.Lock()
+			loginChallenges[id] = c
+			loginChallengesMu.Unlock()
+		}
+		return c.login, fmt.Errorf("invalid code")
+	}
+	return c.login, nil
+}
@@ -0,0 +1,104 @@
+package security
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// RFC 6238 appendix B test vectors (SHA1), truncated to 6 digits
+func TestTOTPCode(t *testing.T) {
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range cases {
+		got, err := totpCode(secret, unix/30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("totpCode at %d = %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestMatchTOTPSkew(t *testing.T) {
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Unix(1111111109, 0)
+	step := now.Unix() / 30
+
+	for _, offset := range []int64{-1, 0, 1} {
+		code, _ := totpCode(secret, step+offset)
+		if got := matchTOTP(secret, code, now); got != step+offset {
+			t.Errorf("code of step offset %d matched step %d", offset, got)
+		}
+	}
+	code, _ := totpCode(secret, step+2)
+	if got := matchTOTP(secret, code, now); got != -1 {
+		t.Errorf("code two steps ahead should not match, got step %d", got)
+	}
+}
+
+func TestRecoveryCodeFormat(t *testing.T) {
+	format := regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`)
+	seen := make(map[string]bool)
+	for range 100 {
+		code := newRecoveryCode()
+		if !format.MatchString(code) || seen[code] {
+			t.Fatalf("unexpected or repeated recovery code %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestLoginChallengeCompletesOnce(t *testing.T) {
+	config.ConfigMu.Lock()
+	oldPath := config.TwoFactorFilePath
+	config.TwoFactorFilePath = t.TempDir() + "/twofactor.json"
+	config.ConfigMu.Unlock()
+
+	// two different valid recovery codes, so only the challenge itself can stop the second request
+	codes := []string{"aaaaa-aaaaa", "bbbbb-bbbbb"}
+	var hashes []string
+	for _, code := range codes {
+		hash, _ := bcrypt.GenerateFromPassword([]byte(code), bcrypt.MinCost)
+		hashes = append(hashes, string(hash))
+	}
+	twoFactorMu.Lock()
+	twoFactorUsers = map[string]*twoFactorUser{"alice": {Enabled: true, RecoveryCodes: hashes}}
+	twoFactorMu.Unlock()
+	t.Cleanup(func() {
+		twoFactorMu.Lock()
+		twoFactorUsers = nil
+		twoFactorMu.Unlock()
+		config.ConfigMu.Lock()
+		config.TwoFactorFilePath = oldPath
+		config.ConfigMu.Unlock()
+	})
+
+	id := NewLoginChallenge(PendingLogin{Username: "alice"}, "127.0.0.1")
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	completed := 0
+	for _, code := range codes {
+		wg.Go(func() {
+			if _, err := CompleteLoginChallenge(id, "127.0.0.1", code); err == nil {
+				mu.Lock()
+				completed++
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if completed != 1 {
+		t.Fatalf("a login challenge must be completed exactly once, got %d", completed)
+	}
+}
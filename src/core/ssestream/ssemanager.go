@@ -2,8 +2,11 @@
 package ssestream
 
 import (
+	"cmp"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,9 +21,16 @@ import (
 // Workaround: Use HTTP/2 (RFC 7540, Section 5.1.2) for up to 100 streams: https://datatracker.ietf.org/doc/html/rfc7540#section-5.1.2
 // I spent way too much time on this thinking it would be the backend blocking requests. It's not.
 
+// Event is a stream message with its ID. IDs increase per stream and start over when SSUI restarts.
+type Event struct {
+	ID   uint64
+	Data string
+}
+
 // Client represents a connected SSE client
 type Client struct {
-	messages chan string
+	messages chan Event
+	internal chan string // set instead of messages for internal subscribers, which only need the text
 	lastSeen time.Time
 }
 
@@ -33,6 +43,11 @@ type SSEManager struct {
 	kinematicDropCount int
 	lastKinematicLog   time.Time
 	dropMu             sync.Mutex
+
+	// scrollback replayed to new clients, the last config.GetSSEScrollbackLines() events
+	history   []Event
+	lastID    uint64
+	historyMu sync.Mutex
 }
 
 // NewSSEManager creates a new SSE stream manager
@@ -85,12 +100,13 @@ func (m *SSEManager) CreateStreamHandler(streamType string) http.HandlerFunc {
 			return
 		}
 
-		// Create a new client
+		// Create a new client. The scrollback is taken while no broadcast can run, so nothing is missed or sent twice.
 		client := &Client{
-			messages: make(chan string, m.maxBuffer),
+			messages: make(chan Event, m.maxBuffer),
 			lastSeen: time.Now(),
 		}
 		m.clients[client] = true
+		replay := m.historySince(lastEventID(r))
 		m.clientsMu.Unlock()
 
 		// Send initial connection event
@@ -102,6 +118,15 @@ func (m *SSEManager) CreateStreamHandler(streamType string) http.HandlerFunc {
 		}
 		flusher.Flush()
 
+		// Replay the scrollback, only what the client has not seen yet if it reconnects with Last-Event-ID
+		for _, event := range replay {
+			if err := writeEvent(w, event); err != nil {
+				m.removeClient(client)
+				return
+			}
+		}
+		flusher.Flush()
+
 		// Handle client disconnection
 		notify := r.Context().Done()
 
@@ -110,6 +135,66 @@ func (m *SSEManager) CreateStreamHandler(streamType string) http.HandlerFunc {
 	}
 }
 
+// Subscribe registers a listener that is not an SSE response, e.g. a WebSocket connection, under the same client limit.
+// replay holds the scrollback after lastEventID (all of it for 0), then events arrive on messages until unsubscribe is called.
+// ok is false if the client limit is reached.
+func (m *SSEManager) Subscribe(lastEventID uint64) (replay []Event, messages <-chan Event, unsubscribe func(), ok bool) {
+	m.clientsMu.Lock()
+	defer m.clientsMu.Unlock()
+	if len(m.clients) >= m.maxClients {
+		return nil, nil, nil, false
+	}
+	client := &Client{
+		messages: make(chan Event, m.maxBuffer),
+		lastSeen: time.Now(),
+	}
+	m.clients[client] = true
+	var once sync.Once
+	return m.historySince(lastEventID), client.messages, func() { once.Do(func() { m.removeClient(client) }) }, true
+}
+
+// lastEventID returns the ID of the last event a reconnecting client has seen, from the standard Last-Event-ID header
+// or a lastEventId query parameter for clients that cannot set headers. 0 means none.
+func lastEventID(r *http.Request) uint64 {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	id, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+	return id
+}
+
+// historySince returns the scrollback after the event with ID lastEventID. An ID from before a restart gets everything.
+func (m *SSEManager) historySince(lastEventID uint64) []Event {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	if lastEventID > m.lastID {
+		lastEventID = 0
+	}
+	n, _ := slices.BinarySearchFunc(m.history, lastEventID+1, func(e Event, id uint64) int {
+		return cmp.Compare(e.ID, id)
+	})
+	return slices.Clone(m.history[n:])
+}
+
+// record assigns the next ID to a message and keeps it in the scrollback
+func (m *SSEManager) record(message string) Event {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	m.lastID++
+	event := Event{ID: m.lastID, Data: message}
+	m.history = append(m.history, event)
+	if excess := len(m.history) - config.GetSSEScrollbackLines(); excess > 0 {
+		m.history = slices.Delete(m.history, 0, excess)
+	}
+	return event
+}
+
+func writeEvent(w http.ResponseWriter, event Event) error {
+	_, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.ID, event.Data)
+	return err
+}
+
 // streamMessages handles sending messages to a specific client
 func (m *SSEManager) streamMessages(
 	w http.ResponseWriter,
@@ -122,9 +207,8 @@ func (m *SSEManager) streamMessages(
 
 	for {
 		select {
-		case msg := <-client.messages:
-			_, err := fmt.Fprintf(w, "data: %s\n\n", msg)
-			if err != nil {
+		case event := <-client.messages:
+			if err := writeEvent(w, event); err != nil {
 				//logger.SSE.Error(" ❌ Failed to send message: " + err.Error())
 				return
 			}
@@ -188,9 +272,17 @@ func (m *SSEManager) Broadcast(message string) {
 	m.clientsMu.RLock()
 	defer m.clientsMu.RUnlock()
 
+	event := m.record(message)
 	for client := range m.clients {
+		if client.internal != nil {
+			select {
+			case client.internal <- message:
+			default:
+			}
+			continue
+		}
 		select {
-		case client.messages <- message:
+		case client.messages <- event:
 			// Message sent successfully
 		default:
 			// Client channel is full, log and skip
@@ -204,11 +296,11 @@ func (m *SSEManager) AddInternalSubscriber() chan string {
 	defer m.clientsMu.Unlock()
 
 	client := &Client{
-		messages: make(chan string, m.maxBuffer),
+		internal: make(chan string, m.maxBuffer),
 		lastSeen: time.Now(),
 	}
 	m.clients[client] = true
-	return client.messages
+	return client.internal
 }
 
 // removeClient safely removes a client from the manager
@@ -217,5 +309,9 @@ func (m *SSEManager) removeClient(client *Client) {
 	defer m.clientsMu.Unlock()
 
 	delete(m.clients, client)
-	close(client.messages)
+	if client.internal != nil {
+		close(client.internal)
+	} else {
+		close(client.messages)
+	}
 }
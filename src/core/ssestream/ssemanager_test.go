@@ -0,0 +1,59 @@
+package ssestream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+func TestScrollbackReplay(t *testing.T) {
+	config.ConfigMu.Lock()
+	oldLines := config.SSEScrollbackLines
+	config.SSEScrollbackLines = 3
+	config.ConfigMu.Unlock()
+	t.Cleanup(func() {
+		config.ConfigMu.Lock()
+		config.SSEScrollbackLines = oldLines
+		config.ConfigMu.Unlock()
+	})
+
+	m := NewSSEManager(10, 10)
+	for _, line := range []string{"one", "two", "three", "four"} {
+		m.Broadcast(line)
+	}
+
+	// a new client gets the last lines, a reconnecting one only what it missed
+	for header, want := range map[string]string{
+		"":   "id: 2\ndata: two\n\nid: 3\ndata: three\n\nid: 4\ndata: four\n\n",
+		"3":  "id: 4\ndata: four\n\n",
+		"99": "id: 2\ndata: two\n\nid: 3\ndata: three\n\nid: 4\ndata: four\n\n", // ID from before a restart
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		r := httptest.NewRequest(http.MethodGet, "/console", nil).WithContext(ctx)
+		if header != "" {
+			r.Header.Set("Last-Event-ID", header)
+		}
+		rec := httptest.NewRecorder()
+		m.CreateStreamHandler("Console")(rec, r)
+		cancel()
+		body := strings.TrimPrefix(rec.Body.String(), "data: Console Stream Connected\n\n")
+		if body != want {
+			t.Errorf("Last-Event-ID %q: got %q, want %q", header, body, want)
+		}
+	}
+
+	replay, messages, unsubscribe, ok := m.Subscribe(4)
+	if !ok || len(replay) != 0 {
+		t.Fatalf("a subscriber that is up to date must not get a replay, got %v", replay)
+	}
+	m.Broadcast("five")
+	if event := <-messages; event.ID != 5 || event.Data != "five" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	unsubscribe()
+}
@@ -14,8 +14,21 @@ var (
 	WarnLogStreamManager    = NewSSEManager(config.GetMaxSSEConnections(), config.GetSSEMessageBufferSize())
 	ErrorLogStreamManager   = NewSSEManager(config.GetMaxSSEConnections(), config.GetSSEMessageBufferSize())
 	BackendLogStreamManager = NewSSEManager(config.GetMaxSSEConnections(), config.GetSSEMessageBufferSize())
+	SteamCMDStreamManager   = NewSSEManager(config.GetMaxSSEConnections(), config.GetSSEMessageBufferSize())
 )
 
+// Streams maps the stream names of the WebSocket API to their managers. The names match the SSE endpoint paths.
+var Streams = map[string]*SSEManager{
+	"console":           ConsoleStreamManager,
+	"events":            EventStreamManager,
+	"logs/debug":        DebugLogStreamManager,
+	"logs/info":         InfoLogStreamManager,
+	"logs/warn":         WarnLogStreamManager,
+	"logs/error":        ErrorLogStreamManager,
+	"logs/backend":      BackendLogStreamManager,
+	"steamcmd/progress": SteamCMDStreamManager,
+}
+
 // BroadcastConsoleOutput sends log to all connected console log clients
 func BroadcastConsoleOutput(message string) {
 	ConsoleStreamManager.Broadcast(message)
@@ -50,3 +63,8 @@ func BroadcastErrorLog(message string) {
 func BroadcastBackendLog(message string) {
 	BackendLogStreamManager.Broadcast(message)
 }
+
+// BroadcastSteamCMDProgress sends SteamCMD progress as JSON to all connected clients
+func BroadcastSteamCMDProgress(message string) {
+	SteamCMDStreamManager.Broadcast(message)
+}
@@ -0,0 +1,33 @@
+package discordbot
+
+import (
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
+	"github.com/bwmarrin/discordgo"
+)
+
+// auditEventType is the event type audit log entries are mirrored as, so routing rules can send them to a dedicated channel
+const auditEventType = "AUDIT"
+
+// mirrorAuditEntry sends an audit log entry to Discord, registered with audit.RegisterMirror.
+// Entries contain user supplied params, so they must not ping anyone.
+func mirrorAuditEntry(e audit.Entry) {
+	sendEventMessage(auditEventType, "📝 "+e.Summary(), &discordgo.MessageAllowedMentions{})
+}
+
+// recordInteraction adds a Discord interaction to the audit log
+func recordInteraction(i *discordgo.InteractionCreate, action string, params map[string]any, err error) {
+	if params == nil {
+		params = make(map[string]any)
+	}
+	params["userID"] = interactionUserID(i)
+	audit.RecordError(audit.Entry{ActorType: audit.ActorDiscord, Actor: interactionUserName(i), Action: action, Params: params}, err)
+}
+
+// commandOptionParams returns the options of a slash command as audit params
+func commandOptionParams(i *discordgo.InteractionCreate) map[string]any {
+	params := make(map[string]any)
+	for _, option := range i.ApplicationCommandData().Options {
+		params[option.Name] = option.Value
+	}
+	return params
+}
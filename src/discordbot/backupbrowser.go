@@ -0,0 +1,222 @@
+package discordbot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/backupmgr"
+	"github.com/bwmarrin/discordgo"
+)
+
+/*
+Interactive backup browser for /list.
+- Pages of backups as an embed, a select menu to pick one and buttons for Download, Restore and Pin
+- Stateless: the browser state (limit, page, selection) is encoded in the component custom IDs,
+  so the browser keeps working across bot restarts
+- Download reuses ButtonDownloadBackupPfx and handleDownloadButtonInteraction, Restore goes through askForConfirmation
+*/
+
+const (
+	BackupBrowserPagePfx    = "ssui_bb_page_"    // followed by the encoded browser state
+	BackupBrowserSelectPfx  = "ssui_bb_select_"  // followed by the encoded browser state
+	BackupBrowserPinPfx     = "ssui_bb_pin_"     // followed by the encoded browser state
+	BackupBrowserRestorePfx = "ssui_bb_restore_" // followed by the encoded browser state
+	backupBrowserPageSize   = 10
+)
+
+// backupBrowserState is everything needed to render one view of the backup browser
+type backupBrowserState struct {
+	limit    int // number of most recent backups to browse, 0 for all
+	page     int
+	selected int // backup index, -1 if nothing is selected
+}
+
+func (st backupBrowserState) encode() string {
+	return fmt.Sprintf("%d.%d.%d", st.limit, st.page, st.selected)
+}
+
+func decodeBackupBrowserState(encoded string) (backupBrowserState, error) {
+	parts := strings.Split(encoded, ".")
+	if len(parts) != 3 {
+		return backupBrowserState{}, fmt.Errorf("invalid backup browser state %q", encoded)
+	}
+	var values [3]int
+	for n, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return backupBrowserState{}, fmt.Errorf("invalid backup browser state %q", encoded)
+		}
+		values[n] = v
+	}
+	return backupBrowserState{limit: values[0], page: values[1], selected: values[2]}, nil
+}
+
+// buildBackupBrowser renders the embed and components for the given browser state
+func buildBackupBrowser(st backupBrowserState) (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	backups, err := backupmgr.GlobalBackupManager.ListBackups(st.limit) // newest first
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(backups) == 0 {
+		return generateEmbed(EmbedData{Title: "Backup List", Description: "No backups found", Color: 0xFFD700}), nil, nil
+	}
+
+	pages := (len(backups) + backupBrowserPageSize - 1) / backupBrowserPageSize
+	st.page = max(0, min(st.page, pages-1))
+	start := st.page * backupBrowserPageSize
+	end := min(start+backupBrowserPageSize, len(backups))
+	pageBackups := backups[start:end]
+
+	fields := make([]EmbedField, 0, len(pageBackups))
+	options := make([]discordgo.SelectMenuOption, 0, len(pageBackups))
+	var selected *backupmgr.BackupSaveFile
+	for n, b := range pageBackups {
+		name := fmt.Sprintf("📂 Backup #%d", b.Index)
+		if b.Pinned {
+			name += " 📌"
+		}
+		if b.Index == st.selected {
+			name = "▶️ " + name
+			selected = &pageBackups[n]
+		}
+		fields = append(fields, EmbedField{
+			Name:  name,
+			Value: fmt.Sprintf("%s (<t:%d:R>)", b.SaveTime.Format("January 2, 2006, 3:04 PM"), b.SaveTime.Unix()),
+		})
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       fmt.Sprintf("Backup #%d", b.Index),
+			Description: b.SaveTime.Format("Jan 2, 2006 3:04 PM"),
+			Value:       strconv.Itoa(b.Index),
+			Default:     b.Index == st.selected,
+		})
+	}
+
+	embed := generateEmbed(EmbedData{
+		Title:       "📜 Backup Archives",
+		Description: fmt.Sprintf("Showing %d-%d of %d backups • Page %d/%d\nPick a backup below to download, restore or pin it.", start+1, end, len(backups), st.page+1, pages),
+		Color:       0xFFD700,
+		Fields:      fields,
+	})
+
+	prev, next := st, st
+	prev.page, prev.selected = st.page-1, -1
+	next.page, next.selected = st.page+1, -1
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    BackupBrowserSelectPfx + st.encode(),
+				Placeholder: "Select a backup...",
+				Options:     options,
+			},
+		}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "◀ Newer", Style: discordgo.SecondaryButton, CustomID: BackupBrowserPagePfx + prev.encode(), Disabled: st.page == 0},
+			discordgo.Button{Label: "Older ▶", Style: discordgo.SecondaryButton, CustomID: BackupBrowserPagePfx + next.encode(), Disabled: st.page >= pages-1},
+		}},
+	}
+
+	if selected != nil {
+		pinLabel := "📌 Pin"
+		if selected.Pinned {
+			pinLabel = "📌 Unpin"
+		}
+		components = append(components, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: fmt.Sprintf("📥 Download #%d", selected.Index), Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("%s%d", ButtonDownloadBackupPfx, selected.Index)},
+			discordgo.Button{Label: fmt.Sprintf("⏪ Restore #%d", selected.Index), Style: discordgo.DangerButton, CustomID: BackupBrowserRestorePfx + st.encode()},
+			discordgo.Button{Label: pinLabel, Style: discordgo.SecondaryButton, CustomID: BackupBrowserPinPfx + st.encode()},
+		}})
+	}
+
+	return embed, components, nil
+}
+
+// handleBackupBrowserInteraction handles paging, selection, pinning and restoring from the backup browser
+func handleBackupBrowserInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent {
+		return
+	}
+
+	customID := i.MessageComponentData().CustomID
+	var prefix string
+	for _, p := range []string{BackupBrowserPagePfx, BackupBrowserSelectPfx, BackupBrowserPinPfx, BackupBrowserRestorePfx} {
+		if strings.HasPrefix(customID, p) {
+			prefix = p
+			break
+		}
+	}
+	if prefix == "" {
+		return
+	}
+
+	st, err := decodeBackupBrowserState(strings.TrimPrefix(customID, prefix))
+	if err != nil {
+		respondToButtonError(s, i, "Invalid backup browser state")
+		return
+	}
+
+	switch prefix {
+	case BackupBrowserSelectPfx:
+		values := i.MessageComponentData().Values
+		if len(values) == 0 {
+			st.selected = -1
+		} else if st.selected, err = strconv.Atoi(values[0]); err != nil {
+			respondToButtonError(s, i, "Invalid backup index")
+			return
+		}
+	case BackupBrowserPinPfx:
+		backup, ok := findBackup(st.selected)
+		if !ok {
+			respondToButtonError(s, i, fmt.Sprintf("Backup #%d does not exist anymore", st.selected))
+			return
+		}
+		err := backupmgr.GlobalBackupManager.SetBackupPinned(backup.Index, !backup.Pinned)
+		recordInteraction(i, "discord backup pin", map[string]any{"index": backup.Index, "pinned": !backup.Pinned}, err)
+		if err != nil {
+			respondToButtonError(s, i, "Failed to pin backup: "+err.Error())
+			return
+		}
+		action := map[bool]string{true: "📌 pinned", false: "unpinned"}[!backup.Pinned]
+		SendMessageToEventLogChannel(fmt.Sprintf("Backup #%d %s by %s", backup.Index, action, interactionUserName(i)))
+	case BackupBrowserRestorePfx:
+		backup, ok := findBackup(st.selected)
+		if !ok {
+			respondToButtonError(s, i, fmt.Sprintf("Backup #%d does not exist anymore", st.selected))
+			return
+		}
+		prompt := EmbedData{
+			Title:       "Backup Restore",
+			Description: fmt.Sprintf("Restore backup #%d? The server will be stopped and the current world state will be replaced.", backup.Index),
+			Color:       0xFFA500,
+			Fields:      []EmbedField{{Name: "Saved", Value: backup.SaveTime.Format("January 2, 2006, 3:04 PM"), Inline: true}},
+		}
+		err := askForConfirmation(s, i, prompt, "⏪ Restore", func(s *discordgo.Session, ci *discordgo.InteractionCreate, progress *progressMessage) error {
+			return restoreBackupWithProgress(backup, interactionUserName(ci), progress)
+		})
+		if err != nil && !errors.Is(err, errAwaitingConfirmation) {
+			logger.Discord.Error("Error asking for restore confirmation: " + err.Error())
+		}
+		return
+	}
+
+	embed, components, err := buildBackupBrowser(st)
+	if err != nil {
+		respondToButtonError(s, i, "Failed to fetch backup list: "+err.Error())
+		return
+	}
+	if components == nil {
+		components = []discordgo.MessageComponent{}
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+	if err != nil {
+		logger.Discord.Error("Error updating backup browser: " + err.Error())
+	}
+}
@@ -0,0 +1,189 @@
+package discordbot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/commandmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+/*
+Two-way chat bridge between the in-game chat and a Discord channel.
+- In-game chat lines are detected by detectionmgr (EventPlayerChat) and relayed to ChatBridgeChannelID
+- Messages posted in ChatBridgeChannelID are broadcast in-game via the SSCM "announce" command, like other server messages
+- Both directions are rate limited and mentions are never resolved on the Discord side
+*/
+
+const (
+	chatBridgeInGamePrefix   = "[Discord]" // sender prefix of messages we sent in-game, so their echo is not relayed back to Discord
+	chatBridgeMaxInGameLen   = 200         // keep in-game lines short, the game chat window is small
+	chatBridgeUserCooldown   = 2 * time.Second
+	chatBridgeWindow         = 10 * time.Second
+	chatBridgeMaxPerWindow   = 10 // per direction, across all users
+	chatBridgeZeroWidthSpace = "\u200b"
+)
+
+var (
+	discordMentionPattern = regexp.MustCompile(`<(@[!&]?|#)(\d+)>`)
+	customEmojiPattern    = regexp.MustCompile(`<a?:(\w+):\d+>`)
+
+	toDiscordLimiter = newChatRateLimiter(chatBridgeUserCooldown, chatBridgeWindow, chatBridgeMaxPerWindow)
+	toGameLimiter    = newChatRateLimiter(chatBridgeUserCooldown, chatBridgeWindow, chatBridgeMaxPerWindow)
+)
+
+// chatRateLimiter enforces a per-sender cooldown and a global cap per time window
+type chatRateLimiter struct {
+	mu          sync.Mutex
+	cooldown    time.Duration
+	window      time.Duration
+	maxInWindow int
+	lastByUser  map[string]time.Time
+	sent        []time.Time
+}
+
+func newChatRateLimiter(cooldown, window time.Duration, maxInWindow int) *chatRateLimiter {
+	return &chatRateLimiter{
+		cooldown:    cooldown,
+		window:      window,
+		maxInWindow: maxInWindow,
+		lastByUser:  make(map[string]time.Time),
+	}
+}
+
+// allow reports whether sender may send a message at now and records it if so
+func (l *chatRateLimiter) allow(sender string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.lastByUser[sender]; ok && now.Sub(last) < l.cooldown {
+		return false
+	}
+
+	// drop timestamps that left the window
+	kept := l.sent[:0]
+	for _, t := range l.sent {
+		if now.Sub(t) < l.window {
+			kept = append(kept, t)
+		}
+	}
+	l.sent = kept
+
+	if len(l.sent) >= l.maxInWindow {
+		return false
+	}
+
+	l.sent = append(l.sent, now)
+	l.lastByUser[sender] = now
+	return true
+}
+
+// RelayChatToDiscord is called from the detection module to forward an in-game chat line to the chat bridge channel.
+func RelayChatToDiscord(username, message string) {
+	if !config.GetIsDiscordEnabled() || config.DiscordSession == nil {
+		return
+	}
+
+	channelID := config.GetChatBridgeChannelID()
+	if channelID == "" {
+		return
+	}
+
+	// Skip our own messages echoed back by the gameserver, players may still write the prefix in their messages
+	if strings.HasPrefix(strings.TrimSpace(username), chatBridgeInGamePrefix) {
+		return
+	}
+
+	if !toDiscordLimiter.allow(username, time.Now()) {
+		logger.Discord.Debug("Chat bridge: rate limit hit, dropping in-game message from " + username)
+		return
+	}
+
+	content := fmt.Sprintf("💬 **%s**: %s", sanitizeForDiscord(username), sanitizeForDiscord(message))
+	_, err := config.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{}, // never ping anyone from in-game text
+	})
+	if err != nil {
+		logger.Discord.Error("Error relaying chat message to Discord: " + err.Error())
+	}
+}
+
+// listenToChatBridgeMessages forwards messages posted in the chat bridge channel to the gameserver
+func listenToChatBridgeMessages(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m.Author == nil || m.Author.Bot || m.Author.ID == s.State.User.ID {
+		return
+	}
+
+	channelID := config.GetChatBridgeChannelID()
+	if channelID == "" || m.ChannelID != channelID {
+		return
+	}
+
+	if !config.GetIsSSCMEnabled() || !gamemgr.InternalIsServerRunning() {
+		return
+	}
+
+	text := sanitizeForGame(m.ContentWithMentionsReplaced())
+	if text == "" {
+		return
+	}
+
+	if !toGameLimiter.allow(m.Author.ID, time.Now()) {
+		if err := s.MessageReactionAdd(m.ChannelID, m.ID, "⏳"); err != nil {
+			logger.Discord.Debug("Chat bridge: could not add rate limit reaction: " + err.Error())
+		}
+		return
+	}
+
+	name := sanitizeForGame(chatDisplayName(m))
+	line := fmt.Sprintf("%s %s: %s", chatBridgeInGamePrefix, name, text)
+	if runes := []rune(line); len(runes) > chatBridgeMaxInGameLen {
+		line = string(runes[:chatBridgeMaxInGameLen-3]) + "..."
+	}
+
+	if err := commandmgr.WriteCommand("announce " + line); err != nil {
+		logger.Discord.Error("Error relaying chat message to gameserver: " + err.Error())
+	}
+}
+
+// chatDisplayName returns the guild nickname, global display name or username of the message author, in that order
+func chatDisplayName(m *discordgo.MessageCreate) string {
+	if m.Member != nil && m.Member.Nick != "" {
+		return m.Member.Nick
+	}
+	if m.Author.GlobalName != "" {
+		return m.Author.GlobalName
+	}
+	return m.Author.Username
+}
+
+// sanitizeForDiscord neutralizes mass mentions and raw mention tokens in text that originates from the gameserver
+func sanitizeForDiscord(text string) string {
+	text = strings.ReplaceAll(text, "@everyone", "@"+chatBridgeZeroWidthSpace+"everyone")
+	text = strings.ReplaceAll(text, "@here", "@"+chatBridgeZeroWidthSpace+"here")
+	text = discordMentionPattern.ReplaceAllString(text, "<$1"+chatBridgeZeroWidthSpace+"$2>")
+	return text
+}
+
+// sanitizeForGame flattens a Discord message into a single plain line the game console accepts
+func sanitizeForGame(text string) string {
+	text = customEmojiPattern.ReplaceAllString(text, ":$1:")
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		if r < 0x20 {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,272 @@
+package discordbot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
+)
+
+/*
+Interactive confirmations for destructive commands.
+- askForConfirmation answers a slash command with Confirm/Cancel buttons instead of acting right away
+- Only the user who ran the command can confirm; the prompt expires after confirmationTimeout
+- On confirm, the prompt message is edited in place and the action receives a progress reporter
+  that keeps editing the same message, so long operations do not need a "please be patient" text
+- The audit log records the confirmed action with its real outcome, not the prompt
+*/
+
+const (
+	ButtonConfirmPfx    = "ssui_confirm_" // Prefix for confirmation buttons, followed by the pending confirmation ID
+	ButtonCancelPfx     = "ssui_cancel_"  // Prefix for cancel buttons, followed by the pending confirmation ID
+	confirmationTimeout = 30 * time.Second
+)
+
+// errAwaitingConfirmation is returned by askForConfirmation once the prompt is shown, so the command is not recorded before the user answered
+var errAwaitingConfirmation = errors.New("awaiting confirmation")
+
+// confirmedAction runs after the user confirmed. progress edits the original prompt message in place.
+// The returned error is recorded in the audit log.
+type confirmedAction func(s *discordgo.Session, i *discordgo.InteractionCreate, progress *progressMessage) error
+
+type pendingConfirmation struct {
+	userID       string
+	prompt       EmbedData
+	confirmLabel string
+	action       confirmedAction
+	auditAction  string
+	auditParams  map[string]any
+	interaction  *discordgo.Interaction // the slash command interaction that owns the prompt message
+	timer        *time.Timer
+}
+
+var (
+	pendingConfirmations   = make(map[string]*pendingConfirmation)
+	pendingConfirmationsMu sync.Mutex
+)
+
+// askForConfirmation responds to a slash command with a confirmation prompt. action only runs if the invoking user confirms in time.
+// It returns errAwaitingConfirmation once the prompt is shown.
+func askForConfirmation(s *discordgo.Session, i *discordgo.InteractionCreate, prompt EmbedData, confirmLabel string, action confirmedAction) error {
+	id := uuid.New().String()
+	prompt.Fields = append(prompt.Fields, EmbedField{
+		Name:  "Confirmation required",
+		Value: fmt.Sprintf("This prompt expires <t:%d:R>.", time.Now().Add(confirmationTimeout).Unix()),
+	})
+
+	pending := &pendingConfirmation{
+		userID:       interactionUserID(i),
+		prompt:       prompt,
+		confirmLabel: confirmLabel,
+		action:       action,
+		interaction:  i.Interaction,
+		auditAction:  "discord confirm " + confirmLabel,
+		auditParams:  map[string]any{"prompt": prompt.Title + ": " + prompt.Description},
+	}
+	if i.Type == discordgo.InteractionApplicationCommand {
+		pending.auditAction, pending.auditParams = "discord /"+i.ApplicationCommandData().Name, commandOptionParams(i)
+	}
+
+	pendingConfirmationsMu.Lock()
+	pendingConfirmations[id] = pending
+	pending.timer = time.AfterFunc(confirmationTimeout, func() { expireConfirmation(s, id) })
+	pendingConfirmationsMu.Unlock()
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{generateEmbed(prompt)},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.Button{Label: confirmLabel, Style: discordgo.DangerButton, CustomID: ButtonConfirmPfx + id},
+					discordgo.Button{Label: "Cancel", Style: discordgo.SecondaryButton, CustomID: ButtonCancelPfx + id},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		takePendingConfirmation(id)
+		return err
+	}
+	return errAwaitingConfirmation
+}
+
+// takePendingConfirmation removes and returns a pending confirmation, or nil if it already expired or was answered
+func takePendingConfirmation(id string) *pendingConfirmation {
+	pendingConfirmationsMu.Lock()
+	defer pendingConfirmationsMu.Unlock()
+	pending, ok := pendingConfirmations[id]
+	if !ok {
+		return nil
+	}
+	delete(pendingConfirmations, id)
+	pending.timer.Stop()
+	return pending
+}
+
+// expireConfirmation disables the buttons of a prompt nobody answered in time
+func expireConfirmation(s *discordgo.Session, id string) {
+	pendingConfirmationsMu.Lock()
+	pending, ok := pendingConfirmations[id]
+	if ok {
+		delete(pendingConfirmations, id)
+	}
+	pendingConfirmationsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	expired := pending.prompt
+	expired.Color = 0x808080
+	expired.Fields = []EmbedField{{Name: "Status", Value: "⌛ Timed out, nothing was done", Inline: true}}
+	embeds := []*discordgo.MessageEmbed{generateEmbed(expired)}
+	components := []discordgo.MessageComponent{}
+	if _, err := s.InteractionResponseEdit(pending.interaction, &discordgo.WebhookEdit{Embeds: &embeds, Components: &components}); err != nil {
+		logger.Discord.Debug("Could not mark confirmation prompt as expired: " + err.Error())
+	}
+}
+
+// handleConfirmationButtonInteraction handles the Confirm and Cancel buttons of confirmation prompts
+func handleConfirmationButtonInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent {
+		return
+	}
+
+	customID := i.MessageComponentData().CustomID
+	var id string
+	var confirmed bool
+	switch {
+	case strings.HasPrefix(customID, ButtonConfirmPfx):
+		id, confirmed = strings.TrimPrefix(customID, ButtonConfirmPfx), true
+	case strings.HasPrefix(customID, ButtonCancelPfx):
+		id = strings.TrimPrefix(customID, ButtonCancelPfx)
+	default:
+		return
+	}
+
+	pendingConfirmationsMu.Lock()
+	pending, ok := pendingConfirmations[id]
+	pendingConfirmationsMu.Unlock()
+	if !ok {
+		respondToButtonError(s, i, "This confirmation has expired or was already answered")
+		return
+	}
+	if pending.userID != interactionUserID(i) {
+		respondToButtonError(s, i, "Only the user who ran the command can answer this confirmation")
+		return
+	}
+	if takePendingConfirmation(id) == nil {
+		respondToButtonError(s, i, "This confirmation has expired or was already answered")
+		return
+	}
+
+	data := pending.prompt
+	if !confirmed {
+		recordInteraction(i, "discord cancel "+pending.confirmLabel, map[string]any{"prompt": pending.prompt.Title + ": " + pending.prompt.Description}, nil)
+		data.Color = 0x808080
+		data.Fields = []EmbedField{{Name: "Status", Value: "✖️ Cancelled, nothing was done", Inline: true}}
+		updateComponentMessage(s, i, data)
+		return
+	}
+
+	data.Fields = []EmbedField{{Name: "Status", Value: "🕛 Confirmed by " + interactionUserName(i), Inline: true}}
+	updateComponentMessage(s, i, data)
+
+	progress := &progressMessage{session: s, interaction: pending.interaction, base: pending.prompt}
+	go func() {
+		err := pending.action(s, i, progress)
+		recordInteraction(i, pending.auditAction, pending.auditParams, err)
+	}()
+}
+
+// updateComponentMessage replaces the message a button belongs to and removes its buttons
+func updateComponentMessage(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{generateEmbed(data)},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		logger.Discord.Error("Error updating confirmation prompt: " + err.Error())
+	}
+}
+
+// progressMessage edits a slash command response in place while a long running operation makes progress
+type progressMessage struct {
+	session     *discordgo.Session
+	interaction *discordgo.Interaction
+	base        EmbedData
+	mu          sync.Mutex
+}
+
+// Update replaces the status fields of the message. Errors are logged, progress reporting never fails the operation.
+func (p *progressMessage) Update(color int, fields ...EmbedField) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data := p.base
+	data.Color = color
+	data.Fields = fields
+	embeds := []*discordgo.MessageEmbed{generateEmbed(data)}
+	components := []discordgo.MessageComponent{}
+	if _, err := p.session.InteractionResponseEdit(p.interaction, &discordgo.WebhookEdit{Embeds: &embeds, Components: &components}); err != nil {
+		logger.Discord.Debug("Could not update progress message: " + err.Error())
+	}
+}
+
+// Ticker calls render every interval until the returned stop function is called. Used to show elapsed time for operations without granular progress.
+func (p *progressMessage) Ticker(interval time.Duration, render func(elapsed time.Duration)) (stop func()) {
+	start := time.Now()
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				render(time.Since(start).Round(time.Second))
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-finished // make sure no tick overwrites the caller's final update
+	}
+}
+
+// interactionUserID returns the ID of the user behind an interaction, in guilds and DMs alike
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// interactionUserName returns a readable name of the user behind an interaction
+func interactionUserName(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		if name := i.Member.DisplayName(); name != "" {
+			return name
+		}
+		return i.Member.User.Username
+	}
+	if i.User != nil {
+		return i.User.Username
+	}
+	return "unknown user"
+}
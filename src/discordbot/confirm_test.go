@@ -0,0 +1,24 @@
+package discordbot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPendingConfirmationIsAnsweredOnce(t *testing.T) {
+	pending := &pendingConfirmation{userID: "42", timer: time.NewTimer(time.Hour)}
+	pendingConfirmationsMu.Lock()
+	pendingConfirmations["test"] = pending
+	pendingConfirmationsMu.Unlock()
+
+	if got := takePendingConfirmation("test"); got != pending {
+		t.Fatalf("expected the pending confirmation, got %+v", got)
+	}
+	// a second click, or the expiry timer racing the click, must not run the action again
+	if got := takePendingConfirmation("test"); got != nil {
+		t.Fatal("a confirmation must only be taken once")
+	}
+	if pending.timer.Stop() {
+		t.Error("taking a confirmation must stop its expiry timer")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
@@ -135,6 +136,7 @@ func handleControlReactions(s *discordgo.Session, r *discordgo.MessageReactionAd
 		return
 	}
 	username := user.Username
+	audit.Record(audit.Entry{ActorType: audit.ActorDiscord, Actor: username, Action: "discord control panel " + r.Emoji.Name, Params: map[string]any{"userID": r.UserID}})
 
 	// Send a temporary confirmation message to the control panel channel
 	sendTemporaryMessage(s, config.GetControlPanelChannelID(), actionMessage, 30*time.Second)
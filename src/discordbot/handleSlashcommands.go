@@ -2,17 +2,20 @@ package discordbot
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/backupmgr"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/commandmgr"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
 
 	"github.com/bwmarrin/discordgo"
@@ -34,8 +37,13 @@ var handlers = map[string]commandHandler{
 	"update":       handleUpdate,
 	"command":      handleCommand,
 	"announce":     handleAnnounce,
+	"logs":         handleLogs,
+	"modpack":      handleModPack,
 }
 
+// readOnlyCommands do not change anything and are not recorded in the audit log
+var readOnlyCommands = []string{"status", "help", "list", "download", "logs", "modpack"}
+
 // Check channel and handle initial validation
 func listenToSlashCommands(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if i.Type != discordgo.InteractionApplicationCommand {
@@ -53,9 +61,16 @@ func listenToSlashCommands(s *discordgo.Session, i *discordgo.InteractionCreate)
 	cmd := i.ApplicationCommandData().Name
 	if handler, ok := handlers[cmd]; ok {
 		data := EmbedData{Title: "Command Error", Color: 0xFF0000}
-		if err := handler(s, i, data); err != nil {
+		err := handler(s, i, data)
+		if errors.Is(err, errAwaitingConfirmation) {
+			return // recorded once the user answered
+		}
+		if err != nil {
 			logger.Discord.Error("Error handling " + cmd + ": " + err.Error())
 		}
+		if !slices.Contains(readOnlyCommands, cmd) {
+			recordInteraction(i, "discord /"+cmd, commandOptionParams(i), err)
+		}
 	}
 }
 
@@ -70,6 +85,30 @@ func respond(s *discordgo.Session, i *discordgo.InteractionCreate, embed EmbedDa
 }
 
 func handleStart(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
+	// mods that keep throwing exceptions on this build are likely to break the server, so starting with them needs a confirmation
+	if flagged := modding.GetFlaggedMods(); len(flagged) > 0 {
+		var lines []string
+		for _, mod := range flagged {
+			name := mod.Name
+			if name == "" {
+				name = mod.Folder
+			}
+			lines = append(lines, fmt.Sprintf("%s: %d errors", name, mod.Errors))
+		}
+		data.Title, data.Color = "Server Control", 0xFFA500
+		data.Description = fmt.Sprintf("⚠️ %d mod(s) are likely incompatible with build %s. Start the server anyway?", len(flagged), flagged[0].BuildID)
+		data.Fields = []EmbedField{{Name: "Flagged mods", Value: strings.Join(lines, "\n")}}
+		return askForConfirmation(s, i, data, "🟢 Start anyway", func(s *discordgo.Session, ci *discordgo.InteractionCreate, progress *progressMessage) error {
+			if err := gamemgr.InternalStartServer(); err != nil {
+				progress.Update(0xFF0000, EmbedField{Name: "Status", Value: "❌ Failed", Inline: true}, EmbedField{Name: "Error", Value: err.Error(), Inline: true})
+				return err
+			}
+			progress.Update(0x00FF00, EmbedField{Name: "Status", Value: "🕛 Starting with flagged mods", Inline: true})
+			SendMessageToEventLogChannel("🕛Start command received from " + interactionUserName(ci) + " despite flagged mods, Server is Starting...")
+			return nil
+		})
+	}
+
 	data.Title, data.Description, data.Color = "Server Control", "Starting the server...", 0x00FF00
 	data.Fields = []EmbedField{{Name: "Status", Value: "🕛 Recieved", Inline: true}}
 	if err := respond(s, i, data); err != nil {
@@ -81,14 +120,17 @@ func handleStart(s *discordgo.Session, i *discordgo.InteractionCreate, data Embe
 }
 
 func handleStop(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
-	data.Title, data.Description, data.Color = "Server Control", "Stopping the server...", 0xFF0000
-	data.Fields = []EmbedField{{Name: "Status", Value: "🕛 Recieved", Inline: true}}
-	if err := respond(s, i, data); err != nil {
-		return err
-	}
-	gamemgr.InternalStopServer()
-	SendMessageToEventLogChannel("🕛Stop command received, flatlining Server in 5 Seconds...")
-	return nil
+	data.Title, data.Description, data.Color = "Server Control", "Stop the gameserver? Connected players will be disconnected.", 0xFFA500
+	return askForConfirmation(s, i, data, "🔴 Stop server", func(s *discordgo.Session, ci *discordgo.InteractionCreate, progress *progressMessage) error {
+		progress.Update(0xFFA500, EmbedField{Name: "Status", Value: "🕛 Stopping...", Inline: true})
+		SendMessageToEventLogChannel("🕛Stop command received from " + interactionUserName(ci) + ", flatlining Server in 5 Seconds...")
+		if err := gamemgr.InternalStopServer(); err != nil {
+			progress.Update(0xFF0000, EmbedField{Name: "Status", Value: "❌ Failed", Inline: true}, EmbedField{Name: "Error", Value: err.Error(), Inline: true})
+			return err
+		}
+		progress.Update(0xFF0000, EmbedField{Name: "Status", Value: "✅ Server stopped", Inline: true})
+		return nil
+	})
 }
 
 func handleStatus(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
@@ -104,56 +146,76 @@ func handleStatus(s *discordgo.Session, i *discordgo.InteractionCreate, data Emb
 }
 
 func handleUpdate(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
-	thinkingData := EmbedData{
-		Title:       "🎮 Gameserver Update",
-		Description: "The Backend is processing the gameserver update via SteamCMD. Please wait, this may take a while...",
-		Color:       0xFFA500, // Orange color for in-progress
-	}
-
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{generateEmbed(thinkingData)},
-		},
-	})
-	if err != nil {
-		return err
-	}
 	data.Title = "🎮 Gameserver Update"
-	data.Description = "Gameserver update completed."
-	data.Color = 0x00FF00 // Green for completion (will adjust if error)
-
-	_, err = steamcmd.InstallAndRunSteamCMD()
-
-	data.Fields = []EmbedField{
-		{Name: "Update Status:", Value: map[bool]string{true: "🟢 Success", false: "🔴 Failed"}[err == nil], Inline: true},
-	}
-	if err != nil {
-		data.Color = 0xFF0000 // Red for error
-		data.Fields = append(data.Fields, EmbedField{Name: "Error:", Value: err.Error(), Inline: true})
-	}
+	data.Description = "Update the gameserver via SteamCMD? A running server will be stopped for the duration of the update."
+	data.Color = 0xFFA500
+	return askForConfirmation(s, i, data, "♻️ Update", func(s *discordgo.Session, ci *discordgo.InteractionCreate, progress *progressMessage) error {
+		progress.Update(0xFFA500, EmbedField{Name: "Update Status:", Value: "🕛 Starting SteamCMD...", Inline: true})
+		SendMessageToEventLogChannel("♻️ Gameserver update requested by " + interactionUserName(ci))
+
+		queued := steamcmd.StartGameUpdate()
+
+		// keep the message alive with what SteamCMD reports
+		stopTicker := progress.Ticker(10*time.Second, func(elapsed time.Duration) {
+			status := "🕑 Queued behind other SteamCMD jobs..."
+			update, started := steamcmd.GetUpdateJob(queued.ID)
+			if started {
+				status = "🔄 SteamCMD is " + update.Progress.State + "..."
+			}
+			progress.Update(0xFFA500,
+				EmbedField{Name: "Update Status:", Value: status, Inline: true},
+				EmbedField{Name: "Progress:", Value: fmt.Sprintf("%.1f%%", update.Progress.Percent), Inline: true},
+				EmbedField{Name: "Elapsed:", Value: elapsed.Round(time.Second).String(), Inline: true},
+			)
+		})
+		finished, _ := jobs.Wait(queued.ID)
+		stopTicker()
+
+		job, ok := steamcmd.GetUpdateJob(queued.ID)
+		if !ok {
+			progress.Update(0xFF0000,
+				EmbedField{Name: "Update Status:", Value: "🔴 " + string(finished.State), Inline: true},
+				EmbedField{Name: "Error:", Value: finished.Error, Inline: false},
+			)
+			return fmt.Errorf("update job %s: %s", finished.State, finished.Error)
+		}
 
-	// Edit the original message with "update completed" embed
-	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Embeds: &[]*discordgo.MessageEmbed{generateEmbed(data)},
+		succeeded := job.Status == steamcmd.JobSucceeded
+		fields := []EmbedField{
+			{Name: "Update Status:", Value: map[bool]string{true: "🟢 Success", false: "🔴 Failed"}[succeeded], Inline: true},
+			{Name: "Duration:", Value: job.Duration, Inline: true},
+			{Name: "Build:", Value: job.OldBuildID + " → " + job.NewBuildID, Inline: true},
+		}
+		color := 0x00FF00 // Green for completion
+		if !succeeded {
+			color = 0xFF0000 // Red for error
+			fields[2] = EmbedField{Name: "Cause:", Value: job.ErrorClass, Inline: true}
+			fields = append(fields, EmbedField{Name: "Error:", Value: job.Error, Inline: false})
+		}
+		progress.Update(color, fields...)
+		if !succeeded {
+			return fmt.Errorf("%s: %s", job.ErrorClass, job.Error)
+		}
+		return nil
 	})
-	return err
 }
 
 func handleHelp(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
 	data.Title, data.Description, data.Color = "Command Help", "Available Commands:", 0x1E90FF
 	data.Fields = []EmbedField{
 		{Name: "/start", Value: "Starts the server"},
-		{Name: "/stop", Value: "Stops the server"},
+		{Name: "/stop", Value: "Stops the server (asks for confirmation)"},
 		{Name: "/status", Value: "Gets the running status of the gameserver process"},
-		{Name: "/update", Value: "Updates the gameserver via SteamCMD"},
-		{Name: "/list [limit]", Value: "Lists recent backups (default: 5)"},
-		{Name: "/restore <index>", Value: "Restores a backup"},
+		{Name: "/update", Value: "Updates the gameserver via SteamCMD (asks for confirmation)"},
+		{Name: "/list [limit]", Value: "Opens the backup browser to download, restore or pin backups (default: 5)"},
+		{Name: "/restore <index>", Value: "Restores a backup (asks for confirmation)"},
 		{Name: "/download [index]", Value: "Downloads a backup (most recent if no index)"},
-		{Name: "/bansteamid <SteamID>", Value: "Bans a player"},
+		{Name: "/bansteamid <SteamID>", Value: "Bans a player (asks for confirmation)"},
 		{Name: "/unbansteamid <SteamID>", Value: "Unbans a player"},
 		{Name: "/command <command>", Value: "Sends a command to the gameserver console"},
 		{Name: "/announce <message>", Value: "Broadcasts an announcement to all in-game players (via announce cmd)"},
+		{Name: "/logs [last]", Value: "Uploads the console output of the last N minutes as a compressed .log file (default: 10, max: 60)"},
+		{Name: "/modpack", Value: "Lists the mods players need to join, with workshop links"},
 		{Name: "/help", Value: "Shows this help"},
 	}
 	return respond(s, i, data)
@@ -166,23 +228,57 @@ func handleRestore(s *discordgo.Session, i *discordgo.InteractionCreate, data Em
 		data.Fields = []EmbedField{{Name: "Error", Value: "Please provide a valid number", Inline: true}}
 		return respond(s, i, data)
 	}
-	data.Title, data.Description, data.Color = "Backup Restore", fmt.Sprintf("Restoring backup #%d...", index), 0xFFA500
-	data.Fields = []EmbedField{{Name: "Status", Value: "🕛 Recieved", Inline: true}}
-	if err := respond(s, i, data); err != nil {
-		return err
+	data.Title, data.Description, data.Color = "Backup Restore", fmt.Sprintf("Restore backup #%d? The server will be stopped and the current world state will be replaced.", index), 0xFFA500
+	backup, ok := findBackup(index)
+	if ok {
+		data.Fields = []EmbedField{{Name: "Saved", Value: backup.SaveTime.Format("January 2, 2006, 3:04 PM"), Inline: true}}
+	} else {
+		data.Title, data.Description, data.Color = "Restore Failed", fmt.Sprintf("Backup #%d does not exist", index), 0xFF0000
+		data.Fields = []EmbedField{{Name: "Hint", Value: "Use /list to see the available backups", Inline: true}}
+		return respond(s, i, data)
 	}
-	gamemgr.InternalStopServer()
-	if err := backupmgr.GlobalBackupManager.RestoreBackup(index); err != nil {
+	return askForConfirmation(s, i, data, "⏪ Restore", func(s *discordgo.Session, ci *discordgo.InteractionCreate, progress *progressMessage) error {
+		return restoreBackupWithProgress(backup, interactionUserName(ci), progress)
+	})
+}
+
+// restoreBackupWithProgress stops the server, restores the backup and starts the server again, reporting each step.
+// It restores the backup file shown in the prompt, even if newer backups shifted the indices meanwhile.
+func restoreBackupWithProgress(backup backupmgr.BackupSaveFile, requestedBy string, progress *progressMessage) error {
+	index := backup.Index
+	progress.Update(0xFFA500, EmbedField{Name: "Status", Value: fmt.Sprintf("🕛 Stopping server and restoring backup #%d...", index), Inline: true})
+	if job, _ := jobs.Wait(backupmgr.GlobalBackupManager.StartRestoreFile(backup).ID); job.State != jobs.StateSucceeded {
+		err := errors.New(job.Error)
+		progress.Update(0xFF0000, EmbedField{Name: "Status", Value: "❌ Failed", Inline: true}, EmbedField{Name: "Error", Value: err.Error(), Inline: false})
 		SendMessageToControlChannel(fmt.Sprintf("❌Failed to restore backup %d: %v", index, err))
 		SendMessageToEventLogChannel("⚠️Restore command failed")
-		return nil
+		return err
 	}
-	SendMessageToControlChannel(fmt.Sprintf("✅Backup %d restored, Starting Server...", index))
+	progress.Update(0xFFA500, EmbedField{Name: "Status", Value: fmt.Sprintf("✅ Backup #%d restored, starting server...", index), Inline: true})
+	SendMessageToEventLogChannel(fmt.Sprintf("⏪ Backup %d restored by %s", index, requestedBy))
 	time.Sleep(5 * time.Second)
-	gamemgr.InternalStartServer()
+	if err := gamemgr.InternalStartServer(); err != nil {
+		progress.Update(0xFF0000, EmbedField{Name: "Status", Value: fmt.Sprintf("⚠️ Backup #%d restored, but the server failed to start", index), Inline: true}, EmbedField{Name: "Error", Value: err.Error(), Inline: false})
+		return err
+	}
+	progress.Update(0x00FF00, EmbedField{Name: "Status", Value: fmt.Sprintf("✅ Backup #%d restored, server is starting", index), Inline: true})
 	return nil
 }
 
+// findBackup looks up a backup by index
+func findBackup(index int) (backupmgr.BackupSaveFile, bool) {
+	backups, err := backupmgr.GlobalBackupManager.ListBackups(0)
+	if err != nil {
+		return backupmgr.BackupSaveFile{}, false
+	}
+	for _, b := range backups {
+		if b.Index == index {
+			return b, true
+		}
+	}
+	return backupmgr.BackupSaveFile{}, false
+}
+
 const maxDiscordFileSize = 10 * 1024 * 1024 // 10MB Discord file upload limit
 
 func handleDownload(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
@@ -213,6 +309,40 @@ func handleDownload(s *discordgo.Session, i *discordgo.InteractionCreate, data E
 	return nil
 }
 
+func handleLogs(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
+	minutes := 10
+	if len(i.ApplicationCommandData().Options) > 0 {
+		minutes = int(i.ApplicationCommandData().Options[0].IntValue())
+	}
+	maxMinutes := int(logHistoryRetention / time.Minute)
+	if minutes < 1 || minutes > maxMinutes {
+		data.Title, data.Description = "Logs", fmt.Sprintf("last must be between 1 and %d minutes", maxMinutes)
+		return respond(s, i, data)
+	}
+
+	text, from, lines := recentLogLines(time.Duration(minutes) * time.Minute)
+	if lines == 0 {
+		data.Title, data.Description, data.Color = "Logs", fmt.Sprintf("No console output in the last %d minutes", minutes), 0xFFD700
+		return respond(s, i, data)
+	}
+
+	file, err := gzipLogFile(text, from)
+	if err != nil {
+		data.Title, data.Description = "Logs", "Failed to prepare log file"
+		data.Fields = []EmbedField{{Name: "Error", Value: err.Error(), Inline: true}}
+		return respond(s, i, data)
+	}
+
+	data.Title, data.Description, data.Color = "🗒️ Console Logs", fmt.Sprintf("Console output of the last %d minutes (%d lines, unfiltered)", minutes, lines), 0x1E90FF
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{generateEmbed(data)},
+			Files:  []*discordgo.File{file},
+		},
+	})
+}
+
 func sendBackupToChannel(s *discordgo.Session, channelID string, index int) {
 	backupData, err := backupmgr.GlobalBackupManager.GetBackupFileData(index)
 	if err != nil {
@@ -255,67 +385,38 @@ func handleList(s *discordgo.Session, i *discordgo.InteractionCreate, data Embed
 		}
 	}
 
-	backups, err := backupmgr.GlobalBackupManager.ListBackups(limit)
+	embed, components, err := buildBackupBrowser(backupBrowserState{limit: limit, selected: -1})
 	if err != nil {
 		data.Title, data.Description = "List Failed", "Error fetching backups"
 		data.Fields = []EmbedField{{Name: "Error", Value: "Failed to fetch backup list", Inline: true}}
 		return respond(s, i, data)
 	}
-	if len(backups) == 0 {
-		data.Title, data.Description, data.Color = "Backup List", "No backups found", 0xFFD700
-		return respond(s, i, data)
-	}
-
-	sort.Slice(backups, func(i, j int) bool { return backups[i].SaveTime.After(backups[j].SaveTime) })
-	batchSize := 20
-	embeds := []*discordgo.MessageEmbed{}
-	for start := 0; start < len(backups); start += batchSize {
-		end := start + batchSize
-		if end > len(backups) {
-			end = len(backups)
-		}
-		fields := make([]EmbedField, end-start)
-		for j, b := range backups[start:end] {
-			fields[j] = EmbedField{Name: fmt.Sprintf("📂 Backup #%d", b.Index), Value: b.SaveTime.Format("January 2, 2006, 3:04 PM")}
-		}
-		embeds = append(embeds, generateEmbed(EmbedData{
-			Title: "📜 Backup Archives", Description: fmt.Sprintf("Showing %d-%d of %d backups", start+1, end, len(backups)),
-			Color: 0xFFD700, Fields: fields,
-		}))
-	}
-
-	// Add download buttons if showing 5 or fewer backups
-	var components []discordgo.MessageComponent
-	if len(backups) <= 5 {
-		var buttons []discordgo.MessageComponent
-		for _, b := range backups {
-			buttons = append(buttons, discordgo.Button{
-				Label:    fmt.Sprintf("📥 Download #%d", b.Index),
-				Style:    discordgo.SecondaryButton,
-				CustomID: fmt.Sprintf("%s%d", ButtonDownloadBackupPfx, b.Index),
-			})
-		}
-		components = append(components, discordgo.ActionsRow{Components: buttons})
-	}
 
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Embeds:     []*discordgo.MessageEmbed{embeds[0]},
+			Embeds:     []*discordgo.MessageEmbed{embed},
 			Components: components,
 		},
-	}); err != nil {
-		return err
-	}
-	for _, embed := range embeds[1:] {
-		time.Sleep(500 * time.Millisecond)
-		s.ChannelMessageSendEmbed(i.ChannelID, embed)
-	}
-	return nil
+	})
 }
 
 func handleBan(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
-	return handleBanUnban(s, i, data, banSteamID, "Banned", "Ban Failed", 0xFF0000)
+	if len(i.ApplicationCommandData().Options) == 0 {
+		return handleBanUnban(s, i, data, banSteamID, "Banned", "Ban Failed", 0xFF0000)
+	}
+	steamID := i.ApplicationCommandData().Options[0].StringValue()
+	data.Title, data.Description, data.Color = "Ban Player", fmt.Sprintf("Ban SteamID %s? The ban takes effect after the next server restart.", steamID), 0xFFA500
+	data.Fields = []EmbedField{{Name: "Profile", Value: fmt.Sprintf("https://steamcommunity.com/profiles/%s/", steamID), Inline: false}}
+	return askForConfirmation(s, i, data, "🔨 Ban", func(s *discordgo.Session, ci *discordgo.InteractionCreate, progress *progressMessage) error {
+		if err := banSteamID(steamID); err != nil {
+			progress.Update(0xFF0000, EmbedField{Name: "Status", Value: "❌ Ban Failed", Inline: true}, EmbedField{Name: "Error", Value: err.Error(), Inline: true})
+			return err
+		}
+		progress.Update(0xFF0000, EmbedField{Name: "Status", Value: fmt.Sprintf("✅ SteamID %s has been banned", steamID), Inline: true})
+		SendMessageToEventLogChannel(fmt.Sprintf("🔨 SteamID %s banned by %s", steamID, interactionUserName(ci)))
+		return nil
+	})
 }
 
 func handleUnban(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
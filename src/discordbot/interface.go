@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 
 	"github.com/bwmarrin/discordgo"
@@ -31,6 +32,10 @@ func InitializeDiscordBot() {
 
 	// Set intents
 	config.DiscordSession.Identify.Intents = discordgo.IntentsGuildMessageReactions
+	if config.GetChatBridgeChannelID() != "" {
+		// Reading message text for the chat bridge requires the privileged Message Content intent, enable it in the Discord developer portal.
+		config.DiscordSession.Identify.Intents |= discordgo.IntentsGuildMessages | discordgo.IntentMessageContent
+	}
 
 	logger.Discord.Info("Starting Discord integration...")
 	//logger.Discord.Debug("Discord token: " + config.GetDiscordToken())
@@ -38,6 +43,7 @@ func InitializeDiscordBot() {
 	logger.Discord.Debug("EventLogChannelID: " + config.GetEventLogChannelID())
 	logger.Discord.Debug("StatusPanelChannelID: " + config.GetStatusPanelChannelID())
 	logger.Discord.Debug("LogChannelID: " + config.GetLogChannelID())
+	logger.Discord.Debug("ChatBridgeChannelID: " + config.GetChatBridgeChannelID())
 
 	// Open session first
 	err = config.DiscordSession.Open()
@@ -49,9 +55,13 @@ func InitializeDiscordBot() {
 	// Register handlers and commands after session is open
 	config.DiscordSession.AddHandler(listenToDiscordReactions)
 	config.DiscordSession.AddHandler(listenToSlashCommands)
-	config.DiscordSession.AddHandler(handlePanelButtonInteraction)    // Handle button interactions (server info + players panel)
-	config.DiscordSession.AddHandler(handleDownloadButtonInteraction) // Handle download button interactions
+	config.DiscordSession.AddHandler(handlePanelButtonInteraction)        // Handle button interactions (server info + players panel)
+	config.DiscordSession.AddHandler(handleDownloadButtonInteraction)     // Handle download button interactions
+	config.DiscordSession.AddHandler(handleConfirmationButtonInteraction) // Handle confirm/cancel buttons of destructive commands
+	config.DiscordSession.AddHandler(handleBackupBrowserInteraction)      // Handle paging, selection and actions of the backup browser
+	config.DiscordSession.AddHandler(listenToChatBridgeMessages)          // Relay chat bridge channel messages in-game
 	registerSlashCommands(config.DiscordSession)
+	audit.RegisterMirror(mirrorAuditEntry)
 
 	logger.Discord.Info("Bot is now running.")
 	SendMessageToEventLogChannel("🤖 SSUI Version " + config.GetVersion() + " connected to Discord.")
@@ -1,36 +1,109 @@
 package discordbot
 
 import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+/*
+Console log forwarding to LogChannelID.
+- LogChannelIncludePatterns / LogChannelExcludePatterns decide which console lines are forwarded
+- Stream mode (LogChannelDigestMinutes = 0) posts the buffer in DiscordCharBufferSize chunks every few seconds
+- Digest mode uploads the buffer as a gzip compressed .log attachment every LogChannelDigestMinutes minutes
+- Independent of the filters, the last logHistoryRetention of console output is kept for /logs last:<minutes>
+*/
+
+const (
+	logHistoryRetention = 60 * time.Minute
+	logHistoryMaxBytes  = 16 * 1024 * 1024 // upper bound for the /logs history, oldest lines are dropped first
+	logDigestMaxBytes   = 32 * 1024 * 1024 // upload a digest early if the buffer grows beyond this
+	logStreamMaxChunks  = 3                // stream mode keeps at most this many messages of unsent text, so a Discord outage does not end in a burst of messages
+)
+
+type logHistoryLine struct {
+	time time.Time
+	line string
+}
+
+var (
+	logBufferMu      sync.Mutex
+	logFlushMu       sync.Mutex // one flush sends at a time, so requeued lines keep their order
+	logDigestStarted time.Time  // start of the current digest period
+
+	logHistoryMu    sync.Mutex
+	logHistory      []logHistoryLine
+	logHistoryBytes int
+
+	logFiltersMu  sync.Mutex
+	logFiltersKey string
+	logIncludeRes []*regexp.Regexp
+	logExcludeRes []*regexp.Regexp
 )
 
 // PassLogMessageToDiscordLogBuffer is called from the detection module to add a log message to the buffer.
 func PassLogStreamToDiscordLogBuffer(logMessage string) {
+	recordLogHistory(logMessage, time.Now())
+
+	if !passesLogChannelFilters(logMessage) {
+		return
+	}
+
+	logBufferMu.Lock()
+	if LogMessageBuffer == "" {
+		logDigestStarted = time.Now()
+	}
 	LogMessageBuffer += logMessage + "\n"
-	if len(LogMessageBuffer) >= config.GetDiscordCharBufferSize() && config.GetIsDiscordEnabled() {
+	bufferLen := len(LogMessageBuffer)
+	logBufferMu.Unlock()
+
+	if !config.GetIsDiscordEnabled() {
+		return
+	}
+	if config.GetLogChannelDigestMinutes() > 0 {
+		if bufferLen >= logDigestMaxBytes {
+			flushLogBufferToDiscord()
+		}
+		return
+	}
+	if bufferLen >= config.GetDiscordCharBufferSize() {
 		flushLogBufferToDiscord()
 	}
 }
 
 // FlushLogBufferToDiscord flushes the log buffer to Discord periodically with a configurable "DiscordCharBufferSize" character limit per message.
+// In digest mode, the buffer is only uploaded once the digest interval has passed.
+// The buffer is taken under logBufferMu and sent without it, so a slow Discord does not block log writers; what failed to send is requeued.
 func flushLogBufferToDiscord() {
-	if len(LogMessageBuffer) == 0 {
-		return // No messages to send
+	if !logFlushMu.TryLock() {
+		return // another flush is sending, the next one picks up what arrived meanwhile
 	}
+	defer logFlushMu.Unlock()
 
-	if config.GetLogChannelID() == "" {
-		LogMessageBuffer = ""
-		return // No log channel ID set, skip and set buffer to empty
+	message, started, ok := takeLogBuffer()
+	if !ok {
+		return
 	}
 
-	if !config.GetIsDiscordEnabled() || config.DiscordSession == nil {
+	if config.GetLogChannelDigestMinutes() > 0 {
+		content := fmt.Sprintf("🗒️ Console log digest <t:%d:t> - <t:%d:t>", started.Unix(), time.Now().Unix())
+		if err := sendLogAttachment(config.GetLogChannelID(), content, message, started); err != nil {
+			logger.Discord.Error("Error sending log digest to Discord: " + err.Error())
+			requeueLogBuffer(message, started)
+		}
 		return
 	}
 
-	discordMaxMessageLength := config.DiscordCharBufferSize
-
-	message := LogMessageBuffer
+	discordMaxMessageLength := config.GetDiscordCharBufferSize()
 
 	for len(message) > 0 {
 		// Determine how much of the message we can send
@@ -45,13 +118,183 @@ func flushLogBufferToDiscord() {
 		_, err := config.DiscordSession.ChannelMessageSend(config.GetLogChannelID(), message[:chunkSize])
 		if err != nil {
 			logger.Discord.Error("Error sending log to Discord: " + err.Error())
-			break
+			requeueLogBuffer(message, started)
+			return
 		}
 
 		// Move to the next chunk
 		message = message[chunkSize:]
 	}
+}
+
+// takeLogBuffer empties the log buffer and returns its content if it is due to be sent
+func takeLogBuffer() (string, time.Time, bool) {
+	logBufferMu.Lock()
+	defer logBufferMu.Unlock()
+
+	if len(LogMessageBuffer) == 0 {
+		return "", time.Time{}, false // No messages to send
+	}
+
+	if config.GetLogChannelID() == "" {
+		LogMessageBuffer = ""
+		return "", time.Time{}, false // No log channel ID set, skip and set buffer to empty
+	}
 
-	// Clear the buffer after sending
+	if !config.GetIsDiscordEnabled() || config.DiscordSession == nil {
+		return "", time.Time{}, false
+	}
+
+	digestMinutes := config.GetLogChannelDigestMinutes()
+	if digestMinutes > 0 && time.Since(logDigestStarted) < time.Duration(digestMinutes)*time.Minute && len(LogMessageBuffer) < logDigestMaxBytes {
+		return "", time.Time{}, false
+	}
+	message, started := LogMessageBuffer, logDigestStarted
 	LogMessageBuffer = ""
+	return message, started, true
+}
+
+// requeueLogBuffer puts unsent log text back in front of what arrived while it was sent.
+// The buffer is capped at logDigestMaxBytes in digest mode and logStreamMaxChunks messages in stream mode,
+// so an unreachable Discord drops the oldest lines instead of growing it forever.
+func requeueLogBuffer(unsent string, started time.Time) {
+	limit := logDigestMaxBytes
+	if config.GetLogChannelDigestMinutes() == 0 {
+		limit = logStreamMaxChunks * max(config.GetDiscordCharBufferSize(), 1)
+	}
+
+	logBufferMu.Lock()
+	defer logBufferMu.Unlock()
+
+	buffer := unsent + LogMessageBuffer
+	if excess := len(buffer) - limit; excess > 0 {
+		cut := excess
+		if newline := strings.IndexByte(buffer[excess:], '\n'); newline >= 0 {
+			cut += newline + 1
+		}
+		logger.Discord.Warn(fmt.Sprintf("Dropping %d bytes of console log that could not be sent to Discord", cut))
+		buffer = buffer[cut:]
+	}
+	LogMessageBuffer = buffer
+	if !started.IsZero() {
+		logDigestStarted = started
+	}
+}
+
+// passesLogChannelFilters reports whether a console line should be forwarded to the log channel.
+// Filters are recompiled whenever the configured patterns change; invalid patterns are logged and ignored.
+func passesLogChannelFilters(line string) bool {
+	include := config.GetLogChannelIncludePatterns()
+	exclude := config.GetLogChannelExcludePatterns()
+	if len(include) == 0 && len(exclude) == 0 {
+		return true
+	}
+
+	logFiltersMu.Lock()
+	key := strings.Join(include, "\x00") + "\x01" + strings.Join(exclude, "\x00")
+	if key != logFiltersKey {
+		logIncludeRes, logExcludeRes = compileLogFilters(include), compileLogFilters(exclude)
+		logFiltersKey = key
+	}
+	includeRes, excludeRes := logIncludeRes, logExcludeRes
+	logFiltersMu.Unlock()
+
+	for _, re := range excludeRes {
+		if re.MatchString(line) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, re := range includeRes {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileLogFilters(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			logger.Discord.Warn("Ignoring invalid log channel filter " + pattern + ": " + err.Error())
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled
+}
+
+// recordLogHistory keeps the unfiltered console output of the last logHistoryRetention for /logs
+func recordLogHistory(line string, now time.Time) {
+	logHistoryMu.Lock()
+	defer logHistoryMu.Unlock()
+
+	logHistory = append(logHistory, logHistoryLine{time: now, line: line})
+	logHistoryBytes += len(line) + 1
+
+	drop := 0
+	for drop < len(logHistory) && (now.Sub(logHistory[drop].time) > logHistoryRetention || logHistoryBytes > logHistoryMaxBytes) {
+		logHistoryBytes -= len(logHistory[drop].line) + 1
+		drop++
+	}
+	logHistory = logHistory[drop:] // append reallocates eventually, which releases the dropped lines
+}
+
+// recentLogLines returns the console output of the last d (newest line last), the time of its first line and the number of lines
+func recentLogLines(d time.Duration) (string, time.Time, int) {
+	logHistoryMu.Lock()
+	defer logHistoryMu.Unlock()
+
+	since := time.Now().Add(-d)
+	start := len(logHistory)
+	for start > 0 && !logHistory[start-1].time.Before(since) {
+		start--
+	}
+	if start == len(logHistory) {
+		return "", time.Time{}, 0
+	}
+
+	var b strings.Builder
+	for _, l := range logHistory[start:] {
+		b.WriteString(l.line)
+		b.WriteByte('\n')
+	}
+	return b.String(), logHistory[start].time, len(logHistory) - start
+}
+
+// gzipLogFile compresses log text into a discordgo file named after the start of the covered period
+func gzipLogFile(text string, from time.Time) (*discordgo.File, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(text)); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	if buf.Len() > maxDiscordFileSize {
+		return nil, fmt.Errorf("compressed log is too large to upload (%.2f MB > 10 MB limit)", float64(buf.Len())/(1024*1024))
+	}
+	return &discordgo.File{
+		Name:        fmt.Sprintf("console-%s.log.gz", from.Format("20060102-150405")),
+		ContentType: "application/gzip",
+		Reader:      &buf,
+	}, nil
+}
+
+// sendLogAttachment uploads log text as a compressed .log attachment to a channel
+func sendLogAttachment(channelID, content, text string, from time.Time) error {
+	file, err := gzipLogFile(text, from)
+	if err != nil {
+		return err
+	}
+	_, err = config.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: content,
+		Files:   []*discordgo.File{file},
+	})
+	return err
 }
@@ -0,0 +1,56 @@
+package discordbot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+func TestRequeueLogBufferKeepsOrderAndCap(t *testing.T) {
+	config.ConfigMu.Lock()
+	oldDigestMinutes := config.LogChannelDigestMinutes
+	config.LogChannelDigestMinutes = 60
+	config.ConfigMu.Unlock()
+	t.Cleanup(func() {
+		config.ConfigMu.Lock()
+		config.LogChannelDigestMinutes = oldDigestMinutes
+		config.ConfigMu.Unlock()
+		LogMessageBuffer, logDigestStarted = "", time.Time{}
+	})
+	started := time.Now().Add(-time.Hour)
+
+	// a line arrived while the failed send was in flight
+	LogMessageBuffer, logDigestStarted = "third\n", time.Now()
+	requeueLogBuffer("first\nsecond\n", started)
+	if LogMessageBuffer != "first\nsecond\nthird\n" || !logDigestStarted.Equal(started) {
+		t.Fatalf("unsent lines must go back in front, got %q from %v", LogMessageBuffer, logDigestStarted)
+	}
+
+	LogMessageBuffer = ""
+	requeueLogBuffer("old line\n"+strings.Repeat("x", logDigestMaxBytes-1)+"\n", started)
+	if len(LogMessageBuffer) > logDigestMaxBytes || strings.HasPrefix(LogMessageBuffer, "old line") {
+		t.Fatalf("the buffer must drop its oldest lines beyond the cap, got %d bytes", len(LogMessageBuffer))
+	}
+}
+
+func TestRequeueLogBufferStreamModeKeepsFewChunks(t *testing.T) {
+	config.ConfigMu.Lock()
+	oldDigestMinutes, oldBufferSize := config.LogChannelDigestMinutes, config.DiscordCharBufferSize
+	config.LogChannelDigestMinutes, config.DiscordCharBufferSize = 0, 1000
+	config.ConfigMu.Unlock()
+	t.Cleanup(func() {
+		config.ConfigMu.Lock()
+		config.LogChannelDigestMinutes, config.DiscordCharBufferSize = oldDigestMinutes, oldBufferSize
+		config.ConfigMu.Unlock()
+		LogMessageBuffer, logDigestStarted = "", time.Time{}
+	})
+
+	// an hour of unsent lines must not turn into thousands of messages once Discord is back
+	LogMessageBuffer = "newest\n"
+	requeueLogBuffer(strings.Repeat(strings.Repeat("x", 99)+"\n", 10000), time.Time{})
+	if len(LogMessageBuffer) > logStreamMaxChunks*1000 || !strings.HasSuffix(LogMessageBuffer, "newest\n") {
+		t.Fatalf("stream mode must keep only the newest %d chunks, got %d bytes", logStreamMaxChunks, len(LogMessageBuffer))
+	}
+}
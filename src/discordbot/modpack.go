@@ -0,0 +1,81 @@
+package discordbot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+	"github.com/bwmarrin/discordgo"
+)
+
+const maxEmbedDescription = 4096
+
+// modPackEmbed lists the mods players need to join, in load order and with their workshop links
+func modPackEmbed(pack modding.ModPack) EmbedData {
+	data := EmbedData{Title: "🧩 Mods required to join " + pack.Server, Color: 0x1E90FF}
+	if len(pack.Mods) == 0 {
+		data.Description = "This server runs without mods."
+		return data
+	}
+
+	header := fmt.Sprintf("%d mods in load order. Subscribe to them on the Steam Workshop before joining.\n\n", len(pack.Mods))
+	var lines []string
+	notOnWorkshop := 0
+	for i, mod := range pack.Mods {
+		name := mod.Name
+		if name == "" {
+			name = mod.Folder
+		}
+		line := fmt.Sprintf("%d. %s", i+1, name)
+		if mod.WorkshopURL != "" {
+			line = fmt.Sprintf("%d. [%s](%s)", i+1, name, mod.WorkshopURL)
+		} else {
+			notOnWorkshop++
+			line += " *(not on the workshop)*"
+		}
+		if mod.Version != "" {
+			line += " v" + mod.Version
+		}
+		lines = append(lines, line)
+	}
+
+	description := header
+	for i, line := range lines {
+		more := fmt.Sprintf("…and %d more", len(lines)-i)
+		if len(description)+len(line)+len(more)+2 > maxEmbedDescription {
+			description += more
+			break
+		}
+		description += line + "\n"
+	}
+	data.Description = strings.TrimSpace(description)
+	data.Fields = []EmbedField{{Name: "Game build", Value: pack.BuildID, Inline: true}}
+	if notOnWorkshop > 0 {
+		data.Fields = append(data.Fields, EmbedField{Name: "Not on the workshop", Value: fmt.Sprintf("%d mod(s), ask an admin for the mod pack zip", notOnWorkshop), Inline: true})
+	}
+	return data
+}
+
+// SendModPackEmbed posts the list of mods players need to the event log channel
+func SendModPackEmbed(pack modding.ModPack) error {
+	if !config.GetIsDiscordEnabled() || config.GetEventLogChannelID() == "" {
+		return errors.New("discord is not enabled or has no event log channel")
+	}
+	if config.DiscordSession == nil {
+		return errors.New("discord is enabled but the session is not initialized")
+	}
+	_, err := config.DiscordSession.ChannelMessageSendEmbed(config.GetEventLogChannelID(), generateEmbed(modPackEmbed(pack)))
+	return err
+}
+
+func handleModPack(s *discordgo.Session, i *discordgo.InteractionCreate, data EmbedData) error {
+	pack, err := modding.BuildModPack()
+	if err != nil {
+		data.Description = "Failed to read the mod list"
+		data.Fields = []EmbedField{{Name: "Error", Value: err.Error()}}
+		return respond(s, i, data)
+	}
+	return respond(s, i, modPackEmbed(pack))
+}
@@ -30,7 +30,7 @@ func registerSlashCommands(s *discordgo.Session) {
 		},
 		{
 			Name:        "update",
-			Description: "Update the gameserver via SteamCMD. Feedback will take a while, please be patient.",
+			Description: "Update the gameserver via SteamCMD. Asks for confirmation and reports progress.",
 		},
 		{
 			Name:        "command",
@@ -56,6 +56,18 @@ func registerSlashCommands(s *discordgo.Session) {
 				},
 			},
 		},
+		{
+			Name:        "logs",
+			Description: "Upload recent console output as a compressed .log file",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "last",
+					Description: "Minutes of console output to upload (default: 10, max: 60)",
+					Required:    false,
+				},
+			},
+		},
 		{
 			Name:        "restore",
 			Description: "Restore a backup at the specified index",
@@ -70,12 +82,12 @@ func registerSlashCommands(s *discordgo.Session) {
 		},
 		{
 			Name:        "list",
-			Description: "List the most recent backups",
+			Description: "Browse backups to download, restore or pin them",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
 					Type:        discordgo.ApplicationCommandOptionString,
 					Name:        "limit",
-					Description: "Number of backups to list or 'all' (default: 5)",
+					Description: "Number of most recent backups to browse or 'all' (default: 5)",
 					Required:    false,
 				},
 			},
@@ -116,6 +128,10 @@ func registerSlashCommands(s *discordgo.Session) {
 				},
 			},
 		},
+		{
+			Name:        "modpack",
+			Description: "List the mods players need to join, with workshop links",
+		},
 	}
 
 	logger.Discord.Info("Checking and registering slash commands with Discord...")
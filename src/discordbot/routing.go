@@ -0,0 +1,97 @@
+package discordbot
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+/*
+Notification routing for detection events.
+- config.DiscordRoutingRules map event types (built-in or custom detection types) to channels, optionally pinging a role
+- Channel IDs are global, so rules can target channels in every guild the bot has joined
+- Events no rule matches keep going to the EventLog channel, so an empty rule set behaves like before
+*/
+
+// eventRoute is one destination channel of an event with the roles to ping there
+type eventRoute struct {
+	channelID string
+	roleIDs   []string
+}
+
+// resolveEventRoutes returns the destination channels for an event type, deduplicated by channel and in rule order
+func resolveEventRoutes(eventType string, rules []config.DiscordRoutingRule) []eventRoute {
+	var routes []eventRoute
+	byChannel := make(map[string]int)
+	for _, rule := range rules {
+		if !ruleMatchesEvent(rule, eventType) {
+			continue
+		}
+		for _, channelID := range rule.ChannelIDs {
+			n, ok := byChannel[channelID]
+			if !ok {
+				n = len(routes)
+				byChannel[channelID] = n
+				routes = append(routes, eventRoute{channelID: channelID})
+			}
+			if rule.MentionRoleID != "" && !slices.Contains(routes[n].roleIDs, rule.MentionRoleID) {
+				routes[n].roleIDs = append(routes[n].roleIDs, rule.MentionRoleID)
+			}
+		}
+	}
+	return routes
+}
+
+func ruleMatchesEvent(rule config.DiscordRoutingRule, eventType string) bool {
+	for _, t := range rule.EventTypes {
+		if t == "*" || strings.EqualFold(t, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// SendEventMessage sends a detection event message to every channel routed for its event type,
+// or to the EventLog channel if no routing rule matches.
+func SendEventMessage(eventType, message string) {
+	sendEventMessage(eventType, message, nil)
+}
+
+// sendEventMessage is SendEventMessage, eventLogMentions are the mentions allowed when the message goes to the EventLog channel
+// (nil for the Discord defaults). Routed messages only ping the roles of their rules.
+func sendEventMessage(eventType, message string, eventLogMentions *discordgo.MessageAllowedMentions) {
+	if !config.GetIsDiscordEnabled() {
+		return
+	}
+
+	routes := resolveEventRoutes(eventType, config.GetDiscordRoutingRules())
+	if len(routes) == 0 {
+		sendMessageToEventLogChannel(message, eventLogMentions)
+		return
+	}
+
+	if config.DiscordSession == nil {
+		logger.Discord.Error("Discord Error: Discord is enabled but session is not initialized")
+		return
+	}
+
+	for _, route := range routes {
+		content := message
+		// only ping the roles from matching rules, never anything contained in the (log derived) message itself
+		allowedMentions := &discordgo.MessageAllowedMentions{Roles: route.roleIDs}
+		if len(route.roleIDs) > 0 {
+			mentions := make([]string, 0, len(route.roleIDs))
+			for _, roleID := range route.roleIDs {
+				mentions = append(mentions, "<@&"+roleID+">")
+			}
+			content = strings.Join(mentions, " ") + " " + message
+		}
+		if err := sendChunkedMessage(route.channelID, content, allowedMentions); err != nil {
+			logger.Discord.Error("Error sending " + eventType + " event to channel " + route.channelID + ": " + err.Error())
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package discordbot
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+func TestResolveEventRoutes(t *testing.T) {
+	rules := []config.DiscordRoutingRule{
+		{EventTypes: []string{"server_error"}, ChannelIDs: []string{"alerts", "admins"}, MentionRoleID: "oncall"},
+		{EventTypes: []string{"*"}, ChannelIDs: []string{"admins", "all"}},
+		{EventTypes: []string{"SERVER_ERROR"}, ChannelIDs: []string{"admins"}, MentionRoleID: "oncall"},
+		{EventTypes: []string{"SERVER_ERROR"}, ChannelIDs: []string{"admins"}, MentionRoleID: "leads"},
+	}
+	routes := resolveEventRoutes("SERVER_ERROR", rules)
+	if len(routes) != 3 || routes[0].channelID != "alerts" || routes[1].channelID != "admins" || routes[2].channelID != "all" {
+		t.Fatalf("expected one route per channel in rule order, got %+v", routes)
+	}
+	if !slices.Equal(routes[1].roleIDs, []string{"oncall", "leads"}) || len(routes[2].roleIDs) != 0 {
+		t.Errorf("roles must be merged per channel without duplicates, got %+v", routes)
+	}
+
+	if routes := resolveEventRoutes("PLAYER_JOINED", rules[:1]); len(routes) != 0 {
+		t.Errorf("unmatched events must go to the EventLog channel, got %+v", routes)
+	}
+}
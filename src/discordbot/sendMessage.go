@@ -34,6 +34,11 @@ func SendMessageToControlChannel(message string) {
 }
 
 func SendMessageToEventLogChannel(message string) {
+	sendMessageToEventLogChannel(message, nil)
+}
+
+// sendMessageToEventLogChannel sends a message to the EventLog channel, allowedMentions may be nil to keep the Discord defaults
+func sendMessageToEventLogChannel(message string, allowedMentions *discordgo.MessageAllowedMentions) {
 	if !config.GetIsDiscordEnabled() {
 		return
 	}
@@ -47,45 +52,41 @@ func SendMessageToEventLogChannel(message string) {
 		return
 	}
 
-	if len(message) <= 2000 {
-		//clearMessagesAboveLastN(config.EventLogChannelID, 10)
-		_, err := config.DiscordSession.ChannelMessageSend(config.GetEventLogChannelID(), message)
-		if err != nil {
-			logger.Discord.Error("Error sending message to EventLog channel: " + err.Error())
-		}
-		return
+	//clearMessagesAboveLastN(config.EventLogChannelID, 10)
+	if err := sendChunkedMessage(config.GetEventLogChannelID(), message, allowedMentions); err != nil {
+		logger.Discord.Error("Error sending message to EventLog channel: " + err.Error())
 	}
+}
 
+// sendChunkedMessage sends a message to a channel, split into several messages if it exceeds the Discord message limit.
+// allowedMentions may be nil to keep the Discord defaults.
+func sendChunkedMessage(channelID, message string, allowedMentions *discordgo.MessageAllowedMentions) error {
 	maxMessageLength := 2000 // Discord's message character limit
 
-	// Function to split the message into chunks and send each one in case the message (primilariy exception stack traces) exceeds the Discord message limit
+	// Split the message into chunks and send each one in case the message (primilariy exception stack traces) exceeds the Discord message limit
 	for len(message) > 0 {
+		chunk := message
 		if len(message) > maxMessageLength {
 			// Find a safe split point, for example, the last newline before the limit
 			splitIndex := strings.LastIndex(message[:maxMessageLength], "\n")
-			if splitIndex == -1 {
+			if splitIndex <= 0 {
 				splitIndex = maxMessageLength // No newline found, force split at max length
 			}
+			chunk = message[:splitIndex]
+		}
 
-			// Send the chunk
-			_, err := config.DiscordSession.ChannelMessageSend(config.GetEventLogChannelID(), message[:splitIndex])
-			if err != nil {
-				logger.Discord.Error("Error sending message to EventLog channel: " + err.Error())
-				return
-			}
-
-			// Remove the sent chunk from the message
-			message = message[splitIndex:]
-		} else {
-			// Send the remaining part of the message
-			_, err := config.DiscordSession.ChannelMessageSend(config.GetEventLogChannelID(), message)
-			if err != nil {
-				logger.Discord.Error("Error sending message to EventLog channel: " + err.Error())
-				return
-			}
-			break
+		_, err := config.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:         chunk,
+			AllowedMentions: allowedMentions,
+		})
+		if err != nil {
+			return err
 		}
+
+		// Remove the sent chunk from the message
+		message = message[len(chunk):]
 	}
+	return nil
 }
 
 // This function is used to clear messages above the last N messages in a channel. If you call this with 5, it will clear all messages in the channel besides the most recent 5.
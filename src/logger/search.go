@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+// Log sources for Search
+const (
+	SourceSSUI       = "ssui"       // ssui.log or <subsystem>.log, written when CreateSSUILogFile is on
+	SourceGameServer = "gameserver" // serverlog_<yyyyMMddHHmm>_<uuid>.log, one per server run, written when CreateGameServerLogFile is on
+)
+
+// searchMaxLineBytes cuts longer lines, so one huge line does not stop the search
+const searchMaxLineBytes = 1024 * 1024
+
+// ErrInvalidSearch is wrapped by the errors Search returns for bad options, any other error is a failure to read the logs
+var ErrInvalidSearch = errors.New("invalid log search")
+
+// SearchOptions filters Search. Zero values match everything.
+type SearchOptions struct {
+	Source       string // SourceSSUI (default) or SourceGameServer
+	Subsystem    string // SSUI subsystem, e.g. "WEB"
+	Level        string // DEBUG, INFO, WARN or ERROR, SSUI logs only
+	Pattern      *regexp.Regexp
+	Since, Until time.Time // game server lines carry no timestamps, their files are selected by the time of the server run
+	Limit        int       // keep the newest Limit matches
+}
+
+// SearchMatch is one matching log line
+type SearchMatch struct {
+	File      string    `json:"file"`
+	Line      int       `json:"line"`
+	Time      time.Time `json:"time,omitzero"`
+	Subsystem string    `json:"subsystem,omitempty"`
+	Level     string    `json:"level,omitempty"`
+	Text      string    `json:"text"`
+}
+
+// fileLinePattern parses lines written by writeToFile, see Logger.log
+var fileLinePattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}) \[([A-Z_]+)/([A-Z]+)\] `)
+
+// Search greps the persisted SSUI or game server logs, oldest match first. truncated reports that older matches were left out.
+func Search(opts SearchOptions) (matches []SearchMatch, truncated bool, err error) {
+	var files []string
+	switch opts.Source {
+	case "", SourceSSUI:
+		// the same files as writeToFile
+		file := config.GetLogFolder() + "ssui.log"
+		if opts.Subsystem != "" {
+			opts.Subsystem = strings.ToUpper(opts.Subsystem)
+			if _, ok := subsystemColors[opts.Subsystem]; !ok {
+				return nil, false, fmt.Errorf("%w: unknown subsystem %q", ErrInvalidSearch, opts.Subsystem)
+			}
+			file = getSubsystemLogPath(opts.Subsystem)
+		}
+		files = []string{file}
+	case SourceGameServer:
+		if files, err = gameServerLogFiles(opts.Since, opts.Until); err != nil {
+			return nil, false, err
+		}
+	default:
+		return nil, false, fmt.Errorf("%w: unknown log source %q, use %s or %s", ErrInvalidSearch, opts.Source, SourceSSUI, SourceGameServer)
+	}
+	opts.Level = strings.ToUpper(opts.Level)
+
+	for _, file := range files {
+		fileMatches, fileTruncated, err := searchFile(file, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		matches, truncated = append(matches, fileMatches...), truncated || fileTruncated
+		if opts.Limit > 0 && len(matches) > opts.Limit {
+			matches, truncated = slices.Delete(matches, 0, len(matches)-opts.Limit), true
+		}
+	}
+	return matches, truncated, nil
+}
+
+func searchFile(path string, opts SearchOptions) (matches []SearchMatch, truncated bool, err error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	ssui := opts.Source != SourceGameServer
+	var lineTime time.Time
+	var subsystem, level string
+	reader := bufio.NewReader(file)
+	for n := 1; ; n++ {
+		text, err := readLine(reader, searchMaxLineBytes)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if ssui {
+			// lines without a header (multi-line messages, Clean) belong to the previous one
+			if parts := fileLinePattern.FindStringSubmatch(text); parts != nil {
+				lineTime, _ = time.ParseInLocation(time.DateTime, parts[1], time.Local)
+				subsystem, level = parts[2], parts[3]
+			}
+			if !opts.Until.IsZero() && lineTime.After(opts.Until) {
+				break
+			}
+			if !opts.Since.IsZero() && lineTime.Before(opts.Since) ||
+				opts.Subsystem != "" && subsystem != opts.Subsystem ||
+				opts.Level != "" && level != opts.Level {
+				continue
+			}
+		}
+		if opts.Pattern != nil && !opts.Pattern.MatchString(text) {
+			continue
+		}
+		match := SearchMatch{File: filepath.Base(path), Line: n, Text: text}
+		if ssui {
+			match.Time, match.Subsystem, match.Level = lineTime, subsystem, level
+		}
+		matches = append(matches, match)
+		if opts.Limit > 0 && len(matches) > 2*opts.Limit {
+			matches, truncated = slices.Delete(matches, 0, len(matches)-opts.Limit), true
+		}
+	}
+	return matches, truncated, nil
+}
+
+// readLine returns the next line without its line ending, cut to maxBytes
+func readLine(reader *bufio.Reader, maxBytes int) (string, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		if room := maxBytes - len(line); room > 0 {
+			line = append(line, chunk[:min(len(chunk), room)]...)
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
+
+// gameServerLogFiles returns the game server logs of runs that overlap since..until, oldest first.
+// A run starts at the time in the file name and ends at the last write.
+func gameServerLogFiles(since, until time.Time) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(config.GetLogFolder(), "serverlog_*.log"))
+	if err != nil {
+		return nil, err
+	}
+	type run struct {
+		path  string
+		start time.Time
+	}
+	var runs []run
+	for _, path := range paths {
+		stamp, _, _ := strings.Cut(strings.TrimPrefix(filepath.Base(path), "serverlog_"), "_")
+		start, err := time.ParseInLocation("200601021504", stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !until.IsZero() && start.After(until) || !since.IsZero() && info.ModTime().Before(since) {
+			continue
+		}
+		runs = append(runs, run{path, start})
+	}
+	slices.SortFunc(runs, func(a, b run) int { return a.start.Compare(b.start) })
+	files := make([]string, len(runs))
+	for i, r := range runs {
+		files[i] = r.path
+	}
+	return files, nil
+}
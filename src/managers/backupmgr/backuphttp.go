@@ -7,8 +7,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
-	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
 )
 
 // HTTPHandler provides HTTP endpoints for backup operations
@@ -42,26 +42,6 @@ func (h *HTTPHandler) ListBackupsHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Check if classic mode is requested
-	mode := r.URL.Query().Get("mode")
-	if mode == "classic" {
-		// Format the response in the classic format
-		classicResponses := make([]string, 0, len(backups))
-		for _, backup := range backups {
-			// Format according to classic view: "BackupIndex: X, Created: DD.MM.YYYY HH:MM:SS"
-			classicLine := fmt.Sprintf("BackupIndex: %d, Created: %s",
-				backup.Index,
-				backup.SaveTime.Format("02.01.2006 15:04:05"))
-			classicResponses = append(classicResponses, classicLine)
-		}
-
-		// Return plain text response for classic mode
-		w.Header().Set("Content-Type", "text/plain")
-		w.Write([]byte(strings.Join(classicResponses, "\n")))
-		return
-	}
-
-	// Default JSON response
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(backups)
 }
@@ -69,26 +49,35 @@ func (h *HTTPHandler) ListBackupsHandler(w http.ResponseWriter, r *http.Request)
 // RestoreBackupHandler handles requests to restore a backup
 func (h *HTTPHandler) RestoreBackupHandler(w http.ResponseWriter, r *http.Request) {
 	logger.Web.Debug("Received restore request")
-	indexStr := r.URL.Query().Get("index")
-	if indexStr == "" {
-		http.Error(w, "index parameter is required", http.StatusBadRequest)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed, use POST"})
 		return
 	}
 
-	index, err := strconv.Atoi(indexStr)
+	index, err := strconv.Atoi(r.URL.Query().Get("index"))
 	if err != nil {
-		http.Error(w, "invalid index parameter", http.StatusBadRequest)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "index parameter is required and must be a number"})
 		return
 	}
 
-	gamemgr.InternalStopServer()
-
-	if err := h.manager.RestoreBackup(index); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// stopping the server and restoring can take a while, follow the job on /api/v2/jobs
+	job, err := h.manager.StartRestore(index)
+	if err != nil {
+		if strings.Contains(err.Error(), "out of range") {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
 
-	w.Write([]byte("Server stopped & Backup restored successfully, Start the server to load the restored backup"))
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(jobs.NewAccepted(job, "Stopping the server and restoring the backup, start the server when the job is done to load it"))
 }
 
 // DownloadBackupRequest represents the JSON request for downloading a backup
@@ -132,3 +121,39 @@ func (h *HTTPHandler) DownloadBackupHandler(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("Content-Length", fmt.Sprintf("%d", backupData.Size))
 	w.Write(backupData.Data)
 }
+
+// PinBackupRequest represents the JSON request for pinning or unpinning a backup
+type PinBackupRequest struct {
+	Index  int  `json:"index"`
+	Pinned bool `json:"pinned"`
+}
+
+// PinBackupHandler handles requests to pin or unpin a backup so cleanup keeps it
+func (h *HTTPHandler) PinBackupHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed, use POST"})
+		return
+	}
+
+	var req PinBackupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid JSON request body"})
+		return
+	}
+
+	if err := h.manager.SetBackupPinned(req.Index, req.Pinned); err != nil {
+		if strings.Contains(err.Error(), "out of range") {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(req)
+}
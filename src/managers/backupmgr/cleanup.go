@@ -101,13 +101,24 @@ func (m *BackupManager) cleanSafeBackupDir() error {
 		lastKeptMonthly time.Time
 	)
 
-	for i, backup := range saves {
+	m.prunePinnedBackups(saves)
+
+	unpinned := 0
+	for _, backup := range saves {
+		// Pinned backups are kept on top of the policy, they don't use up KeepLastN
+		// but do cover their day, week and month like any other kept backup.
+		if backup.Pinned {
+			updateRetentionTrackers(backup.SaveTime, &lastKeptDaily, &lastKeptWeekly, &lastKeptMonthly)
+			continue
+		}
+		unpinned++
+
 		age := now.Sub(backup.SaveTime)
 
 		// Always keep the most recent N backups, but also update the retention
 		// trackers so the daily/weekly/monthly logic doesn't redundantly keep
 		// backups for days already covered by KeepLastN.
-		if i < m.config.RetentionPolicy.KeepLastN {
+		if unpinned <= m.config.RetentionPolicy.KeepLastN {
 			updateRetentionTrackers(backup.SaveTime, &lastKeptDaily, &lastKeptWeekly, &lastKeptMonthly)
 			continue
 		}
@@ -0,0 +1,69 @@
+package backupmgr
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeTestBackup creates a .save zip with the world_meta.xml save time the backup list is sorted by
+func writeTestBackup(t *testing.T, dir, name string, saveTime time.Time) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("world_meta.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(w, "<WorldMetaData><DateTime>%d</DateTime></WorldMetaData>", saveTime.UnixNano()/100+filetimeEpochOffset)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCleanupKeepsPinnedBackupsOnTopOfKeepLastN(t *testing.T) {
+	dir := t.TempDir()
+	m := NewBackupManager(BackupConfig{SafeBackupDir: dir, BackupDir: t.TempDir(), RetentionPolicy: RetentionPolicy{KeepLastN: 2}})
+
+	now := time.Now()
+	for n := range 6 { // backup-0 is the oldest
+		writeTestBackup(t, dir, fmt.Sprintf("backup-%d.save", n), now.Add(time.Duration(n-6)*time.Hour))
+	}
+	m.mu.Lock()
+	if err := m.savePinnedBackups(map[string]bool{"backup-0.save": true, "backup-5.save": true, "deleted.save": true}); err != nil {
+		t.Fatal(err)
+	}
+	m.mu.Unlock()
+
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+
+	saves, err := m.getBackupSaveFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []string
+	for _, save := range saves {
+		kept = append(kept, filepath.Base(save.SaveFile))
+	}
+	// both pins plus the two newest unpinned backups
+	if want := []string{"backup-0.save", "backup-3.save", "backup-4.save", "backup-5.save"}; !slices.Equal(kept, want) {
+		t.Fatalf("kept %v, want %v", kept, want)
+	}
+
+	m.mu.Lock()
+	pins := m.loadPinnedBackups()
+	m.mu.Unlock()
+	if len(pins) != 2 || pins["deleted.save"] {
+		t.Fatalf("pins of missing backups must be pruned, got %v", pins)
+	}
+}
@@ -86,9 +86,11 @@ func (m *BackupManager) getBackupSaveFiles() ([]BackupSaveFile, error) {
 	sort.Slice(saves, func(i, j int) bool {
 		return saves[i].SaveTime.Before(saves[j].SaveTime)
 	})
-	// Add the index to each save
+	// Add the index and pin state to each save
+	pinned := m.loadPinnedBackups()
 	for i := range saves {
 		saves[i].Index = i
+		saves[i].Pinned = pinned[filepath.Base(saves[i].SaveFile)]
 	}
 
 	return saves, nil
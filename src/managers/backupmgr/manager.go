@@ -255,6 +255,51 @@ func (m *BackupManager) GetBackupFileData(index int) (*BackupFileData, error) {
 	}, nil
 }
 
+// BackupWorldSave copies the current world save into the safe backup dir as <label>_<time>_<world>.save and pins it,
+// e.g. before a game update. It returns an empty path if the world was never saved.
+func (m *BackupManager) BackupWorldSave(label string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	worldSave := filepath.Join("./saves", m.config.WorldName, m.config.WorldName+".save")
+	if _, err := os.Stat(worldSave); os.IsNotExist(err) {
+		return "", nil
+	}
+	if err := os.MkdirAll(m.config.SafeBackupDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create safe backup dir: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s_%s.save", label, time.Now().Format("2006-01-02_15-04-05"), m.config.WorldName)
+	dstPath := filepath.Join(m.config.SafeBackupDir, name)
+	if err := copyFile(worldSave, dstPath); err != nil {
+		return "", fmt.Errorf("failed to copy world save: %w", err)
+	}
+
+	pins := m.loadPinnedBackups()
+	pins[name] = true
+	if err := m.savePinnedBackups(pins); err != nil {
+		return dstPath, err
+	}
+	logger.Backup.Infof("World save backed up and pinned: %s", dstPath)
+	return dstPath, nil
+}
+
+// RestoreWorldSave copies a .save file made by BackupWorldSave back over the world save, the server must be stopped
+func (m *BackupManager) RestoreWorldSave(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	worldDir := filepath.Join("./saves", m.config.WorldName)
+	if err := os.MkdirAll(worldDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create world directory: %w", err)
+	}
+	if err := copyFile(path, filepath.Join(worldDir, m.config.WorldName+".save")); err != nil {
+		return fmt.Errorf("failed to restore world save: %w", err)
+	}
+	logger.Backup.Infof("World save restored from %s", path)
+	return nil
+}
+
 // Shutdown stops all backup operations
 func (m *BackupManager) Shutdown() {
 	logger.Backup.Debug("Shutting down previous backup manager...")
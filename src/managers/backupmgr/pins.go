@@ -0,0 +1,125 @@
+package backupmgr
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+// pinnedBackupsFile lives next to the backups in the safe backup dir and lists backup file names that cleanup must never delete.
+// Pins are stored by file name instead of index because indices shift once older backups are cleaned up.
+const pinnedBackupsFile = "ssui-pinned-backups.json"
+
+func (m *BackupManager) pinnedBackupsPath() string {
+	return filepath.Join(m.config.SafeBackupDir, pinnedBackupsFile)
+}
+
+// loadPinnedBackups returns the set of pinned backup file names. Caller must hold m.mu.
+func (m *BackupManager) loadPinnedBackups() map[string]bool {
+	pinned := make(map[string]bool)
+	data, err := os.ReadFile(m.pinnedBackupsPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Backup.Warn("Failed to read pinned backups: " + err.Error())
+		}
+		return pinned
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		logger.Backup.Warn("Failed to parse pinned backups: " + err.Error())
+		return pinned
+	}
+	for _, name := range names {
+		pinned[name] = true
+	}
+	return pinned
+}
+
+// savePinnedBackups writes the set of pinned backup file names. Caller must hold m.mu.
+func (m *BackupManager) savePinnedBackups(pinned map[string]bool) error {
+	names := make([]string, 0, len(pinned))
+	for name := range pinned {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pinned backups: %w", err)
+	}
+	if err := os.WriteFile(m.pinnedBackupsPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write pinned backups: %w", err)
+	}
+	return nil
+}
+
+// SetBackupPinned pins or unpins the backup with the given index. Pinned backups are skipped by the retention cleanup.
+func (m *BackupManager) SetBackupPinned(index int, pinned bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saves, err := m.getBackupSaveFiles()
+	if err != nil {
+		return fmt.Errorf("failed to get backup files: %w", err)
+	}
+	if index < 0 || index >= len(saves) {
+		return fmt.Errorf("backup index %d out of range (0-%d)", index, len(saves)-1)
+	}
+
+	name := filepath.Base(saves[index].SaveFile)
+	pins := m.loadPinnedBackups()
+	if pinned {
+		pins[name] = true
+	} else {
+		delete(pins, name)
+	}
+	if err := m.savePinnedBackups(pins); err != nil {
+		return err
+	}
+	logger.Backup.Infof("Backup %d (%s) pinned: %t", index, name, pinned)
+	return nil
+}
+
+// UnpinBackupFile unpins a backup by its path, e.g. a world save pinned by BackupWorldSave that is no longer needed.
+// The retention cleanup deletes it later like any other backup.
+func (m *BackupManager) UnpinBackupFile(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := filepath.Base(path)
+	pins := m.loadPinnedBackups()
+	if !pins[name] {
+		return nil
+	}
+	delete(pins, name)
+	if err := m.savePinnedBackups(pins); err != nil {
+		return err
+	}
+	logger.Backup.Infof("Backup %s unpinned", name)
+	return nil
+}
+
+// prunePinnedBackups drops pins of backups that no longer exist, e.g. after they were deleted by hand. Caller must hold m.mu.
+func (m *BackupManager) prunePinnedBackups(saves []BackupSaveFile) {
+	pins := m.loadPinnedBackups()
+	existing := make(map[string]bool, len(saves))
+	for _, save := range saves {
+		existing[filepath.Base(save.SaveFile)] = true
+	}
+	pruned := false
+	for name := range pins {
+		if !existing[name] {
+			delete(pins, name)
+			pruned = true
+		}
+	}
+	if !pruned {
+		return
+	}
+	if err := m.savePinnedBackups(pins); err != nil {
+		logger.Backup.Warn("Failed to prune pinned backups: " + err.Error())
+	}
+}
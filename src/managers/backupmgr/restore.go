@@ -2,6 +2,7 @@ package backupmgr
 
 import (
 	"archive/zip"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -10,9 +11,38 @@ import (
 	"strings"
 	"time"
 
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
 )
 
+// StartRestore queues a job that stops the server and restores the backup with the given index.
+// The index is resolved to the backup file right away, cleanups or new backups while the job waits shift the indices.
+func (m *BackupManager) StartRestore(index int) (jobs.Job, error) {
+	m.mu.Lock()
+	saves, err := m.getBackupSaveFiles()
+	m.mu.Unlock()
+	if err != nil {
+		return jobs.Job{}, fmt.Errorf("failed to get backup groups: %w", err)
+	}
+	if index < 0 || index >= len(saves) {
+		return jobs.Job{}, fmt.Errorf("backup index %d out of range (0-%d)", index, len(saves)-1)
+	}
+	return m.StartRestoreFile(saves[index]), nil
+}
+
+// StartRestoreFile queues a job that stops the server and restores the given backup, as listed by ListBackups.
+// Use it when the backup was shown to a user before, so the restore cannot pick up a different backup at the same index.
+func (m *BackupManager) StartRestoreFile(backup BackupSaveFile) jobs.Job {
+	name := filepath.Base(backup.SaveFile)
+	return jobs.Submit("backup-restore", jobs.LaneBackup, fmt.Sprintf("Restore backup #%d (%s)", backup.Index, name), func(ctx context.Context, job *jobs.Job) (any, error) {
+		job.SetProgress(0, "Stopping server")
+		gamemgr.InternalStopServer()
+		job.SetProgress(50, "Restoring backup "+name)
+		return nil, m.RestoreBackupFile(backup.SaveFile)
+	})
+}
+
 // RestoreBackup restores a backup with the given index
 func (m *BackupManager) RestoreBackup(index int) error {
 	m.mu.Lock()
@@ -24,12 +54,29 @@ func (m *BackupManager) RestoreBackup(index int) error {
 		return fmt.Errorf("failed to get backup groups: %w", err)
 	}
 
-	var targetSave = saves[index]
+	if index < 0 || index >= len(saves) {
+		return fmt.Errorf("backup index %d out of range (0-%d)", index, len(saves)-1)
+	}
+	return m.restoreSaveFile(saves[index].SaveFile)
+}
+
+// RestoreBackupFile restores the backup at backupFile, a path in the safe backup dir as returned by getBackupSaveFiles
+func (m *BackupManager) RestoreBackupFile(backupFile string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	logger.Backup.Infof("Restoring backup %s", backupFile)
+
+	if _, err := os.Stat(backupFile); err != nil {
+		return fmt.Errorf("backup %s is no longer available: %w", filepath.Base(backupFile), err)
+	}
+	return m.restoreSaveFile(backupFile)
+}
 
+// restoreSaveFile extracts a .save backup over the world save. Caller must hold m.mu.
+func (m *BackupManager) restoreSaveFile(backupFile string) error {
 	restoredFiles := make(map[string]string)
 
 	// .save file case
-	backupFile := targetSave.SaveFile
 	destFile := filepath.Join("./saves/"+m.config.WorldName, m.config.WorldName+".save")
 
 	// This check was disabled since it was relatively unnecessary and didnt bring much benefit
@@ -33,6 +33,7 @@ type BackupSaveFile struct {
 	Index    int
 	SaveFile string
 	SaveTime time.Time
+	Pinned   bool // pinned backups are never removed by cleanup
 }
 
 // BackupFileData contains the backup file bytes and metadata for download/transfer
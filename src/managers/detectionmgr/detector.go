@@ -43,6 +43,10 @@ func (d *Detector) RegisterHandler(eventType EventType, handler Handler) {
 
 // ProcessLogMessage analyzes a log message and triggers appropriate handlers
 func (d *Detector) ProcessLogMessage(logMessage string) {
+	if d.collectStackFrame(logMessage) {
+		return
+	}
+
 	// Check for simple keyword patterns
 	keywordPatterns := map[string]EventType{
 		"Ready":                               EventServerReady,
@@ -74,7 +78,7 @@ func (d *Detector) ProcessLogMessage(logMessage string) {
 			if matches != nil {
 				// Format message with {0}, {1} placeholders
 				message := formatMessage(cp.MessageTmpl, matches)
-				d.triggerEvent(Event{
+				d.triggerCustomEvent(Event{
 					Type:      cp.EventType,
 					Message:   message,
 					RawLog:    logMessage,
@@ -84,7 +88,7 @@ func (d *Detector) ProcessLogMessage(logMessage string) {
 		} else {
 			// Keyword matching
 			if strings.Contains(logMessage, cp.Keyword) {
-				d.triggerEvent(Event{
+				d.triggerCustomEvent(Event{
 					Type:      cp.EventType,
 					Message:   cp.MessageTmpl,
 					RawLog:    logMessage,
@@ -190,7 +194,7 @@ func (d *Detector) processRegexPatterns(logMessage string) {
 			// Exception pattern
 			pattern: regexp.MustCompile(`(?m)^\s*>\s*\d{2}:\d{2}:\d{2}:.*Exception.*|>\s+\d{2}:\d{2}:\d{2}:.*StackTrace`),
 			handler: func(matches []string, logMessage string) {
-				d.triggerEvent(Event{
+				d.startException(Event{
 					Type:      EventException,
 					Message:   "Exception detected",
 					RawLog:    logMessage,
@@ -201,6 +205,23 @@ func (d *Detector) processRegexPatterns(logMessage string) {
 				})
 			},
 		},
+		{ // In-game chat pattern, e.g. "12:34:56: Chat: PlayerName: hello" or "12:34:56: [Chat] PlayerName: hello"
+			pattern: regexp.MustCompile(`\d{2}:\d{2}:\d{2}:\s+(?:Chat:|\[Chat\])\s*(.+?):\s+(.+?)\s*$`),
+			handler: func(matches []string, logMessage string) {
+				username := matches[1]
+				chatMessage := matches[2]
+				d.triggerEvent(Event{
+					Type:      EventPlayerChat,
+					Message:   fmt.Sprintf("%s: %s", username, chatMessage),
+					RawLog:    logMessage,
+					Timestamp: time.Now().Format(time.RFC3339),
+					ChatInfo: &ChatInfo{
+						Username: username,
+						Message:  chatMessage,
+					},
+				})
+			},
+		},
 		{ // Setting changed pattern
 			pattern: regexp.MustCompile(`\d{2}:\d{2}:\d{2}: Changed setting '(.+?)' from '(.+?)' to '(.+?)'`),
 			handler: func(matches []string, logMessage string) {
@@ -273,6 +294,19 @@ func (d *Detector) triggerEvent(event Event) {
 	}
 }
 
+// triggerCustomEvent triggers an event from a custom detection. Custom event types without their own handlers
+// fall back to the CUSTOM_DETECTION handlers, keeping the custom type so Discord routing rules can still match it.
+func (d *Detector) triggerCustomEvent(event Event) {
+	if _, ok := d.handlers[event.Type]; ok {
+		d.triggerEvent(event)
+		return
+	}
+	handleServerStateEvent(event.Type)
+	for _, handler := range d.handlers[EventCustomDetection] {
+		handler(event)
+	}
+}
+
 // GetConnectedPlayers returns a copy of the connected players map
 func (d *Detector) GetConnectedPlayers() map[string]string {
 	players := make(map[string]string)
@@ -0,0 +1,64 @@
+package detectionmgr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlayerChatIsDetected(t *testing.T) {
+	detector := NewDetector()
+	var got []ChatInfo
+	detector.RegisterHandler(EventPlayerChat, func(event Event) {
+		if event.ChatInfo != nil {
+			got = append(got, *event.ChatInfo)
+		}
+	})
+
+	lines := []string{
+		"12:34:56: Chat: Jackson: hello there",
+		"12:34:57: [Chat] Some Player: what time is it: noon?",
+		"12:34:58: Client Jackson (76561198000000000) is ready!",
+	}
+	for _, line := range lines {
+		detector.ProcessLogMessage(line)
+	}
+
+	want := []ChatInfo{
+		{Username: "Jackson", Message: "hello there"},
+		{Username: "Some Player", Message: "what time is it: noon?"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d chat detections, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("chat %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestExceptionCollectsStackFrames(t *testing.T) {
+	detector := NewDetector()
+	var got []ExceptionInfo
+	detector.RegisterHandler(EventException, func(event Event) {
+		got = append(got, *event.ExceptionInfo)
+	})
+
+	lines := []string{
+		"> 12:34:56: NullReferenceException: Object reference not set to an instance of an object",
+		"  at DoorTweaks.Patches.DoorPatch.Postfix (Assets.Scripts.Objects.Door __instance) [0x00000] in <abc>:0",
+		"  at (wrapper dynamic-method) Assets.Scripts.Objects.Door.DMD<OnInteract>(Assets.Scripts.Objects.Door)",
+		"UnityEngine.Debug:LogException(Exception)",
+		"> 12:34:57: Client Jackson (76561198000000000) is ready!",
+	}
+	for _, line := range lines {
+		detector.ProcessLogMessage(line)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("expected one exception, got %d: %+v", len(got), got)
+	}
+	if got[0].Frames != 3 || !strings.Contains(got[0].StackTrace, "DoorTweaks.Patches.DoorPatch.Postfix") || strings.Contains(got[0].StackTrace, "is ready") {
+		t.Errorf("unexpected stack trace (%d frames):\n%s", got[0].Frames, got[0].StackTrace)
+	}
+}
@@ -0,0 +1,109 @@
+// exceptions.go
+package detectionmgr
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/ssestream"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/discordbot"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+)
+
+/*
+Exception Stack Trace Collection
+- The game logs an exception as one line followed by its stack frames, one per log line
+- The EXCEPTION event is held back until the frames are collected: it fires on the first line that is not a frame,
+  after maxExceptionFrames frames, or exceptionFlushDelay after the last line
+- The default handler attributes the trace to an installed mod via modding.AttributeModException
+*/
+
+const (
+	maxExceptionFrames  = 64
+	exceptionFlushDelay = 2 * time.Second
+)
+
+// stackFramePattern matches Mono frames ("  at MyMod.Patch.Postfix () [0x00000] in <...>:0", "(wrapper dynamic-method) ..."),
+// Unity frames ("MyMod.Patch:Postfix(Thing)", "(Filename: ... Line: 0)") and the separators between inner and outer exceptions
+var stackFramePattern = regexp.MustCompile("^\\s*(?:at |\\(wrapper |\\(Filename: |--- |Rethrow as |[\\w.`<>+]+:[\\w.`<>|]+\\s*\\()")
+
+// startException holds an exception event back until its stack frames are collected
+func (d *Detector) startException(event Event) {
+	d.exceptionMu.Lock()
+	previous := d.pendingException
+	d.pendingException = &event
+	if d.exceptionTimer != nil {
+		d.exceptionTimer.Stop()
+	}
+	d.exceptionTimer = time.AfterFunc(exceptionFlushDelay, d.flushException)
+	d.exceptionMu.Unlock()
+
+	if previous != nil {
+		d.triggerEvent(*previous)
+	}
+}
+
+// collectStackFrame adds a log line to the pending exception if it is a stack frame, any other line completes the exception
+func (d *Detector) collectStackFrame(logMessage string) bool {
+	d.exceptionMu.Lock()
+	if d.pendingException == nil {
+		d.exceptionMu.Unlock()
+		return false
+	}
+	if !stackFramePattern.MatchString(logMessage) {
+		d.exceptionMu.Unlock()
+		d.flushException()
+		return false
+	}
+	d.pendingException.ExceptionInfo.StackTrace += "\n" + logMessage
+	d.pendingException.ExceptionInfo.Frames++
+	full := d.pendingException.ExceptionInfo.Frames >= maxExceptionFrames
+	d.exceptionMu.Unlock()
+
+	if full {
+		d.flushException()
+	}
+	return true
+}
+
+// flushException triggers the pending exception event
+func (d *Detector) flushException() {
+	d.exceptionMu.Lock()
+	event := d.pendingException
+	d.pendingException = nil
+	if d.exceptionTimer != nil {
+		d.exceptionTimer.Stop()
+		d.exceptionTimer = nil
+	}
+	d.exceptionMu.Unlock()
+
+	if event != nil {
+		d.triggerEvent(*event)
+	}
+}
+
+// reportModException counts an exception against the mod it comes from and warns once the mod is flagged as incompatible
+func reportModException(info *ExceptionInfo) {
+	attribution, ok := modding.AttributeModException(info.StackTrace)
+	if !ok {
+		return
+	}
+	mod := attribution.Mod
+	label := mod.Folder
+	if mod.Name != "" {
+		label = mod.Name + " (" + mod.Folder + ")"
+	}
+	message := fmt.Sprintf("🎮 [Gameserver] 🧩 Exception likely caused by mod %s, %d errors on build %s", label, mod.Errors, mod.BuildID)
+	logger.Detection.Warn(message)
+	ssestream.BroadcastDetectionEvent(message)
+
+	if attribution.NewlyFlagged {
+		message := fmt.Sprintf("🎮 [Gameserver] ⚠️ Mod %s is likely incompatible with build %s after %d errors. Starting the server asks for confirmation until the mod is updated or its errors are reset.",
+			label, mod.BuildID, mod.Errors)
+		logger.Detection.Warn(message)
+		ssestream.BroadcastDetectionEvent(message)
+		discordbot.SendEventMessage(string(EventException), message)
+	}
+}
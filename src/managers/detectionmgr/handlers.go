@@ -17,6 +17,7 @@ Event Handler Subsystem
 - Formats and routes event notifications to:
   - Terminal output with ANSI coloring
   - SSE stream for web UI
+  - Discord, routed per event type by discordbot.SendEventMessage
 */
 
 var lastWorldSavedTime time.Time // zero value means never saved
@@ -29,74 +30,74 @@ func DefaultHandlers() map[EventType]Handler {
 			message := fmt.Sprintf("🎮 [Custom Detection] %s", event.Message)
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 
 		EventServerReady: func(event Event) {
 			message := "🎮 [Gameserver] 🔔 Server is ready to connect!"
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventServerStarting: func(event Event) {
 			message := "🎮 [Gameserver] 🕑 Server is starting up..."
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventServerError: func(event Event) {
 			message := "🎮 [Gameserver] ⚠️ Server error detected"
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventSettingsChanged: func(event Event) {
 			message := fmt.Sprintf("🎮 [Gameserver] ⚙️ %s", event.Message)
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventServerHosted: func(event Event) {
 			message := fmt.Sprintf("🎮 [Gameserver] 🌐 %s", event.Message)
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventNewGameStarted: func(event Event) {
 			message := fmt.Sprintf("🎮 [Gameserver] 🎲 %s", event.Message)
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventVersionExtracted: func(event Event) {
 			message := fmt.Sprintf("🎮 [Gameserver] 📦 Version %s detected", event.Message)
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventServerRunning: func(event Event) {
 			message := "🎮 [Gameserver] ✅ Server process has started!"
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventGameManagerReady: func(event Event) {
 			message := "🎮 [Gameserver] 🗺️ Game manager initialized; loading map..."
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventSessionStarting: func(event Event) {
 			message := "🎮 [Gameserver] 🌐 Starting multiplayer session..."
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventSessionRegistered: func(event Event) {
 			message := "🎮 [Gameserver] ✅ Session registered; server is running."
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 		EventPlayerConnecting: func(event Event) {
 			if event.PlayerInfo != nil {
@@ -104,7 +105,9 @@ func DefaultHandlers() map[EventType]Handler {
 					event.PlayerInfo.Username, event.PlayerInfo.SteamID)
 				logger.Detection.Info(message)
 				ssestream.BroadcastDetectionEvent(message)
-				discordbot.SendMessageToEventLogChannel(message)
+				discordbot.SendEventMessage(string(event.Type), message)
+
+				reportModException(event.ExceptionInfo)
 			}
 		},
 		EventPlayerReady: func(event Event) {
@@ -113,7 +116,7 @@ func DefaultHandlers() map[EventType]Handler {
 					event.PlayerInfo.Username, event.PlayerInfo.SteamID)
 				logger.Detection.Info(message)
 				ssestream.BroadcastDetectionEvent(message)
-				discordbot.SendMessageToEventLogChannel(message)
+				discordbot.SendEventMessage(string(event.Type), message)
 			}
 		},
 		EventPlayerDisconnect: func(event Event) {
@@ -122,7 +125,15 @@ func DefaultHandlers() map[EventType]Handler {
 					event.PlayerInfo.Username)
 				logger.Detection.Info(message)
 				ssestream.BroadcastDetectionEvent(message)
-				discordbot.SendMessageToEventLogChannel(message)
+				discordbot.SendEventMessage(string(event.Type), message)
+			}
+		},
+		EventPlayerChat: func(event Event) {
+			if event.ChatInfo != nil {
+				message := fmt.Sprintf("🎮 [Gameserver] 💬 %s: %s", event.ChatInfo.Username, event.ChatInfo.Message)
+				logger.Detection.Info(message)
+				ssestream.BroadcastDetectionEvent(message)
+				discordbot.RelayChatToDiscord(event.ChatInfo.Username, event.ChatInfo.Message)
 			}
 		},
 		EventWorldSaved: func(event Event) {
@@ -142,16 +153,16 @@ func DefaultHandlers() map[EventType]Handler {
 
 			logger.Detection.Info(message)
 			ssestream.BroadcastDetectionEvent(message)
-			discordbot.SendMessageToEventLogChannel(message)
+			discordbot.SendEventMessage(string(event.Type), message)
 		},
 
-		// not sure if this Detector still works, so I changed this to SendMessageToEventLogChannel instead of SendMessageToErrorChannel.
+		// not sure if this Detector still works, so this goes through the event routing instead of SendMessageToErrorChannel.
 		EventException: func(event Event) {
 			// Initial alert message
 			alertMessage := "🎮 [Gameserver] 🚨 Exception detected!"
 			logger.Detection.Info(alertMessage)
 			ssestream.BroadcastDetectionEvent(alertMessage)
-			discordbot.SendMessageToEventLogChannel(alertMessage)
+			discordbot.SendEventMessage(string(event.Type), alertMessage)
 
 			if event.ExceptionInfo != nil && len(event.ExceptionInfo.StackTrace) > 0 {
 				// Format stack trace as a single-line string for SSE compatibility
@@ -160,7 +171,7 @@ func DefaultHandlers() map[EventType]Handler {
 
 				logger.Detection.Info(message)
 				ssestream.BroadcastDetectionEvent(message)
-				discordbot.SendMessageToEventLogChannel(message)
+				discordbot.SendEventMessage(string(event.Type), message)
 			}
 		},
 	}
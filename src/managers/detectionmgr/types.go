@@ -4,6 +4,7 @@ package detectionmgr
 import (
 	"regexp"
 	"sync"
+	"time"
 )
 
 // EventType defines the type of event detected
@@ -26,6 +27,7 @@ const (
 	EventGameManagerReady  EventType = "GAME_MANAGER_READY"
 	EventSessionStarting   EventType = "SESSION_STARTING"
 	EventSessionRegistered EventType = "SESSION_REGISTERED"
+	EventPlayerChat        EventType = "PLAYER_CHAT"
 	EventCustomDetection   EventType = "CUSTOM_DETECTION"
 )
 
@@ -33,6 +35,10 @@ type Detector struct {
 	handlers         map[EventType][]Handler
 	connectedPlayers map[string]string // SteamID -> Username
 	customPatterns   []CustomPattern
+
+	exceptionMu      sync.Mutex
+	pendingException *Event // exception whose stack frames are still being collected
+	exceptionTimer   *time.Timer
 }
 
 type CustomPattern struct {
@@ -51,6 +57,7 @@ type Event struct {
 	Timestamp     string
 	PlayerInfo    *PlayerInfo
 	ExceptionInfo *ExceptionInfo
+	ChatInfo      *ChatInfo
 }
 
 // PlayerInfo contains information about a player
@@ -61,7 +68,14 @@ type PlayerInfo struct {
 
 // ExceptionInfo contains information about a server exception
 type ExceptionInfo struct {
-	StackTrace string
+	StackTrace string // the exception line followed by its stack frames
+	Frames     int
+}
+
+// ChatInfo contains a single in-game chat line
+type ChatInfo struct {
+	Username string
+	Message  string
 }
 
 // Handler is a function that handles detected events
@@ -1,6 +1,7 @@
 package gamemgr
 
 import (
+	"slices"
 	"sync"
 	"time"
 )
@@ -19,12 +20,34 @@ const (
 
 const startupStateTimeout = 5 * time.Minute
 
+type serverStateListener struct {
+	id uint64
+	fn func(previous, state ServerState)
+}
+
 var (
 	serverStateMu         sync.RWMutex
 	serverState           = ServerStateUncertain
 	serverStateGeneration uint64
+	serverStateListeners  []serverStateListener
+	nextStateListenerID   uint64
 )
 
+// AddServerStateListener registers a function that is called after every change of the server state, returns a function that removes it
+func AddServerStateListener(fn func(previous, state ServerState)) (remove func()) {
+	serverStateMu.Lock()
+	defer serverStateMu.Unlock()
+	nextStateListenerID++
+	id := nextStateListenerID
+	// copy on write, notifications iterate over the slice they got without holding the lock
+	serverStateListeners = append(slices.Clip(serverStateListeners), serverStateListener{id: id, fn: fn})
+	return func() {
+		serverStateMu.Lock()
+		defer serverStateMu.Unlock()
+		serverStateListeners = slices.DeleteFunc(slices.Clone(serverStateListeners), func(l serverStateListener) bool { return l.id == id })
+	}
+}
+
 func GetServerState() ServerState {
 	serverStateMu.RLock()
 	defer serverStateMu.RUnlock()
@@ -33,18 +56,31 @@ func GetServerState() ServerState {
 
 func SetServerState(state ServerState) {
 	serverStateMu.Lock()
+	previous := serverState
 	serverState = state
+	listeners := serverStateListeners
 	if state == ServerStateStarting {
 		serverStateGeneration++
 		generation := serverStateGeneration
 		serverStateMu.Unlock()
 		go markStartupUncertainAfter(generation, startupStateTimeout)
+		notifyServerStateChange(listeners, previous, state)
 		return
 	}
 	if state == ServerStateRunning || state == ServerStateStopping || state == ServerStateStopped {
 		serverStateGeneration++
 	}
 	serverStateMu.Unlock()
+	notifyServerStateChange(listeners, previous, state)
+}
+
+func notifyServerStateChange(listeners []serverStateListener, previous, state ServerState) {
+	if previous == state {
+		return
+	}
+	for _, listener := range listeners {
+		listener.fn(previous, state)
+	}
 }
 
 func markStartupUncertainAfter(generation uint64, delay time.Duration) {
@@ -53,12 +89,16 @@ func markStartupUncertainAfter(generation uint64, delay time.Duration) {
 	<-timer.C
 
 	serverStateMu.Lock()
-	defer serverStateMu.Unlock()
 	if generation != serverStateGeneration {
+		serverStateMu.Unlock()
 		return
 	}
+	previous := serverState
 	switch serverState {
 	case ServerStateStarting, ServerStateLoadingMap, ServerStateHostingSession:
 		serverState = ServerStateUncertain
 	}
+	state, listeners := serverState, serverStateListeners
+	serverStateMu.Unlock()
+	notifyServerStateChange(listeners, previous, state)
 }
@@ -29,3 +29,34 @@ func TestCompletedStartupIgnoresOldTimeout(t *testing.T) {
 		t.Fatalf("expected running state to survive old timeout, got %s", state)
 	}
 }
+
+func TestServerStateListenerSeesStartupTimeout(t *testing.T) {
+	var changes []ServerState
+	t.Cleanup(AddServerStateListener(func(previous, state ServerState) { changes = append(changes, previous, state) }))
+
+	SetServerState(ServerStateStopped)
+	SetServerState(ServerStateStarting)
+	serverStateMu.RLock()
+	generation := serverStateGeneration
+	serverStateMu.RUnlock()
+	markStartupUncertainAfter(generation, time.Millisecond)
+
+	if len(changes) < 4 || changes[len(changes)-2] != ServerStateStarting || changes[len(changes)-1] != ServerStateUncertain {
+		t.Fatalf("expected the listener to see starting -> uncertain, got %v", changes)
+	}
+}
+
+func TestServerStateListenersAreIndependent(t *testing.T) {
+	var first, second int
+	removeFirst := AddServerStateListener(func(previous, state ServerState) { first++ })
+	t.Cleanup(AddServerStateListener(func(previous, state ServerState) { second++ }))
+
+	SetServerState(ServerStateStopped)
+	SetServerState(ServerStateStopping)
+	removeFirst()
+	SetServerState(ServerStateStopped)
+
+	if first != 2 || second != 3 {
+		t.Fatalf("expected 2 and 3 notifications, got %d and %d", first, second)
+	}
+}
@@ -0,0 +1,178 @@
+package gameupdatemgr
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/ssestream"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/discordbot"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/detectionmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
+)
+
+/*
+Branch switching and rollback
+- SwitchBranch snapshots the install and the world save, sets GameBranch and runs SteamCMD. If SteamCMD fails or is cancelled the snapshot is restored.
+- Rollback restores a snapshot (the latest by default) with its branch, build and world save
+- Both take the job context, cancelling the job stops the snapshot copy, SteamCMD or the restore copy
+- Both are recorded in the install history next to every SteamCMD update
+*/
+
+// EventGameBranchChanged is raised when a branch switch or rollback finished
+const EventGameBranchChanged = "GAME_BRANCH_CHANGED"
+
+// operationMu allows one branch switch, rollback or automatic update at a time
+var operationMu sync.Mutex
+
+// CheckBranch validates a branch name against the branches the poller has seen, if it has seen any yet
+func CheckBranch(branch string) error {
+	if branch == "" {
+		return fmt.Errorf("branch cannot be empty")
+	}
+	if branch == config.GetGameBranch() {
+		return fmt.Errorf("the server is already on branch %s", branch)
+	}
+	known := steamcmd.KnownBranches()
+	if _, ok := known[branch]; len(known) > 0 && !ok {
+		return fmt.Errorf("unknown branch %s, available: %s", branch, strings.Join(slices.Sorted(maps.Keys(known)), ", "))
+	}
+	return nil
+}
+
+// SwitchBranch installs another game branch after taking a snapshot to roll back to
+func SwitchBranch(ctx context.Context, branch string) error {
+	branch = strings.ToLower(strings.TrimSpace(branch))
+	if err := CheckBranch(branch); err != nil {
+		return err
+	}
+	if !operationMu.TryLock() {
+		return fmt.Errorf("a branch switch, rollback or game update is already running")
+	}
+	defer operationMu.Unlock()
+
+	previous := config.GetGameBranch()
+	logger.Install.Info("🔀 Switching game branch from " + previous + " to " + branch)
+	wasRunning, err := stopForMaintenance()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := createSnapshot(ctx)
+	if err != nil {
+		restartIf(wasRunning)
+		return fmt.Errorf("branch switch aborted, snapshot failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		restartIf(wasRunning)
+		return fmt.Errorf("branch switch cancelled: %w", err)
+	}
+	if err := config.SetGameBranch(branch); err != nil {
+		restartIf(wasRunning)
+		return fmt.Errorf("failed to save the branch: %w", err)
+	}
+	if _, err := steamcmd.InstallAndRunSteamCMDContext(ctx); err != nil {
+		logger.Install.Error("❌ Installing branch " + branch + " failed, restoring snapshot " + snapshot.ID)
+		// a cancelled switch leaves a half updated install, the restore has to finish either way
+		if restoreErr := restoreInstall(context.WithoutCancel(ctx), snapshot); restoreErr != nil {
+			err = fmt.Errorf("%w, restoring the snapshot failed too: %v", err, restoreErr)
+		}
+		restartIf(wasRunning)
+		announceInstallChange("❌ Switching to branch " + branch + " failed: " + err.Error())
+		return fmt.Errorf("installing branch %s failed: %w", branch, err)
+	}
+
+	restartIf(wasRunning)
+	announceInstallChange(fmt.Sprintf("🔀 Switched from branch %s to %s, roll back with snapshot %s", previous, branch, snapshot.ID))
+	return nil
+}
+
+// Rollback restores the snapshot with the given ID, or the latest one if id is empty
+func Rollback(ctx context.Context, id string) error {
+	snapshots := ListSnapshots()
+	index := slices.IndexFunc(snapshots, func(s Snapshot) bool { return id == "" || s.ID == id })
+	if index < 0 {
+		if id == "" {
+			return fmt.Errorf("there is no snapshot to roll back to")
+		}
+		return fmt.Errorf("snapshot %s not found", id)
+	}
+	snapshot := snapshots[index]
+
+	if !operationMu.TryLock() {
+		return fmt.Errorf("a branch switch, rollback or game update is already running")
+	}
+	defer operationMu.Unlock()
+
+	logger.Install.Info("⏪ Rolling back to snapshot " + snapshot.ID)
+	wasRunning, err := stopForMaintenance()
+	if err != nil {
+		return err
+	}
+	err = restoreInstall(ctx, snapshot)
+	restartIf(wasRunning)
+	if err != nil {
+		announceInstallChange("❌ Rollback to snapshot " + snapshot.ID + " failed: " + err.Error())
+		return err
+	}
+	announceInstallChange(fmt.Sprintf("⏪ Rolled back to branch %s build %s from snapshot %s", snapshot.Branch, snapshot.BuildID, snapshot.ID))
+	return nil
+}
+
+// restoreInstall puts a snapshot back in place and records it, the server must be stopped
+func restoreInstall(ctx context.Context, snapshot Snapshot) error {
+	if steamcmd.IsUpdating() {
+		return fmt.Errorf("SteamCMD is running, try again when it is done")
+	}
+	if err := restoreSnapshotFiles(ctx, snapshot); err != nil {
+		return err
+	}
+	if err := config.SetGameBranch(snapshot.Branch); err != nil {
+		return fmt.Errorf("failed to save the branch: %w", err)
+	}
+	config.SetCurrentBranchBuildID(snapshot.BuildID)
+
+	// do not update straight back to the build we just left
+	if latest, ok := steamcmd.KnownBranches()[snapshot.Branch]; ok && latest != snapshot.BuildID {
+		mu.Lock()
+		heldID = latest
+		mu.Unlock()
+	}
+	steamcmd.RefreshGameUpdateStatus()
+
+	err := steamcmd.RecordInstall(steamcmd.InstallRecord{
+		Action:   steamcmd.InstallActionRollback,
+		Branch:   snapshot.Branch,
+		BuildID:  snapshot.BuildID,
+		Snapshot: snapshot.ID,
+	})
+	if err != nil {
+		logger.Install.Error("❌ " + err.Error())
+	}
+	return nil
+}
+
+// stopForMaintenance stops a running server and reports whether it was running
+func stopForMaintenance() (bool, error) {
+	if !gamemgr.InternalIsServerRunning() {
+		return false, nil
+	}
+	if err := stopForUpdate(len(detectionmgr.GetPlayers(detectionmgr.GetDetector()))); err != nil {
+		return true, fmt.Errorf("failed to stop the server: %w", err)
+	}
+	return true, nil
+}
+
+func announceInstallChange(message string) {
+	message = "🎮 [Gameserver] " + message
+	logger.Install.Info(message)
+	ssestream.BroadcastDetectionEvent(message)
+	discordbot.SendEventMessage(EventGameBranchChanged, message)
+}
@@ -0,0 +1,259 @@
+package gameupdatemgr
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/ssestream"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/discordbot"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/backupmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/commandmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/detectionmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
+)
+
+/*
+Player-aware automatic game server updates
+- steamcmd reports newer builds of GameBranch to HandleUpdateAvailable, which raises the GAME_UPDATE_AVAILABLE event once per build
+- With AllowAutoGameServerUpdates the update is scheduled. If AutoGameServerUpdateWaitForEmpty or AutoGameServerUpdateWindow is set,
+  it waits until the server is empty or the maintenance window is open, whichever comes first
+- The update announces a countdown in-game (AutoRestartCountdown) if players are online, saves, stops the server,
+  backs up and pins the world save, runs SteamCMD and starts the server again if it was running
+*/
+
+// Event types for Discord routing rules
+const (
+	EventGameUpdateAvailable = "GAME_UPDATE_AVAILABLE"
+	EventGameUpdateApplied   = "GAME_UPDATE_APPLIED"
+)
+
+const checkInterval = time.Minute
+
+// Status is the update state reported by the API
+type Status struct {
+	steamcmd.GameUpdateStatus
+	AutoUpdate   bool      `json:"autoUpdate"`
+	Scheduled    bool      `json:"scheduled"`            // an automatic update is waiting or running
+	WaitingFor   string    `json:"waitingFor,omitempty"` // why the scheduled update has not started yet
+	WaitingSince time.Time `json:"waitingSince,omitzero"`
+	LastResult   string    `json:"lastResult,omitempty"` // outcome of the last automatic update
+}
+
+var (
+	mu           sync.Mutex
+	announcedID  string // build the event was raised for
+	heldID       string // build that is not installed automatically, after its update failed or was rolled back
+	scheduled    bool
+	waitingFor   string
+	waitingSince time.Time
+	lastResult   string
+)
+
+// Init connects the update manager to the poller in steamcmd, call once at startup
+func Init() {
+	steamcmd.SetGameUpdateHandler(HandleUpdateAvailable)
+}
+
+// GetStatus returns the update state
+func GetStatus() Status {
+	mu.Lock()
+	defer mu.Unlock()
+	return Status{
+		GameUpdateStatus: steamcmd.GetGameUpdateStatus(),
+		AutoUpdate:       config.GetAllowAutoGameServerUpdates(),
+		Scheduled:        scheduled,
+		WaitingFor:       waitingFor,
+		WaitingSince:     waitingSince,
+		LastResult:       lastResult,
+	}
+}
+
+// HandleUpdateAvailable is called by the steamcmd poller for every check that found a newer build
+func HandleUpdateAvailable(status steamcmd.GameUpdateStatus) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if announcedID != status.LatestBuildID {
+		announcedID = status.LatestBuildID
+		message := fmt.Sprintf("🎮 [Gameserver] 🆕 Game update available on branch %s: build %s → %s", status.Branch, status.InstalledBuildID, status.LatestBuildID)
+		if !config.GetAllowAutoGameServerUpdates() {
+			message += ", run SteamCMD to install it"
+		}
+		logger.Install.Info(message)
+		ssestream.BroadcastDetectionEvent(message)
+		go discordbot.SendEventMessage(EventGameUpdateAvailable, message)
+	}
+
+	if !config.GetAllowAutoGameServerUpdates() || scheduled || heldID == status.LatestBuildID {
+		return
+	}
+	scheduled, waitingSince = true, time.Now()
+	go runScheduledUpdate()
+}
+
+// runScheduledUpdate waits until the update may start and applies it
+func runScheduledUpdate() {
+	defer func() {
+		mu.Lock()
+		scheduled, waitingFor, waitingSince = false, "", time.Time{}
+		mu.Unlock()
+	}()
+
+	for {
+		if !config.GetAllowAutoGameServerUpdates() || !steamcmd.GetGameUpdateStatus().Available {
+			logger.Install.Info("🔍 Scheduled game update cancelled, automatic updates were disabled or the update was installed")
+			return
+		}
+		running := gamemgr.InternalIsServerRunning()
+		players := 0
+		if running {
+			players = len(detectionmgr.GetPlayers(detectionmgr.GetDetector()))
+		}
+		due, reason := updateDue(time.Now(), running, players, config.GetAutoGameServerUpdateWaitForEmpty(), config.GetAutoGameServerUpdateWindow())
+		if due && !operationMu.TryLock() {
+			due, reason = false, "the running branch switch or rollback is done"
+		}
+		if due {
+			defer operationMu.Unlock()
+			logger.Install.Info("🔍 Starting scheduled game update: " + reason)
+			latest := steamcmd.GetGameUpdateStatus().LatestBuildID
+			result, ok := applyUpdate(running, players)
+			mu.Lock()
+			lastResult = result
+			if !ok {
+				heldID = latest
+			}
+			mu.Unlock()
+			return
+		}
+
+		mu.Lock()
+		if waitingFor != reason {
+			logger.Install.Info("🕑 Game update postponed until " + reason)
+		}
+		waitingFor = reason
+		mu.Unlock()
+		time.Sleep(checkInterval)
+	}
+}
+
+// updateDue decides whether a scheduled update may start now. Without waiting conditions it starts right away.
+// If it may not, reason describes what it is waiting for.
+func updateDue(now time.Time, running bool, players int, waitForEmpty bool, window string) (due bool, reason string) {
+	start, end, _ := config.ParseMaintenanceWindow(window)
+	switch {
+	case !running:
+		return true, "the server is not running"
+	case !waitForEmpty && start < 0:
+		return true, "no maintenance window or empty server required"
+	case waitForEmpty && players == 0:
+		return true, "the server is empty"
+	case start >= 0 && inWindow(now, start, end):
+		return true, "the maintenance window " + window + " is open"
+	case waitForEmpty && start >= 0:
+		return false, "the server is empty or the maintenance window " + window + " opens"
+	case waitForEmpty:
+		return false, "the server is empty"
+	default:
+		return false, "the maintenance window " + window + " opens"
+	}
+}
+
+// inWindow reports whether now lies in the window given as minutes after midnight, windows may wrap past midnight
+func inWindow(now time.Time, start, end int) bool {
+	minute := now.Hour()*60 + now.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// applyUpdate runs the update and returns a short result for the status
+func applyUpdate(wasRunning bool, players int) (string, bool) {
+	status := steamcmd.GetGameUpdateStatus()
+	if wasRunning {
+		if err := stopForUpdate(players); err != nil {
+			return "stopping the server failed: " + err.Error(), false
+		}
+	}
+
+	if backupmgr.GlobalBackupManager != nil {
+		path, err := backupmgr.GlobalBackupManager.BackupWorldSave("pre-update")
+		if err != nil {
+			// never update without a way back
+			logger.Install.Error("❌ Pre-update backup failed, game update skipped: " + err.Error())
+			restartIf(wasRunning)
+			return "pre-update backup failed: " + err.Error(), false
+		}
+		if path != "" {
+			logger.Install.Info("✅ Pre-update backup: " + path)
+		}
+	}
+
+	_, err := steamcmd.InstallAndRunSteamCMD()
+	restartIf(wasRunning)
+	if err != nil {
+		message := "🎮 [Gameserver] ❌ Automatic game update to build " + status.LatestBuildID + " failed: " + err.Error()
+		logger.Install.Error(message)
+		ssestream.BroadcastDetectionEvent(message)
+		discordbot.SendEventMessage(EventGameUpdateApplied, message)
+		return "update failed: " + err.Error(), false
+	}
+	message := fmt.Sprintf("🎮 [Gameserver] ✅ Game server updated to build %s on branch %s", status.LatestBuildID, status.Branch)
+	logger.Install.Info(message)
+	ssestream.BroadcastDetectionEvent(message)
+	discordbot.SendEventMessage(EventGameUpdateApplied, message)
+	return "updated to build " + status.LatestBuildID, true
+}
+
+// stopForUpdate saves and stops the server, with an in-game countdown if players are online
+func stopForUpdate(players int) error {
+	if players > 0 {
+		sendUpdateWarnings()
+	}
+	if config.GetIsSSCMEnabled() {
+		commandmgr.WriteCommand("SAVE")
+		time.Sleep(5 * time.Second)
+	}
+	if err := gamemgr.InternalStopServer(); err != nil {
+		logger.Install.Error("❌ Failed to stop the server for the game update: " + err.Error())
+		return err
+	}
+	return nil
+}
+
+func restartIf(wasRunning bool) {
+	if !wasRunning {
+		return
+	}
+	if err := gamemgr.InternalStartServer(); err != nil {
+		logger.Install.Error("❌ Failed to start the server after the game update: " + err.Error())
+	}
+}
+
+// sendUpdateWarnings counts down in-game like the auto restart does, the lead time is AutoRestartCountdown
+func sendUpdateWarnings() {
+	if !config.GetIsSSCMEnabled() {
+		return
+	}
+	lead, err := strconv.Atoi(config.GetAutoRestartCountdown())
+	if err != nil || lead < 10 {
+		lead = 60
+	}
+	logger.Install.Infof("❗Stopping server for the game update in %d seconds...", lead)
+	for remaining := lead; remaining > 0; {
+		commandmgr.WriteCommand(fmt.Sprintf("announce Game server update, stopping server in %d seconds...", remaining))
+		step := min(10, remaining)
+		if remaining > 30 {
+			step = remaining - 30 // 30 seconds ahead, then every 10
+		}
+		time.Sleep(time.Duration(step) * time.Second)
+		remaining -= step
+	}
+	commandmgr.WriteCommand("announce Game server update, saving and STOPPING SERVER NOW.")
+}
@@ -0,0 +1,34 @@
+package gameupdatemgr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateDue(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2025, 1, 1, hour, minute, 0, 0, time.Local) }
+	tests := []struct {
+		name         string
+		now          time.Time
+		running      bool
+		players      int
+		waitForEmpty bool
+		window       string
+		due          bool
+	}{
+		{"stopped server", at(12, 0), false, 0, true, "03:00-05:00", true},
+		{"no conditions", at(12, 0), true, 4, false, "", true},
+		{"empty server", at(12, 0), true, 0, true, "", true},
+		{"players online", at(12, 0), true, 2, true, "", false},
+		{"players online in window", at(4, 0), true, 2, true, "03:00-05:00", true},
+		{"players online outside window", at(5, 0), true, 2, true, "03:00-05:00", false},
+		{"window only, empty but closed", at(12, 0), true, 0, false, "03:00-05:00", false},
+		{"window wrapping midnight", at(0, 30), true, 1, false, "23:00-01:00", true},
+		{"outside window wrapping midnight", at(22, 59), true, 1, false, "23:00-01:00", false},
+	}
+	for _, tt := range tests {
+		if due, reason := updateDue(tt.now, tt.running, tt.players, tt.waitForEmpty, tt.window); due != tt.due {
+			t.Errorf("%s: got %v (%s), want %v", tt.name, due, reason, tt.due)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package gameupdatemgr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/backupmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
+)
+
+// a snapshot holds a full copy of the game, so only the last few are kept
+const snapshotsToKeep = 2
+
+const snapshotMetaFile = "snapshot.json"
+
+// restoreSuffix marks the staged copy of an install path while a snapshot is restored
+const restoreSuffix = ".ssui-restore"
+
+// Snapshot is a copy of the installed game server, taken before switching branches
+type Snapshot struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Branch      string    `json:"branch"`
+	BuildID     string    `json:"buildId"`
+	WorldBackup string    `json:"worldBackup,omitempty"` // pinned world save backup taken with the snapshot
+	Paths       []string  `json:"paths"`                 // copied install paths, relative to the server directory
+}
+
+// installPaths lists what makes up an install: the executable, its managed data, the Unity player and the app manifest
+// SteamCMD reads the installed build from
+func installPaths() []string {
+	exe := filepath.Clean(config.GetExePath())
+	if filepath.IsAbs(exe) {
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, exe); err == nil {
+				exe = rel
+			}
+		}
+	}
+	paths := []string{
+		exe,
+		strings.TrimSuffix(exe, filepath.Ext(exe)) + "_Data",
+		"UnityPlayer.so",
+		"UnityPlayer.dll",
+		filepath.Join("steamapps", "appmanifest_"+config.GetGameServerAppID()+".acf"),
+	}
+	// never snapshot or restore anything outside the server directory
+	return slices.DeleteFunc(paths, func(path string) bool {
+		return filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator))
+	})
+}
+
+// createSnapshot copies the install and backs up the world save, a cancelled ctx drops the partial snapshot
+func createSnapshot(ctx context.Context) (Snapshot, error) {
+	// a SteamCMD run rewrites the install, a copy taken meanwhile would be a corrupt rollback point
+	if steamcmd.IsUpdating() {
+		return Snapshot{}, fmt.Errorf("SteamCMD is running, try again when it is done")
+	}
+	buildID, err := steamcmd.InstalledBuildID()
+	if err != nil {
+		buildID = config.GetCurrentBranchBuildID()
+	}
+	snapshot := Snapshot{
+		ID:        time.Now().Format("2006-01-02_15-04-05"),
+		CreatedAt: time.Now(),
+		Branch:    config.GetGameBranch(),
+		BuildID:   buildID,
+	}
+	dir := filepath.Join(config.GetInstallSnapshotsFolder(), snapshot.ID)
+
+	logger.Install.Info("📸 Taking a snapshot of the game server install (branch " + snapshot.Branch + ", build " + snapshot.BuildID + ")...")
+	for _, path := range installPaths() {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := copyPath(ctx, path, filepath.Join(dir, "files", path)); err != nil {
+			os.RemoveAll(dir)
+			return Snapshot{}, fmt.Errorf("failed to snapshot %s: %w", path, err)
+		}
+		snapshot.Paths = append(snapshot.Paths, path)
+	}
+	if len(snapshot.Paths) == 0 {
+		return Snapshot{}, fmt.Errorf("no game server files found to snapshot, check ExePath")
+	}
+
+	if backupmgr.GlobalBackupManager != nil {
+		if snapshot.WorldBackup, err = backupmgr.GlobalBackupManager.BackupWorldSave("pre-branch-switch"); err != nil {
+			os.RemoveAll(dir)
+			return Snapshot{}, fmt.Errorf("failed to back up the world save: %w", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		os.RemoveAll(dir)
+		return Snapshot{}, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotMetaFile), data, 0644); err != nil {
+		os.RemoveAll(dir)
+		return Snapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	logger.Install.Info("✅ Snapshot " + snapshot.ID + " saved")
+	pruneSnapshots()
+	return snapshot, nil
+}
+
+// ListSnapshots returns the install snapshots, newest first
+func ListSnapshots() []Snapshot {
+	entries, err := os.ReadDir(config.GetInstallSnapshotsFolder())
+	if err != nil {
+		return nil
+	}
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(config.GetInstallSnapshotsFolder(), entry.Name(), snapshotMetaFile))
+		if err != nil {
+			continue // unfinished snapshot
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil || snapshot.ID != entry.Name() {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	slices.SortFunc(snapshots, func(a, b Snapshot) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return snapshots
+}
+
+// pruneSnapshots deletes all but the newest snapshotsToKeep snapshots and unpins their world backups
+func pruneSnapshots() {
+	snapshots := ListSnapshots()
+	for _, snapshot := range snapshots[min(snapshotsToKeep, len(snapshots)):] {
+		if err := os.RemoveAll(filepath.Join(config.GetInstallSnapshotsFolder(), snapshot.ID)); err != nil {
+			logger.Install.Warn("⚠️ Failed to delete old snapshot " + snapshot.ID + ": " + err.Error())
+			continue
+		}
+		logger.Install.Debug("🗑️ Deleted old snapshot " + snapshot.ID)
+		if snapshot.WorldBackup != "" && backupmgr.GlobalBackupManager != nil {
+			if err := backupmgr.GlobalBackupManager.UnpinBackupFile(snapshot.WorldBackup); err != nil {
+				logger.Install.Warn("⚠️ Failed to unpin the world backup of snapshot " + snapshot.ID + ": " + err.Error())
+			}
+		}
+	}
+}
+
+// restoreSnapshotFiles replaces the install paths with their copies from the snapshot and restores the world save
+// The copies are staged next to the install first, so cancelling ctx while copying leaves the install untouched
+func restoreSnapshotFiles(ctx context.Context, snapshot Snapshot) error {
+	dir := filepath.Join(config.GetInstallSnapshotsFolder(), snapshot.ID, "files")
+	for _, path := range snapshot.Paths {
+		if !slices.Contains(installPaths(), path) {
+			return fmt.Errorf("snapshot path %s is not part of the install", path)
+		}
+	}
+	defer func() {
+		for _, path := range snapshot.Paths {
+			os.RemoveAll(path + restoreSuffix)
+		}
+	}()
+	for _, path := range snapshot.Paths {
+		os.RemoveAll(path + restoreSuffix)
+		if err := copyPath(ctx, filepath.Join(dir, path), path+restoreSuffix); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+	}
+	for _, path := range snapshot.Paths {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		if err := os.Rename(path+restoreSuffix, path); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+	}
+
+	if snapshot.WorldBackup != "" && backupmgr.GlobalBackupManager != nil {
+		// the save may have been played on the other build, keep it too
+		if _, err := backupmgr.GlobalBackupManager.BackupWorldSave("pre-rollback"); err != nil {
+			return fmt.Errorf("failed to back up the world save: %w", err)
+		}
+		if err := backupmgr.GlobalBackupManager.RestoreWorldSave(snapshot.WorldBackup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyPath copies a file or directory tree, keeping execute permissions, and stops between files once ctx is cancelled
+func copyPath(ctx context.Context, src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+			return err
+		}
+		return copyFile(ctx, src, dst, info.Mode().Perm())
+	}
+	return filepath.WalkDir(src, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, os.ModePerm)
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			return copyFile(ctx, path, target, info.Mode().Perm())
+		default:
+			return fmt.Errorf("cannot copy %s, it is not a regular file", path)
+		}
+	})
+}
+
+// copyFile copies one file unless ctx is already cancelled
+func copyFile(ctx context.Context, src, dst string, perm os.FileMode) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	destination, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		return err
+	}
+	return destination.Close()
+}
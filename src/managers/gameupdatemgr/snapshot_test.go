@@ -0,0 +1,80 @@
+package gameupdatemgr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	t.Chdir(t.TempDir())
+	config.ExePath = "./rocketstation_DedicatedServer.x86_64"
+	config.InstallSnapshotsFolder = "snapshots"
+	config.GameServerAppID = "600760"
+
+	write := func(path, content string) {
+		os.MkdirAll(filepath.Dir(path), os.ModePerm)
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("rocketstation_DedicatedServer.x86_64", "old exe")
+	write("rocketstation_DedicatedServer_Data/Managed/Assembly-CSharp.dll", "old dll")
+	write("steamapps/appmanifest_600760.acf", `"buildid" "100"`)
+
+	snapshot, err := createSnapshot(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.BuildID != "100" || len(snapshot.Paths) != 3 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	write("rocketstation_DedicatedServer.x86_64", "new exe")
+	write("rocketstation_DedicatedServer_Data/Managed/Assembly-CSharp.dll", "new dll")
+	write("rocketstation_DedicatedServer_Data/Managed/New.dll", "added")
+	if err := restoreSnapshotFiles(t.Context(), ListSnapshots()[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile("rocketstation_DedicatedServer_Data/Managed/Assembly-CSharp.dll"); string(data) != "old dll" {
+		t.Errorf("managed data not restored, got %q", data)
+	}
+	if _, err := os.Stat("rocketstation_DedicatedServer_Data/Managed/New.dll"); !os.IsNotExist(err) {
+		t.Error("files added by the update must be removed")
+	}
+	if info, err := os.Stat("rocketstation_DedicatedServer.x86_64"); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("executable not restored with its permissions: %v", err)
+	}
+}
+
+func TestCancelledRestoreLeavesInstall(t *testing.T) {
+	t.Chdir(t.TempDir())
+	config.ExePath = "./rocketstation_DedicatedServer.x86_64"
+	config.InstallSnapshotsFolder = "snapshots"
+	config.GameServerAppID = "600760"
+
+	os.MkdirAll("rocketstation_DedicatedServer_Data/Managed", os.ModePerm)
+	os.WriteFile("rocketstation_DedicatedServer.x86_64", []byte("old exe"), 0755)
+	os.WriteFile("rocketstation_DedicatedServer_Data/Managed/Assembly-CSharp.dll", []byte("old dll"), 0644)
+	if _, err := createSnapshot(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile("rocketstation_DedicatedServer_Data/Managed/Assembly-CSharp.dll", []byte("new dll"), 0644)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if err := restoreSnapshotFiles(ctx, ListSnapshots()[0]); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled restore to fail, got %v", err)
+	}
+	if data, _ := os.ReadFile("rocketstation_DedicatedServer_Data/Managed/Assembly-CSharp.dll"); string(data) != "new dll" {
+		t.Errorf("a cancelled restore must not touch the install, got %q", data)
+	}
+	if _, err := os.Stat("rocketstation_DedicatedServer_Data" + restoreSuffix); !os.IsNotExist(err) {
+		t.Error("the staged copy must be cleaned up")
+	}
+}
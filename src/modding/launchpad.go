@@ -5,131 +5,167 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
-	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/update"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/artifacts"
 )
 
-// InstallSLP downloads the latest StationeersLaunchPad-server zip from GitHub
-// and extracts it into BepInEx/plugins/StationeersLaunchPad
-// Returns: (installed version tag or "", error)
-func InstallSLP() (string, error) {
+const (
+	slpPluginsDir = "BepInEx/plugins"
+	slpDir        = slpPluginsDir + "/StationeersLaunchPad"
+)
+
+// resolveSLPRelease refreshes the cached SLP release list and picks the release config.SLPVersion selects.
+// Without GitHub the cached list is used, so "latest" is the newest release the cache knows of.
+func resolveSLPRelease() (slpRelease, slpAsset, error) {
 	const repoOwner = "StationeersLaunchPad"
 	const repoName = "StationeersLaunchPad"
-	const slpAssetPattern = "StationeersLaunchPad-server-"
 
-	// Prepare target folder
-	pluginsDir := "BepInEx/plugins"
-	slpDir := filepath.Join(pluginsDir, "StationeersLaunchPad")
+	version := config.GetSLPVersion()
+	baseURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?per_page=100", repoOwner, repoName)
+	logger.Install.Info(fmt.Sprintf("📡 Fetching Stationeers Launch Pad releases (%s)...", version))
 
-	baseURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", repoOwner, repoName)
-	logger.Install.Info("📡 Fetching latest Stationeers Launch Pad release...")
-
-	resp, err := http.Get(baseURL)
+	cached, _, err := artifacts.Refresh(artifacts.Artifact{Name: "StationeersLaunchPad-releases.json", URL: baseURL, Mutable: true})
+	if err != nil {
+		return slpRelease{}, slpAsset{}, fmt.Errorf("failed to query GitHub API: %w", err)
+	}
+	data, err := os.ReadFile(cached)
 	if err != nil {
-		return "", fmt.Errorf("failed to query GitHub API: %w", err)
+		return slpRelease{}, slpAsset{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("GitHub API returned %s", resp.Status)
+	var releases []slpRelease
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return slpRelease{}, slpAsset{}, fmt.Errorf("failed to parse GitHub releases: %w", err)
 	}
 
-	var releases []struct {
-		TagName    string `json:"tag_name"`
-		Prerelease bool   `json:"prerelease"`
-		Assets     []struct {
-			Name string `json:"name"`
-			URL  string `json:"browser_download_url"`
-		} `json:"assets"`
+	if len(releases) == 0 {
+		return slpRelease{}, slpAsset{}, fmt.Errorf("no releases found in %s/%s", repoOwner, repoName)
 	}
+	return selectSLPRelease(releases, version)
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return "", fmt.Errorf("failed to parse GitHub releases: %w", err)
+// artifact is the cache entry of the asset, with the size and checksum GitHub lists so a bad mirror copy is not cached
+func (asset slpAsset) artifact() artifacts.Artifact {
+	sum, ok := strings.CutPrefix(asset.Digest, "sha256:")
+	if !ok {
+		sum = "" // no digest, or not a sha256 one
 	}
+	return artifacts.Artifact{Name: asset.Name, URL: asset.URL, Size: asset.Size, SHA256: sum}
+}
 
-	if len(releases) == 0 {
-		return "", fmt.Errorf("no releases found in %s/%s", repoOwner, repoName)
+// SLPArtifacts refreshes the cached SLP release list and returns the server zip config.SLPVersion selects, for populating the artifact cache
+func SLPArtifacts() ([]artifacts.Artifact, error) {
+	_, asset, err := resolveSLPRelease()
+	if err != nil {
+		return nil, err
 	}
+	return []artifacts.Artifact{asset.artifact()}, nil
+}
 
-	// Find newest non-prerelease release with server zip
-	var selectedRelease *struct {
-		TagName string
-		URL     string
+// InstallSLP fetches the StationeersLaunchPad-server zip of the release config.SLPVersion selects through the artifact cache,
+// verifies it and extracts it into BepInEx/plugins/StationeersLaunchPad. An existing install is kept for a rollback.
+// Returns: (installed version tag or "", error)
+func InstallSLP() (string, error) {
+	version := config.GetSLPVersion()
+	release, asset, err := resolveSLPRelease()
+	if err != nil {
+		return "", err
 	}
 
-	for _, rel := range releases {
-		if rel.Prerelease {
-			continue // skip prereleases for now (can be made configurable later)
-		}
+	logger.Install.Info(fmt.Sprintf("Found SLP %s → fetching %s...", release.TagName, asset.Name))
+	zipPath, _, err := artifacts.Fetch(asset.artifact())
+	if err != nil {
+		return "", fmt.Errorf("failed to download SLP zip: %w", err)
+	}
 
-		for _, asset := range rel.Assets {
-			if strings.HasPrefix(asset.Name, slpAssetPattern) &&
-				strings.HasSuffix(asset.Name, ".zip") {
-				selectedRelease = &struct {
-					TagName string
-					URL     string
-				}{rel.TagName, asset.URL}
-				break
-			}
-		}
-		if selectedRelease != nil {
-			break
+	install, err := verifySLPDownload(zipPath, asset)
+	if err != nil {
+		if removeErr := artifacts.Remove(asset.Name); removeErr != nil { // do not install the bad copy again
+			logger.Install.Warn(removeErr.Error())
 		}
+		return "", fmt.Errorf("SLP download failed verification: %w", err)
 	}
-
-	if selectedRelease == nil {
-		return "", fmt.Errorf("no suitable StationeersLaunchPad-server-*.zip found in latest releases")
+	if install.Verified == slpVerifiedNone {
+		logger.Install.Warn("GitHub lists no size or checksum for " + asset.Name + ", installing it unverified")
 	}
+	install.Version, install.Prerelease = release.TagName, release.Prerelease
 
-	zipName := fmt.Sprintf("StationeersLaunchPad-server-%s.zip", selectedRelease.TagName)
-	downloadURL := selectedRelease.URL
-
-	logger.Install.Info(fmt.Sprintf("Found SLP %s → downloading %s...", selectedRelease.TagName, zipName))
+	slpMu.Lock()
+	defer slpMu.Unlock()
 
-	// Download to temp file
-	tmpZip := zipName + ".tmp"
-	if err := downloadFile(tmpZip, downloadURL); err != nil {
-		return "", fmt.Errorf("failed to download SLP zip: %w", err)
+	record, err := loadSLPInstallRecord()
+	if err != nil {
+		logger.Install.Warn(err.Error())
 	}
-	defer os.Remove(tmpZip)
 
-	// Clean previous installation if exists
-	if err := os.RemoveAll(slpDir); err != nil {
-		logger.Install.Warn(fmt.Sprintf("Could not clean old SLP folder: %v", err))
+	// Keep the previous installation for a rollback
+	backedUp, err := backupSLP()
+	if err != nil {
+		logger.Install.Warn(fmt.Sprintf("Could not keep the old SLP folder for a rollback: %v", err))
+		if err := os.RemoveAll(slpDir); err != nil {
+			logger.Install.Warn(fmt.Sprintf("Could not clean old SLP folder: %v", err))
+		}
 	}
 
 	// Make sure parent exists
-	if err := os.MkdirAll(pluginsDir, 0755); err != nil {
+	if err := os.MkdirAll(slpPluginsDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create BepInEx/plugins directory: %w", err)
 	}
 
 	// Extract
 	logger.Install.Info("📦 Extracting Stationeers Launch Pad...")
-	if err := unzipTo(tmpZip, slpDir, func(name string) bool {
+	if err := unzipTo(zipPath, slpDir, func(name string) bool {
 		// Only process files inside the StationeersLaunchPad folder
 		return strings.HasPrefix(name, "StationeersLaunchPad/")
 	}); err != nil {
+		if backedUp {
+			restoreSLPBackup()
+		}
 		return "", fmt.Errorf("failed to extract SLP: %w", err)
 	}
 
-	logger.Install.Info(fmt.Sprintf("✅ Stationeers Launch Pad %s installed to %s", selectedRelease.TagName, slpDir))
-	logger.Install.Info("💡 SLP contains its own auto-updater — future updates should happen automatically.")
+	record.Previous = nil
+	if backedUp {
+		record.Previous = record.Current
+		if record.Previous == nil { // installed before install records were kept
+			record.Previous = &SLPInstall{Version: "unknown", Verified: slpVerifiedNone, Confirmed: true}
+		}
+	}
+	install.InstalledAt = time.Now()
+	record.Current = &install
+	slpChecking = false
+	if err := saveSLPInstallRecord(record); err != nil {
+		logger.Install.Warn(err.Error())
+	}
+
+	logger.Install.Info(fmt.Sprintf("✅ Stationeers Launch Pad %s installed to %s (verified: %s)", release.TagName, slpDir, install.Verified))
+	if backedUp {
+		logger.Install.Info(fmt.Sprintf("💾 SLP %s is kept and restored if the server does not reach ready with %s", record.Previous.Version, release.TagName))
+	}
+	if version != SLPChannelLatest && version != SLPChannelPrerelease && config.GetIsStationeersLaunchPadAutoUpdatesEnabled() {
+		logger.Install.Warn("SLP is pinned to " + version + " but its own auto-updater is enabled and may update past the pin")
+	} else {
+		logger.Install.Info("💡 SLP contains its own auto-updater — future updates should happen automatically.")
+	}
 	config.SetIsStationeersLaunchPadEnabled(true)
 
-	return selectedRelease.TagName, nil
+	return release.TagName, nil
 }
 
 // UninstallSLP removes the SLP folder from BepInEx/plugins
 // Returns: ("success" or "failed", error)
 func UninstallSLP() (string, error) {
-	pluginsDir := "BepInEx/plugins"
-	slpDir := filepath.Join(pluginsDir, "StationeersLaunchPad")
+	slpMu.Lock()
+	defer slpMu.Unlock()
+
+	removeSLPInstallRecord()
+	slpChecking = false
 
 	// stat the folder to see if it exists, if not skip removal
 	if _, err := os.Stat(slpDir); os.IsNotExist(err) {
@@ -172,53 +208,19 @@ func UninstallSLP() (string, error) {
 	return "success", nil
 }
 
-// ReinstallSLP removes only the SLP plugin directory (preserving mods and modconfig.xml),
-// then downloads and installs the latest version.
+// ReinstallSLP installs SLP again, preserving mods and modconfig.xml. The existing
+// SLP plugin directory is kept as the previous install for a rollback.
 // Returns: (installed version tag or "", error)
 func ReinstallSLP() (string, error) {
-	pluginsDir := "BepInEx/plugins"
-	slpDir := filepath.Join(pluginsDir, "StationeersLaunchPad")
-
-	// Remove only the SLP plugin directory, keep mods & modconfig.xml
 	if _, err := os.Stat(slpDir); err == nil {
-		logger.Install.Info("🔄 Removing existing SLP installation for reinstall...")
-		if err := os.RemoveAll(slpDir); err != nil {
-			return "", fmt.Errorf("failed to remove SLP folder for reinstall: %w", err)
-		}
+		logger.Install.Info("🔄 Replacing existing SLP installation...")
 	} else {
 		logger.Install.Info("SLP not currently installed; proceeding with fresh install")
 	}
 
-	// Now install fresh
 	return InstallSLP()
 }
 
-func downloadFile(destPath, url string) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-
-	out, err := os.Create(destPath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
-	counter := &update.WriteCounter{Total: resp.ContentLength}
-	_, err = io.Copy(out, io.TeeReader(resp.Body, counter))
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // unzipTo extracts zip contents into destDir
 // Only extracts files where shouldExtract returns true
 func unzipTo(zipPath, destDir string, shouldExtract func(fileName string) bool) error {
@@ -0,0 +1,276 @@
+package modding
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+/*
+Declarative workshop mod list
+- The mod manifest lists the workshop mods the server runs, in load order, each enabled or disabled and optionally pinned to a version
+- A sync (steamcmd.StartModSync) downloads missing and outdated mods, removes workshop mods that are not listed,
+  adds the dependencies declared in About.xml and writes modconfig.xml
+- Pinned mods are only downloaded when they are missing, the workshop only serves the latest version
+- Without a manifest file the installed workshop mods are the manifest, so existing servers keep their mods
+*/
+
+// ManifestMod is one workshop mod of the manifest
+type ManifestMod struct {
+	WorkshopID    string `json:"workshopId"`
+	Name          string `json:"name,omitempty"` // filled in by sync, for humans reading the file
+	Enabled       bool   `json:"enabled"`
+	PinnedVersion string `json:"pinnedVersion,omitempty"` // About.xml version to keep, the mod is not updated while pinned
+	Dependency    bool   `json:"dependency,omitempty"`    // added by sync because another mod depends on it
+}
+
+// ModManifest is the declared set of workshop mods, Mods is in load order
+type ModManifest struct {
+	Mods []ManifestMod `json:"mods"`
+}
+
+// SyncPlan is what a sync has to do to make ./mods match the manifest
+type SyncPlan struct {
+	Download []string `json:"download"` // workshop IDs that are missing or may be updated
+	Remove   []string `json:"remove"`   // folders of installed workshop mods that are not in the manifest
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+var manifestMu sync.Mutex
+
+// LoadModManifest reads the manifest, or builds it from the installed workshop mods if there is no manifest file yet
+func LoadModManifest() (ModManifest, error) {
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+	return loadModManifest()
+}
+
+func loadModManifest() (ModManifest, error) {
+	data, err := os.ReadFile(config.GetModManifestFilePath())
+	if os.IsNotExist(err) {
+		var manifest ModManifest
+		for _, mod := range listMods(false) {
+			if mod.WorkshopHandle != "" && manifest.index(mod.WorkshopHandle) < 0 {
+				manifest.Mods = append(manifest.Mods, ManifestMod{WorkshopID: mod.WorkshopHandle, Name: mod.Name, Enabled: true})
+			}
+		}
+		return manifest, nil
+	}
+	if err != nil {
+		return ModManifest{}, fmt.Errorf("failed to read mod manifest: %w", err)
+	}
+	var manifest ModManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return ModManifest{}, fmt.Errorf("failed to parse mod manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// SaveModManifest validates and writes the manifest
+func SaveModManifest(manifest ModManifest) error {
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+	return saveModManifest(manifest)
+}
+
+func saveModManifest(manifest ModManifest) error {
+	if manifest.Mods == nil {
+		manifest.Mods = []ManifestMod{}
+	}
+	path := config.GetModManifestFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create mod manifest directory: %w", err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode mod manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write mod manifest: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that every workshop ID is numeric and listed once
+func (m ModManifest) Validate() error {
+	seen := make(map[string]bool)
+	for _, mod := range m.Mods {
+		if id, err := strconv.ParseUint(mod.WorkshopID, 10, 64); err != nil || id == 0 {
+			return fmt.Errorf("invalid workshop ID %q: must be a positive integer", mod.WorkshopID)
+		}
+		if seen[mod.WorkshopID] {
+			return fmt.Errorf("workshop ID %s is listed more than once", mod.WorkshopID)
+		}
+		seen[mod.WorkshopID] = true
+	}
+	return nil
+}
+
+// index returns the position of a workshop ID in the manifest, -1 if it is not listed
+func (m ModManifest) index(workshopID string) int {
+	return slices.IndexFunc(m.Mods, func(mod ManifestMod) bool { return mod.WorkshopID == workshopID })
+}
+
+// PlanModSync compares the manifest with the installed mods
+func PlanModSync(manifest ModManifest) SyncPlan {
+	return planModSync(manifest, listMods(false))
+}
+
+func planModSync(manifest ModManifest, installed []ModMetadata) SyncPlan {
+	plan := SyncPlan{Download: []string{}, Remove: []string{}, Warnings: duplicateHandleWarnings(installed)}
+	byHandle := installedByHandle(installed)
+	for _, mod := range manifest.Mods {
+		current, ok := byHandle[mod.WorkshopID]
+		switch {
+		case !ok || mod.PinnedVersion == "":
+			plan.Download = append(plan.Download, mod.WorkshopID)
+		case current.Version != mod.PinnedVersion:
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s is pinned to version %s but version %s is installed, the workshop only serves the latest version",
+				modLabel(mod, current), mod.PinnedVersion, current.Version))
+		}
+	}
+	for _, mod := range installed {
+		if mod.WorkshopHandle != "" && manifest.index(mod.WorkshopHandle) < 0 {
+			plan.Remove = append(plan.Remove, mod.Folder)
+		}
+	}
+	return plan
+}
+
+// ResolveModSync adds the dependencies of the installed manifest mods and then plans the sync,
+// so missing dependencies are downloaded even when every listed mod is pinned and installed
+func ResolveModSync(manifest *ModManifest) (plan SyncPlan, added []string) {
+	return resolveModSync(manifest, listMods(false))
+}
+
+func resolveModSync(manifest *ModManifest, installed []ModMetadata) (SyncPlan, []string) {
+	added, warnings := addDependencies(manifest, installed)
+	plan := planModSync(*manifest, installed)
+	plan.Warnings = append(plan.Warnings, warnings...)
+	return plan, added
+}
+
+// AddDependencies adds the dependencies of the manifest's enabled mods that are not listed yet.
+// It returns the added workshop IDs, which still have to be downloaded, and warnings about disabled dependencies.
+func AddDependencies(manifest *ModManifest) (added []string, warnings []string) {
+	return addDependencies(manifest, listMods(false))
+}
+
+func addDependencies(manifest *ModManifest, installed []ModMetadata) (added []string, warnings []string) {
+	byHandle := installedByHandle(installed)
+	for i := 0; i < len(manifest.Mods); i++ { // grows while dependencies are added
+		mod := manifest.Mods[i]
+		if !mod.Enabled {
+			continue
+		}
+		for _, dependency := range byHandle[mod.WorkshopID].Dependencies {
+			if _, err := strconv.ParseUint(dependency, 10, 64); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s declares dependency %q, which is not a workshop ID", modLabel(mod, byHandle[mod.WorkshopID]), dependency))
+				continue
+			}
+			switch index := manifest.index(dependency); {
+			case index < 0:
+				manifest.Mods = append(manifest.Mods, ManifestMod{WorkshopID: dependency, Enabled: true, Dependency: true})
+				added = append(added, dependency)
+				logger.Modding.Infof("➕ Adding workshop mod %s, %s depends on it", dependency, modLabel(mod, byHandle[mod.WorkshopID]))
+			case !manifest.Mods[index].Enabled:
+				warnings = append(warnings, fmt.Sprintf("%s depends on %s, which is disabled",
+					modLabel(mod, byHandle[mod.WorkshopID]), modLabel(manifest.Mods[index], byHandle[dependency])))
+			}
+		}
+	}
+	return added, warnings
+}
+
+// LoadOrder returns the enabled mods in manifest order, moving dependencies before the mods that need them
+func LoadOrder(manifest ModManifest) ([]ManifestMod, []string) {
+	return loadOrder(manifest, listMods(false))
+}
+
+func loadOrder(manifest ModManifest, installed []ModMetadata) (order []ManifestMod, warnings []string) {
+	byHandle := installedByHandle(installed)
+	placed := make(map[string]bool)
+	visiting := make(map[string]bool)
+	var place func(mod ManifestMod)
+	place = func(mod ManifestMod) {
+		if placed[mod.WorkshopID] {
+			return
+		}
+		if visiting[mod.WorkshopID] {
+			warnings = append(warnings, "dependency cycle at "+modLabel(mod, byHandle[mod.WorkshopID])+", keeping the manifest order")
+			return
+		}
+		visiting[mod.WorkshopID] = true
+		for _, dependency := range byHandle[mod.WorkshopID].Dependencies {
+			if index := manifest.index(dependency); index >= 0 && manifest.Mods[index].Enabled {
+				place(manifest.Mods[index])
+			}
+		}
+		visiting[mod.WorkshopID] = false
+		if !placed[mod.WorkshopID] {
+			placed[mod.WorkshopID] = true
+			order = append(order, mod)
+		}
+	}
+	for _, mod := range manifest.Mods {
+		if mod.Enabled {
+			place(mod)
+		}
+	}
+	return order, warnings
+}
+
+// GetUpdatableWorkshopHandles returns the workshop handles of the installed mods that are not pinned in the manifest
+func GetUpdatableWorkshopHandles() []string {
+	manifest, err := LoadModManifest()
+	if err != nil {
+		logger.Modding.Warn("Ignoring version pins: " + err.Error())
+	}
+	return slices.DeleteFunc(GetModWorkshopHandles(), func(handle string) bool {
+		index := manifest.index(handle)
+		return index >= 0 && manifest.Mods[index].PinnedVersion != ""
+	})
+}
+
+// UpdateManifestNames fills in the names of the manifest's mods from their About.xml and saves it
+func UpdateManifestNames(manifest ModManifest) error {
+	byHandle := installedByHandle(listMods(false))
+	for i, mod := range manifest.Mods {
+		if installed, ok := byHandle[mod.WorkshopID]; ok {
+			manifest.Mods[i].Name = installed.Name
+		}
+	}
+	return SaveModManifest(manifest)
+}
+
+// installedByHandle indexes the installed workshop mods, the first folder wins if a handle is installed twice
+func installedByHandle(installed []ModMetadata) map[string]ModMetadata {
+	byHandle := make(map[string]ModMetadata)
+	for _, mod := range installed {
+		if _, ok := byHandle[mod.WorkshopHandle]; mod.WorkshopHandle != "" && !ok {
+			byHandle[mod.WorkshopHandle] = mod
+		}
+	}
+	return byHandle
+}
+
+// modLabel names a mod for messages
+func modLabel(mod ManifestMod, installed ModMetadata) string {
+	switch {
+	case installed.Name != "":
+		return installed.Name + " (" + mod.WorkshopID + ")"
+	case mod.Name != "":
+		return mod.Name + " (" + mod.WorkshopID + ")"
+	}
+	return "workshop mod " + mod.WorkshopID
+}
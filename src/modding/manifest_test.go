@@ -0,0 +1,99 @@
+package modding
+
+import (
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testInstalled = []ModMetadata{
+	{Name: "Base", Version: "1.0", WorkshopHandle: "100", Folder: "mods/Workshop_100"},
+	{Name: "Addon", Version: "2.0", WorkshopHandle: "200", Folder: "mods/Workshop_200", Dependencies: []string{"100", "300"}},
+	{Name: "Stale", Version: "1.0", WorkshopHandle: "400", Folder: "mods/Workshop_400"},
+	{Name: "Uploaded", Folder: "mods/Uploaded"},
+}
+
+func TestPlanModSync(t *testing.T) {
+	manifest := ModManifest{Mods: []ManifestMod{
+		{WorkshopID: "200", Enabled: true},
+		{WorkshopID: "100", Enabled: true, PinnedVersion: "0.9"},
+		{WorkshopID: "500", Enabled: true, PinnedVersion: "1.0"},
+	}}
+	plan := planModSync(manifest, testInstalled)
+	if !slices.Equal(plan.Download, []string{"200", "500"}) {
+		t.Errorf("download: %v", plan.Download)
+	}
+	if !slices.Equal(plan.Remove, []string{"mods/Workshop_400"}) {
+		t.Errorf("remove: %v", plan.Remove)
+	}
+	if len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "pinned to version 0.9") {
+		t.Errorf("warnings: %v", plan.Warnings)
+	}
+}
+
+func TestDependenciesAndLoadOrder(t *testing.T) {
+	manifest := ModManifest{Mods: []ManifestMod{{WorkshopID: "200", Enabled: true}, {WorkshopID: "100", Enabled: true}}}
+	added, warnings := addDependencies(&manifest, testInstalled)
+	if !slices.Equal(added, []string{"300"}) || len(warnings) != 0 {
+		t.Fatalf("added %v, warnings %v", added, warnings)
+	}
+	if mod := manifest.Mods[2]; !mod.Enabled || !mod.Dependency {
+		t.Errorf("dependency entry: %+v", mod)
+	}
+
+	order, warnings := loadOrder(manifest, testInstalled)
+	var ids []string
+	for _, mod := range order {
+		ids = append(ids, mod.WorkshopID)
+	}
+	if !slices.Equal(ids, []string{"100", "300", "200"}) || len(warnings) != 0 {
+		t.Errorf("load order %v, warnings %v", ids, warnings)
+	}
+
+	manifest.Mods[0].Enabled, manifest.Mods[1].Enabled = true, false
+	if _, warnings := addDependencies(&manifest, testInstalled); len(warnings) != 1 {
+		t.Errorf("expected a warning about the disabled dependency, got %v", warnings)
+	}
+}
+
+func TestResolveModSyncAddsDependenciesOfPinnedMods(t *testing.T) {
+	// everything listed is pinned and installed, only the missing dependency 300 has to be downloaded
+	manifest := ModManifest{Mods: []ManifestMod{
+		{WorkshopID: "200", Enabled: true, PinnedVersion: "2.0"},
+		{WorkshopID: "100", Enabled: true, PinnedVersion: "1.0"},
+	}}
+	plan, added := resolveModSync(&manifest, testInstalled)
+	if !slices.Equal(added, []string{"300"}) || !slices.Equal(plan.Download, []string{"300"}) {
+		t.Errorf("added %v, download %v", added, plan.Download)
+	}
+}
+
+func TestBuildModConfig(t *testing.T) {
+	manifest := ModManifest{Mods: []ManifestMod{
+		{WorkshopID: "100", Enabled: true}, {WorkshopID: "200", Enabled: true}, {WorkshopID: "500", Enabled: true}, {WorkshopID: "400"},
+	}}
+	order, _ := loadOrder(manifest, testInstalled)
+	var current modConfigXML
+	current.Mods.Entries = []modConfigEntry{{XMLName: xml.Name{Local: "Local"}, Path: `C:\server\mods\Uploaded`}}
+
+	modConfig, err := buildModConfig(current, manifest, order, testInstalled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := encodeModConfig(modConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xml := string(content)
+	for _, want := range []string{`<?xml version="1.0"`, `xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"`, `<Core Enabled="true">`,
+		`<Local Enabled="false">`, `<Path>C:\server\mods\Uploaded</Path>`} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("modconfig.xml is missing %s:\n%s", want, xml)
+		}
+	}
+	base, addon, stale, uploaded := strings.Index(xml, "Workshop_100"), strings.Index(xml, "Workshop_200"), strings.Index(xml, "Workshop_400"), strings.Index(xml, "Uploaded")
+	if base < 0 || addon < base || stale < addon || uploaded < stale {
+		t.Errorf("unexpected mod entries:\n%s", xml)
+	}
+}
@@ -0,0 +1,259 @@
+package modding
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+/*
+Mod compatibility tracking
+- Exception stack traces from the game log are matched against the installed mods. A frame belongs to a mod if its
+  namespace starts with the name of one of the mod's assemblies or if it mentions the mod's folder;
+  the mod closest to where the exception was thrown gets the error
+- Errors are counted per mod on the current build ID and mod version, a game update or a new mod version starts over
+- A mod with modIncompatibleThreshold errors on the current build is flagged as likely incompatible,
+  starting the server with flagged mods asks for confirmation in the web UI and on Discord
+*/
+
+const (
+	modIncompatibleThreshold = 5
+	modCompatSaveInterval    = 10 * time.Second // exceptions come in bursts, the counters are saved at most this often
+	modIdentifiersTTL        = time.Minute      // how long the assembly names of the installed mods are cached
+)
+
+// ModCompat is the error record of one mod
+type ModCompat struct {
+	Folder         string    `json:"folder"` // directory name in ./mods
+	Name           string    `json:"name,omitempty"`
+	WorkshopHandle string    `json:"workshopHandle,omitempty"`
+	Version        string    `json:"version,omitempty"` // mod version the errors were counted on
+	BuildID        string    `json:"buildId"`           // game build the errors were counted on
+	Errors         int       `json:"errors"`            // on BuildID and Version
+	TotalErrors    int       `json:"totalErrors"`
+	LastError      string    `json:"lastError,omitempty"` // first line of the last exception attributed to the mod
+	LastSeen       time.Time `json:"lastSeen"`
+	Flagged        bool      `json:"flagged"` // likely incompatible with BuildID
+}
+
+// ModException is an exception attributed to a mod
+type ModException struct {
+	Mod          ModCompat
+	NewlyFlagged bool // the mod reached modIncompatibleThreshold with this exception
+}
+
+// modIdentifiers are the lower case names a stack frame of a mod can start with
+type modIdentifiers struct {
+	mod        ModMetadata
+	folder     string
+	assemblies []string
+}
+
+var (
+	compatMu       sync.Mutex
+	compatRecords  map[string]*ModCompat // by folder, nil until loaded
+	compatSavedAt  time.Time
+	compatIdents   []modIdentifiers
+	compatIdentsAt time.Time
+
+	// dotted names in a frame, e.g. "MyMod.Patches.DoorPatch.Postfix" in "at MyMod.Patches.DoorPatch.Postfix () [0x00000]"
+	// or "MyMod.Patches.DoorPatch:Postfix" in Unity's "MyMod.Patches.DoorPatch:Postfix()"
+	qualifiedNamePattern = regexp.MustCompile("[A-Za-z_][\\w`<>]*(?:\\.[A-Za-z_<][\\w`<>]*)+")
+)
+
+// sharedAssemblyPrefixes are libraries mods ship next to their own assembly, frames in them do not point at the mod
+var sharedAssemblyPrefixes = []string{"0harmony", "system", "mono.", "microsoft.", "unity", "newtonsoft", "bepinex", "assembly-csharp", "stationeersmods", "launchpad"}
+
+// AttributeModException counts an exception against the mod it most likely comes from, false if no installed mod is in the stack trace
+func AttributeModException(stackTrace string) (ModException, bool) {
+	compatMu.Lock()
+	defer compatMu.Unlock()
+
+	if time.Since(compatIdentsAt) > modIdentifiersTTL {
+		compatIdents, compatIdentsAt = loadModIdentifiers(listMods(false)), time.Now()
+	}
+	mod, ok := attributeStackTrace(stackTrace, compatIdents)
+	if !ok {
+		return ModException{}, false
+	}
+	if err := loadModCompat(); err != nil {
+		logger.Modding.Warn("Mod error counters start empty: " + err.Error())
+	}
+
+	folder := folderName(mod.Folder)
+	buildID := config.GetCurrentBranchBuildID()
+	record, exists := compatRecords[folder]
+	if !exists {
+		record = &ModCompat{Folder: folder}
+		compatRecords[folder] = record
+	}
+	if record.BuildID != buildID || record.Version != mod.Version {
+		record.BuildID, record.Version, record.Errors, record.Flagged = buildID, mod.Version, 0, false
+	}
+	record.Name, record.WorkshopHandle = mod.Name, mod.WorkshopHandle
+	record.Errors++
+	record.TotalErrors++
+	record.LastError = strings.TrimSpace(strings.SplitN(stackTrace, "\n", 2)[0])
+	record.LastSeen = time.Now()
+
+	result := ModException{Mod: *record}
+	if !record.Flagged && record.Errors >= modIncompatibleThreshold {
+		record.Flagged = true
+		result.Mod.Flagged, result.NewlyFlagged = true, true
+	}
+	if result.NewlyFlagged || time.Since(compatSavedAt) > modCompatSaveInterval {
+		if err := saveModCompat(); err != nil {
+			logger.Modding.Warn(err.Error())
+		}
+	}
+	return result, true
+}
+
+// attributeStackTrace returns the mod of the frame closest to where the exception was thrown
+func attributeStackTrace(stackTrace string, idents []modIdentifiers) (ModMetadata, bool) {
+	for _, line := range strings.Split(stackTrace, "\n") {
+		lower := strings.ToLower(line)
+		names := qualifiedNamePattern.FindAllString(lower, -1)
+		for _, ident := range idents {
+			if len(ident.folder) >= 6 && strings.Contains(lower, ident.folder) { // short folder names are too likely to appear by chance
+				return ident.mod, true
+			}
+			for _, name := range names {
+				for _, assembly := range ident.assemblies {
+					if name == assembly || strings.HasPrefix(name, assembly+".") || strings.HasPrefix(name, assembly+":") {
+						return ident.mod, true
+					}
+				}
+			}
+		}
+	}
+	return ModMetadata{}, false
+}
+
+// loadModIdentifiers collects the assembly names of the installed mods
+func loadModIdentifiers(installed []ModMetadata) []modIdentifiers {
+	var idents []modIdentifiers
+	for _, mod := range installed {
+		ident := modIdentifiers{mod: mod, folder: strings.ToLower(folderName(mod.Folder))}
+		filepath.WalkDir(mod.Folder, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".dll") {
+				return nil
+			}
+			assembly := strings.ToLower(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+			shared := slices.ContainsFunc(sharedAssemblyPrefixes, func(prefix string) bool { return strings.HasPrefix(assembly, prefix) })
+			if !shared && len(assembly) >= 3 && !slices.Contains(ident.assemblies, assembly) {
+				ident.assemblies = append(ident.assemblies, assembly)
+			}
+			return nil
+		})
+		idents = append(idents, ident)
+	}
+	return idents
+}
+
+// GetModCompat returns the error records of the installed mods, most errors first. Records counted on
+// another build or mod version are reported with zero errors on the current one and are not flagged.
+func GetModCompat() ([]ModCompat, error) {
+	compatMu.Lock()
+	defer compatMu.Unlock()
+
+	if err := loadModCompat(); err != nil {
+		return nil, err
+	}
+	buildID := config.GetCurrentBranchBuildID()
+	records := []ModCompat{}
+	for _, mod := range listMods(false) {
+		record, ok := compatRecords[folderName(mod.Folder)]
+		if !ok {
+			continue
+		}
+		current := *record
+		if current.BuildID != buildID || current.Version != mod.Version {
+			current.BuildID, current.Version, current.Errors, current.Flagged = buildID, mod.Version, 0, false
+		}
+		records = append(records, current)
+	}
+	slices.SortStableFunc(records, func(a, b ModCompat) int { return b.Errors - a.Errors })
+	return records, nil
+}
+
+// GetFlaggedMods returns the installed mods that are likely incompatible with the current build
+func GetFlaggedMods() []ModCompat {
+	records, err := GetModCompat()
+	if err != nil {
+		logger.Modding.Warn(err.Error())
+	}
+	return slices.DeleteFunc(records, func(record ModCompat) bool { return !record.Flagged })
+}
+
+// ResetModCompat clears the error record of a mod folder, or of all mods if folder is empty
+func ResetModCompat(folder string) error {
+	compatMu.Lock()
+	defer compatMu.Unlock()
+
+	if err := loadModCompat(); err != nil {
+		return err
+	}
+	if folder == "" {
+		clear(compatRecords)
+	} else if _, ok := compatRecords[folder]; ok {
+		delete(compatRecords, folder)
+	} else {
+		return fmt.Errorf("%w: no errors recorded for %s", ErrModNotFound, folder)
+	}
+	return saveModCompat()
+}
+
+func loadModCompat() error {
+	if compatRecords != nil {
+		return nil
+	}
+	compatRecords = make(map[string]*ModCompat)
+	data, err := os.ReadFile(config.GetModCompatFilePath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read mod error counters: %w", err)
+	}
+	var records []ModCompat
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("failed to parse mod error counters: %w", err)
+	}
+	for _, record := range records {
+		compatRecords[record.Folder] = &record
+	}
+	return nil
+}
+
+func saveModCompat() error {
+	records := make([]ModCompat, 0, len(compatRecords))
+	for _, record := range compatRecords {
+		records = append(records, *record)
+	}
+	slices.SortFunc(records, func(a, b ModCompat) int { return strings.Compare(a.Folder, b.Folder) })
+
+	path := config.GetModCompatFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create mod error counters directory: %w", err)
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode mod error counters: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write mod error counters: %w", err)
+	}
+	compatSavedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,46 @@
+package modding
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAttributeStackTrace(t *testing.T) {
+	root := t.TempDir()
+	mods := []ModMetadata{
+		{Name: "Door Tweaks", Folder: filepath.Join(root, "Workshop_100")},
+		{Name: "Power Grid", Folder: filepath.Join(root, "Workshop_200")},
+	}
+	for file, folder := range map[string]string{
+		"Assemblies/DoorTweaks.dll": mods[0].Folder,
+		"0Harmony.dll":              mods[0].Folder,
+		"PowerGrid.Core.dll":        mods[1].Folder,
+	} {
+		path := filepath.Join(folder, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idents := loadModIdentifiers(mods)
+
+	tests := []struct {
+		trace string
+		want  string
+	}{
+		{"NullReferenceException: Object reference not set\n  at HarmonyLib.Traverse.Field () [0x00000]\n  at PowerGrid.Core.Network.Tick () [0x0001c]\n  at DoorTweaks.Patches.Postfix () [0x00000]", "Power Grid"},
+		{"InvalidCastException\nDoorTweaks.Patches.DoorPatch:Postfix(Door)\nUnityEngine.Debug:LogException(Exception)", "Door Tweaks"},
+		{"FileNotFoundException: Could not load file " + filepath.Join(root, "Workshop_200", "About", "About.xml"), "Power Grid"},
+		{"NullReferenceException\n  at Assets.Scripts.Objects.Door.OnInteract () [0x00000]\n  at HarmonyLib.AccessTools.Method ()", ""},
+		{"  at DoorTweaksExtra.Patch.Postfix ()", ""},
+	}
+	for _, test := range tests {
+		mod, ok := attributeStackTrace(test.trace, idents)
+		if ok != (test.want != "") || mod.Name != test.want {
+			t.Errorf("expected %q, got %q (%v) for:\n%s", test.want, mod.Name, ok, test.trace)
+		}
+	}
+}
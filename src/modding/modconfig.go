@@ -0,0 +1,524 @@
+package modding
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+/*
+modconfig.xml
+- The game loads the mods listed in modconfig.xml in the listed order and skips entries with Enabled="false"
+- A mod sync writes the workshop entries from the manifest, the edits below change single entries and keep the rest of the file
+- Mods are addressed by their folder name in ./mods, edits take effect on the next server start
+- Edits to workshop mods are mirrored into the manifest, so the next sync does not undo them
+*/
+
+const (
+	modsDir       = "./mods"
+	modConfigPath = "./modconfig.xml"
+)
+
+// ErrModNotFound is returned for a folder that is neither installed in ./mods nor listed in modconfig.xml
+var ErrModNotFound = errors.New("mod not found")
+
+var modConfigMu sync.Mutex
+
+// ModConfigMod is one mod of modconfig.xml
+type ModConfigMod struct {
+	Folder         string `json:"folder"` // directory name in ./mods
+	Enabled        bool   `json:"enabled"`
+	Installed      bool   `json:"installed"`
+	Name           string `json:"name,omitempty"`
+	Version        string `json:"version,omitempty"`
+	WorkshopHandle string `json:"workshopHandle,omitempty"`
+}
+
+// ModConfig is the load order from modconfig.xml and the problems found with it
+type ModConfig struct {
+	Mods     []ModConfigMod `json:"mods"`
+	Warnings []string       `json:"warnings"`
+}
+
+// modConfigXML is the game's modconfig.xml, the order of the entries is the load order
+type modConfigXML struct {
+	XMLName xml.Name `xml:"ModConfig"`
+	XSI     string   `xml:"xmlns:xsi,attr"`
+	XSD     string   `xml:"xmlns:xsd,attr"`
+	Mods    struct {
+		Entries []modConfigEntry `xml:",any"`
+	} `xml:"Mods"`
+}
+
+// modConfigEntry is a <Core>, <Local> or other entry, child elements other than <Path> are written back as they were read
+type modConfigEntry struct {
+	XMLName xml.Name
+	Enabled bool               `xml:"Enabled,attr"`
+	Path    string             `xml:"Path,omitempty"`
+	Other   []modConfigElement `xml:",any"`
+}
+
+type modConfigElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+}
+
+// localIndex returns the position of the <Local> entry of a mod folder, -1 if it is not listed
+func (c modConfigXML) localIndex(folder string) int {
+	return slices.IndexFunc(c.Mods.Entries, func(entry modConfigEntry) bool {
+		return entry.XMLName.Local == "Local" && folderName(entry.Path) == folder
+	})
+}
+
+// readModConfig parses modconfig.xml, a missing file is an empty config
+func readModConfig() (modConfigXML, error) {
+	var modConfig modConfigXML
+	data, err := os.ReadFile(modConfigPath)
+	if os.IsNotExist(err) {
+		return modConfig, nil
+	}
+	if err != nil {
+		return modConfig, fmt.Errorf("failed to read modconfig.xml: %w", err)
+	}
+	if err := xml.Unmarshal(data, &modConfig); err != nil {
+		return modConfig, fmt.Errorf("failed to parse modconfig.xml: %w", err)
+	}
+	return modConfig, nil
+}
+
+func writeModConfig(modConfig modConfigXML) error {
+	content, err := encodeModConfig(modConfig)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(modConfigPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write modconfig.xml: %w", err)
+	}
+	return nil
+}
+
+func encodeModConfig(modConfig modConfigXML) ([]byte, error) {
+	modConfig.XSI, modConfig.XSD = "http://www.w3.org/2001/XMLSchema-instance", "http://www.w3.org/2001/XMLSchema"
+	content, err := xml.MarshalIndent(modConfig, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode modconfig.xml: %w", err)
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+// WriteModConfig writes the manifest's workshop mods to modconfig.xml, the enabled ones in load order followed by the disabled ones.
+// Mods without a workshop handle, e.g. from an uploaded mod package, are not managed by the manifest and keep their entry.
+func WriteModConfig(manifest ModManifest, order []ManifestMod) error {
+	modConfigMu.Lock()
+	defer modConfigMu.Unlock()
+
+	current, err := readModConfig()
+	if err != nil {
+		logger.Modding.Warn("Replacing modconfig.xml: " + err.Error())
+		current = modConfigXML{}
+	}
+	modConfig, err := buildModConfig(current, manifest, order, listMods(false))
+	if err != nil {
+		return err
+	}
+	if err := writeModConfig(modConfig); err != nil {
+		return err
+	}
+	logger.Modding.Infof("📝 Wrote modconfig.xml with %d enabled workshop mods", len(order))
+	return nil
+}
+
+func buildModConfig(current modConfigXML, manifest ModManifest, order []ManifestMod, installed []ModMetadata) (modConfigXML, error) {
+	byHandle := installedByHandle(installed)
+	var locals []modConfigEntry
+	addLocal := func(folder string, enabled *bool) error {
+		entry := modConfigEntry{XMLName: xml.Name{Local: "Local"}, Enabled: true}
+		if index := current.localIndex(folderName(folder)); index >= 0 {
+			entry = current.Mods.Entries[index]
+		} else {
+			path, err := filepath.Abs(folder)
+			if err != nil {
+				return fmt.Errorf("failed to resolve mod folder %s: %w", folder, err)
+			}
+			entry.Path = path
+		}
+		if enabled != nil {
+			entry.Enabled = *enabled
+		}
+		locals = append(locals, entry)
+		return nil
+	}
+
+	enabled, disabled := true, false
+	for _, mod := range order {
+		installedMod, ok := byHandle[mod.WorkshopID]
+		if !ok {
+			logger.Modding.Warnf("Workshop mod %s is not installed, leaving it out of modconfig.xml", mod.WorkshopID)
+			continue
+		}
+		if err := addLocal(installedMod.Folder, &enabled); err != nil {
+			return modConfigXML{}, err
+		}
+	}
+	for _, mod := range manifest.Mods {
+		if installedMod, ok := byHandle[mod.WorkshopID]; ok && !mod.Enabled {
+			if err := addLocal(installedMod.Folder, &disabled); err != nil {
+				return modConfigXML{}, err
+			}
+		}
+	}
+	for _, mod := range installed {
+		if mod.WorkshopHandle == "" {
+			if err := addLocal(mod.Folder, nil); err != nil {
+				return modConfigXML{}, err
+			}
+		}
+	}
+
+	var modConfig modConfigXML
+	for _, entry := range current.Mods.Entries {
+		if entry.XMLName.Local != "Local" {
+			modConfig.Mods.Entries = append(modConfig.Mods.Entries, entry)
+		}
+	}
+	if !slices.ContainsFunc(modConfig.Mods.Entries, func(entry modConfigEntry) bool { return entry.XMLName.Local == "Core" }) {
+		core := modConfigEntry{XMLName: xml.Name{Local: "Core"}, Enabled: true}
+		modConfig.Mods.Entries = append([]modConfigEntry{core}, modConfig.Mods.Entries...)
+	}
+	modConfig.Mods.Entries = append(modConfig.Mods.Entries, locals...)
+	return modConfig, nil
+}
+
+// resolveModConfigPaths makes the relative paths of an imported modconfig.xml absolute, as exported mod packs have them
+func resolveModConfigPaths() error {
+	modConfigMu.Lock()
+	defer modConfigMu.Unlock()
+
+	modConfig, err := readModConfig()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i, entry := range modConfig.Mods.Entries {
+		// a Windows path is absolute on the server it came from, even if filepath.IsAbs disagrees on Linux
+		if entry.XMLName.Local != "Local" || entry.Path == "" || filepath.IsAbs(entry.Path) || filepath.VolumeName(entry.Path) != "" || strings.Contains(entry.Path, `:\`) {
+			continue
+		}
+		path, err := filepath.Abs(entry.Path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve mod path %s: %w", entry.Path, err)
+		}
+		modConfig.Mods.Entries[i].Path, changed = path, true
+	}
+	if !changed {
+		return nil
+	}
+	return writeModConfig(modConfig)
+}
+
+// GetModConfig returns the mods of modconfig.xml in load order
+func GetModConfig() (ModConfig, error) {
+	modConfigMu.Lock()
+	defer modConfigMu.Unlock()
+
+	modConfig, err := readModConfig()
+	if err != nil {
+		return ModConfig{}, err
+	}
+	return describeModConfig(modConfig, listMods(false)), nil
+}
+
+// SetModEnabled enables or disables a mod, an installed mod that is not listed yet is added at the end of the load order
+func SetModEnabled(folder string, enabled bool) (ModConfig, error) {
+	modConfigMu.Lock()
+	defer modConfigMu.Unlock()
+
+	modConfig, err := readModConfig()
+	if err != nil {
+		return ModConfig{}, err
+	}
+	installed := listMods(false)
+	mod, isInstalled := installedByFolder(installed)[folder]
+	index := modConfig.localIndex(folder)
+	switch {
+	case index >= 0:
+		modConfig.Mods.Entries[index].Enabled = enabled
+	case isInstalled:
+		path, err := filepath.Abs(mod.Folder)
+		if err != nil {
+			return ModConfig{}, fmt.Errorf("failed to resolve mod folder %s: %w", folder, err)
+		}
+		if !slices.ContainsFunc(modConfig.Mods.Entries, func(entry modConfigEntry) bool { return entry.XMLName.Local == "Core" }) {
+			modConfig.Mods.Entries = append(modConfig.Mods.Entries, modConfigEntry{XMLName: xml.Name{Local: "Core"}, Enabled: true})
+		}
+		modConfig.Mods.Entries = append(modConfig.Mods.Entries, modConfigEntry{XMLName: xml.Name{Local: "Local"}, Enabled: enabled, Path: path})
+	default:
+		return ModConfig{}, fmt.Errorf("%w: %s", ErrModNotFound, folder)
+	}
+	if err := writeModConfig(modConfig); err != nil {
+		return ModConfig{}, err
+	}
+
+	if mod.WorkshopHandle != "" {
+		err = updateManifest(func(manifest *ModManifest) bool {
+			index := manifest.index(mod.WorkshopHandle)
+			if index < 0 || manifest.Mods[index].Enabled == enabled {
+				return false
+			}
+			manifest.Mods[index].Enabled = enabled
+			return true
+		})
+		if err != nil {
+			return ModConfig{}, err
+		}
+	}
+	logger.Modding.Infof("Mod %s %s, takes effect on the next server start", folder, map[bool]string{true: "enabled", false: "disabled"}[enabled])
+	return describeModConfig(modConfig, installed), nil
+}
+
+// ReorderMods moves the given mod folders to the front of the load order, in the given order, the other mods follow in their current order.
+// A sync still loads a workshop mod's dependencies before it.
+func ReorderMods(order []string) (ModConfig, error) {
+	modConfigMu.Lock()
+	defer modConfigMu.Unlock()
+
+	modConfig, err := readModConfig()
+	if err != nil {
+		return ModConfig{}, err
+	}
+	if err := reorderLocals(&modConfig, order); err != nil {
+		return ModConfig{}, err
+	}
+	if err := writeModConfig(modConfig); err != nil {
+		return ModConfig{}, err
+	}
+
+	// mirror the new order of the workshop mods into the manifest
+	installed := listMods(false)
+	byFolder := installedByFolder(installed)
+	position := make(map[string]int)
+	for _, entry := range modConfig.Mods.Entries {
+		handle := byFolder[folderName(entry.Path)].WorkshopHandle
+		if _, seen := position[handle]; entry.XMLName.Local == "Local" && handle != "" && !seen {
+			position[handle] = len(position)
+		}
+	}
+	err = updateManifest(func(manifest *ModManifest) bool {
+		var slots []int
+		var mods []ManifestMod
+		for i, mod := range manifest.Mods {
+			if _, ok := position[mod.WorkshopID]; ok {
+				slots = append(slots, i)
+				mods = append(mods, mod)
+			}
+		}
+		slices.SortStableFunc(mods, func(a, b ManifestMod) int { return position[a.WorkshopID] - position[b.WorkshopID] })
+		for i, slot := range slots {
+			manifest.Mods[slot] = mods[i]
+		}
+		return len(slots) > 1
+	})
+	if err != nil {
+		return ModConfig{}, err
+	}
+	logger.Modding.Info("Mod load order changed, takes effect on the next server start")
+	return describeModConfig(modConfig, installed), nil
+}
+
+// reorderLocals puts the listed <Local> entries first, into the slots the <Local> entries already take so <Core> stays in place
+func reorderLocals(modConfig *modConfigXML, order []string) error {
+	var slots []int
+	var locals []modConfigEntry
+	for i, entry := range modConfig.Mods.Entries {
+		if entry.XMLName.Local == "Local" {
+			slots = append(slots, i)
+			locals = append(locals, entry)
+		}
+	}
+
+	reordered := make([]modConfigEntry, 0, len(locals))
+	moved := make(map[string]bool)
+	for _, folder := range order {
+		if moved[folder] {
+			continue
+		}
+		index := slices.IndexFunc(locals, func(entry modConfigEntry) bool { return folderName(entry.Path) == folder })
+		if index < 0 {
+			return fmt.Errorf("%w: %s is not listed in modconfig.xml", ErrModNotFound, folder)
+		}
+		reordered = append(reordered, locals[index])
+		moved[folder] = true
+	}
+	for _, entry := range locals {
+		if !moved[folderName(entry.Path)] {
+			reordered = append(reordered, entry)
+		}
+	}
+	for i, slot := range slots {
+		modConfig.Mods.Entries[slot] = reordered[i]
+	}
+	return nil
+}
+
+// RemoveMod deletes a mod folder from ./mods and its entry from modconfig.xml and the manifest
+func RemoveMod(folder string) (ModConfig, error) {
+	modConfigMu.Lock()
+	defer modConfigMu.Unlock()
+
+	modConfig, err := readModConfig()
+	if err != nil {
+		return ModConfig{}, err
+	}
+	installed := listMods(false)
+	mod, isInstalled := installedByFolder(installed)[folder]
+	index := modConfig.localIndex(folder)
+	if !isInstalled && index < 0 {
+		return ModConfig{}, fmt.Errorf("%w: %s", ErrModNotFound, folder)
+	}
+
+	if isInstalled {
+		if err := os.RemoveAll(mod.Folder); err != nil {
+			return ModConfig{}, fmt.Errorf("failed to remove mod folder %s: %w", folder, err)
+		}
+		installed = slices.DeleteFunc(installed, func(other ModMetadata) bool { return other.Folder == mod.Folder })
+	}
+	if index >= 0 {
+		modConfig.Mods.Entries = slices.Delete(modConfig.Mods.Entries, index, index+1)
+		if err := writeModConfig(modConfig); err != nil {
+			return ModConfig{}, err
+		}
+	}
+
+	// a second copy of the same workshop mod keeps it in the manifest
+	if _, stillInstalled := installedByHandle(installed)[mod.WorkshopHandle]; mod.WorkshopHandle != "" && !stillInstalled {
+		err = updateManifest(func(manifest *ModManifest) bool {
+			index := manifest.index(mod.WorkshopHandle)
+			if index < 0 {
+				return false
+			}
+			manifest.Mods = slices.Delete(manifest.Mods, index, index+1)
+			return true
+		})
+		if err != nil {
+			return ModConfig{}, err
+		}
+	}
+	logger.Modding.Infof("🗑️ Removed mod %s", folder)
+	return describeModConfig(modConfig, installed), nil
+}
+
+// describeModConfig lists the entries of modconfig.xml and warns about missing folders, unlisted mods,
+// workshop mods installed twice and dependencies that are missing, disabled or loaded too late
+func describeModConfig(modConfig modConfigXML, installed []ModMetadata) ModConfig {
+	result := ModConfig{Mods: []ModConfigMod{}, Warnings: duplicateHandleWarnings(installed)}
+	byFolder := installedByFolder(installed)
+	listed := make(map[string]bool)
+	loaded := make(map[string]int) // workshop handle to load position of the enabled mods
+	for _, entry := range modConfig.Mods.Entries {
+		if entry.XMLName.Local != "Local" {
+			continue
+		}
+		folder := folderName(entry.Path)
+		mod, ok := byFolder[folder]
+		listed[folder] = true
+		result.Mods = append(result.Mods, ModConfigMod{
+			Folder:         folder,
+			Enabled:        entry.Enabled,
+			Installed:      ok,
+			Name:           mod.Name,
+			Version:        mod.Version,
+			WorkshopHandle: mod.WorkshopHandle,
+		})
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("modconfig.xml lists %s, which is not installed in ./mods", folder))
+		}
+		if _, seen := loaded[mod.WorkshopHandle]; ok && entry.Enabled && mod.WorkshopHandle != "" && !seen {
+			loaded[mod.WorkshopHandle] = len(loaded)
+		}
+	}
+
+	for _, mod := range installed {
+		folder := folderName(mod.Folder)
+		if !listed[folder] {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s is installed but not listed in modconfig.xml, enable it to load it", folder))
+		}
+	}
+
+	for _, listedMod := range result.Mods {
+		if !listedMod.Enabled || !listedMod.Installed {
+			continue
+		}
+		for _, dependency := range byFolder[listedMod.Folder].Dependencies {
+			position, enabled := loaded[dependency]
+			switch {
+			case !enabled && slices.ContainsFunc(installed, func(mod ModMetadata) bool { return mod.WorkshopHandle == dependency }):
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s depends on workshop mod %s, which is disabled", listedMod.Folder, dependency))
+			case !enabled:
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s depends on workshop mod %s, which is not installed", listedMod.Folder, dependency))
+			case listedMod.WorkshopHandle != "" && position > loaded[listedMod.WorkshopHandle]:
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s is loaded before its dependency %s", listedMod.Folder, dependency))
+			}
+		}
+	}
+	return result
+}
+
+// duplicateHandleWarnings warns about workshop mods that are installed in more than one folder
+func duplicateHandleWarnings(installed []ModMetadata) []string {
+	var handles []string
+	folders := make(map[string][]string)
+	for _, mod := range installed {
+		if mod.WorkshopHandle == "" {
+			continue
+		}
+		if _, seen := folders[mod.WorkshopHandle]; !seen {
+			handles = append(handles, mod.WorkshopHandle)
+		}
+		folders[mod.WorkshopHandle] = append(folders[mod.WorkshopHandle], folderName(mod.Folder))
+	}
+	warnings := []string{}
+	for _, handle := range handles {
+		if len(folders[handle]) > 1 {
+			warnings = append(warnings, fmt.Sprintf("workshop mod %s is installed %d times (%s), remove all but one", handle, len(folders[handle]), strings.Join(folders[handle], ", ")))
+		}
+	}
+	return warnings
+}
+
+// updateManifest applies a change to the manifest and saves it if the change reports that it changed something
+func updateManifest(change func(manifest *ModManifest) bool) error {
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+
+	manifest, err := loadModManifest()
+	if err != nil {
+		return err
+	}
+	if !change(&manifest) {
+		return nil
+	}
+	return saveModManifest(manifest)
+}
+
+// installedByFolder indexes the installed mods by their folder name
+func installedByFolder(installed []ModMetadata) map[string]ModMetadata {
+	byFolder := make(map[string]ModMetadata)
+	for _, mod := range installed {
+		byFolder[folderName(mod.Folder)] = mod
+	}
+	return byFolder
+}
+
+// folderName returns the last element of a mod path, modconfig.xml may hold Windows paths on any OS
+func folderName(path string) string {
+	path = strings.TrimRight(path, `/\`)
+	return path[strings.LastIndexAny(path, `/\`)+1:]
+}
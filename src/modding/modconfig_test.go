@@ -0,0 +1,76 @@
+package modding
+
+import (
+	"encoding/xml"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testModConfig = `<?xml version="1.0" encoding="utf-8"?>
+<ModConfig xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+  <Mods>
+    <Core Enabled="true" />
+    <Local Enabled="true">
+      <Path>/srv/stationeers/mods/Workshop_200</Path>
+    </Local>
+    <Local Enabled="false">
+      <Path>C:\server\mods\Workshop_100</Path>
+      <Note kind="custom">kept</Note>
+    </Local>
+    <Local Enabled="true">
+      <Path>/srv/stationeers/mods/Gone</Path>
+    </Local>
+  </Mods>
+</ModConfig>`
+
+func TestEditModConfig(t *testing.T) {
+	var modConfig modConfigXML
+	if err := xml.Unmarshal([]byte(testModConfig), &modConfig); err != nil {
+		t.Fatal(err)
+	}
+	installed := append(slices.Clone(testInstalled), ModMetadata{Name: "Copy", WorkshopHandle: "100", Folder: "mods/Base_Copy"})
+
+	warnings := strings.Join(describeModConfig(modConfig, installed).Warnings, "\n")
+	for _, want := range []string{
+		"workshop mod 100 is installed 2 times (Workshop_100, Base_Copy)",
+		"Gone, which is not installed",
+		"Workshop_400 is installed but not listed",
+		"Workshop_200 depends on workshop mod 100, which is disabled",
+		"Workshop_200 depends on workshop mod 300, which is not installed",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("missing warning %q in:\n%s", want, warnings)
+		}
+	}
+
+	modConfig.Mods.Entries[modConfig.localIndex("Workshop_100")].Enabled = true
+	if err := reorderLocals(&modConfig, []string{"Gone", "Workshop_100"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reorderLocals(&modConfig, []string{"Workshop_999"}); err == nil {
+		t.Error("reordering a mod that is not listed should fail")
+	}
+	described := describeModConfig(modConfig, installed)
+	var folders []string
+	for _, mod := range described.Mods {
+		folders = append(folders, mod.Folder)
+	}
+	if !slices.Equal(folders, []string{"Gone", "Workshop_100", "Workshop_200"}) {
+		t.Errorf("load order: %v", folders)
+	}
+	if strings.Contains(strings.Join(described.Warnings, "\n"), "loaded before") {
+		t.Errorf("dependency is loaded first now: %v", described.Warnings)
+	}
+
+	content, err := encodeModConfig(modConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `<Note kind="custom">kept</Note>`) || !strings.HasPrefix(strings.TrimSpace(string(content)[len(xml.Header):]), "<ModConfig") {
+		t.Errorf("unknown elements were not kept:\n%s", content)
+	}
+	if core := modConfig.Mods.Entries[0]; core.XMLName.Local != "Core" {
+		t.Errorf("Core moved: %+v", core)
+	}
+}
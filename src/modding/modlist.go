@@ -16,6 +16,8 @@ type ModMetadata struct {
 	Description    string
 	WorkshopHandle string
 	Images         map[string]string // filename -> base64 encoded image data
+	Folder         string            // directory in ./mods
+	Dependencies   []string          // workshop handles of the mods this mod needs
 }
 
 // aboutXML is the structure for parsing About.xml files
@@ -25,6 +27,26 @@ type aboutXML struct {
 	Version        string `xml:"Version"`
 	Description    string `xml:"Description"`
 	WorkshopHandle string `xml:"WorkshopHandle"`
+	Dependencies   struct {
+		Mods []aboutDependency `xml:",any"`
+	} `xml:"Dependencies"`
+}
+
+// aboutDependency is one entry of <Dependencies>, either <Mod><Id>123</Id></Mod>, <Mod><WorkshopHandle>123</WorkshopHandle></Mod> or <Id>123</Id>
+type aboutDependency struct {
+	ID             string `xml:"Id"`
+	WorkshopHandle string `xml:"WorkshopHandle"`
+	Value          string `xml:",chardata"`
+}
+
+// handle returns the workshop handle of the dependency, "" if it has none
+func (d aboutDependency) handle() string {
+	for _, value := range []string{d.WorkshopHandle, d.ID, d.Value} {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
 }
 
 // loadModImages loads all images from the About folder and converts them to base64
@@ -76,10 +98,15 @@ func loadModImages(aboutPath string) map[string]string {
 
 // GetModList returns an array of installed mods and their details
 func GetModList() []ModMetadata {
+	return listMods(true)
+}
+
+// listMods reads the About.xml of every mod in ./mods, the images are only loaded for the UI
+func listMods(withImages bool) []ModMetadata {
 	var mods []ModMetadata
 
 	// Check if ./mods folder exists
-	modsPath := "./mods"
+	modsPath := modsDir
 	info, err := os.Stat(modsPath)
 	if err != nil || !info.IsDir() {
 		return mods // Return empty slice if folder doesn't exist or isn't a directory
@@ -119,19 +146,31 @@ func GetModList() []ModMetadata {
 		}
 
 		// Load images from the About folder
-		images := loadModImages(aboutPath)
+		var images map[string]string
+		if withImages {
+			images = loadModImages(aboutPath)
+		}
 
 		// Use WorkshopHandle from XML if available
 		workshopHandle := xmlData.WorkshopHandle
 
+		var dependencies []string
+		for _, dependency := range xmlData.Dependencies.Mods {
+			if handle := dependency.handle(); handle != "" {
+				dependencies = append(dependencies, handle)
+			}
+		}
+
 		// Build the ModMetadata struct
 		mod := ModMetadata{
 			Name:           xmlData.Name,
 			Author:         xmlData.Author,
 			Version:        xmlData.Version,
 			Description:    xmlData.Description,
-			WorkshopHandle: workshopHandle,
+			WorkshopHandle: strings.TrimSpace(workshopHandle),
 			Images:         images,
+			Folder:         filepath.Join(modsPath, dirName),
+			Dependencies:   dependencies,
 		}
 
 		mods = append(mods, mod)
@@ -143,7 +182,7 @@ func GetModList() []ModMetadata {
 // GetModWorkshopHandles returns an array of workshop handles for installed mods that have one
 func GetModWorkshopHandles() []string {
 	var handles []string
-	mods := GetModList()
+	mods := listMods(false)
 
 	for _, mod := range mods {
 		if mod.WorkshopHandle != "" {
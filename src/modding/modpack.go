@@ -0,0 +1,161 @@
+package modding
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+/*
+Mod pack export
+- A mod pack lists the mods a player needs to join the server: the enabled mods of modconfig.xml in load order,
+  or every installed mod if there is no modconfig.xml yet, with workshop links and versions
+- The zip holds mods/<folder>/..., a modconfig.xml and modpack.json, the layout ProcessModPackageUpload extracts.
+  Its modconfig.xml paths are relative to the zip root and are resolved when the package is imported.
+*/
+
+const workshopItemURL = "https://steamcommunity.com/sharedfiles/filedetails/?id="
+
+// ModPack is a shareable list of the mods the server runs
+type ModPack struct {
+	Server     string       `json:"server"`
+	BuildID    string       `json:"buildId"` // game build the server runs
+	ExportedAt time.Time    `json:"exportedAt"`
+	Mods       []ModPackMod `json:"mods"` // in load order
+}
+
+// ModPackMod is one mod of a mod pack
+type ModPackMod struct {
+	Name           string `json:"name"`
+	Author         string `json:"author,omitempty"`
+	Version        string `json:"version,omitempty"`
+	Folder         string `json:"folder"`
+	WorkshopHandle string `json:"workshopHandle,omitempty"`
+	WorkshopURL    string `json:"workshopUrl,omitempty"` // empty for mods that are not on the workshop, players need the zip for those
+}
+
+// BuildModPack lists the mods players need, in load order
+func BuildModPack() (ModPack, error) {
+	modConfigMu.Lock()
+	defer modConfigMu.Unlock()
+
+	modConfig, err := readModConfig()
+	if err != nil {
+		return ModPack{}, err
+	}
+	return ModPack{
+		Server:     config.GetServerName(),
+		BuildID:    config.GetCurrentBranchBuildID(),
+		ExportedAt: time.Now(),
+		Mods:       modPackMods(modConfig, listMods(false)),
+	}, nil
+}
+
+func modPackMods(modConfig modConfigXML, installed []ModMetadata) []ModPackMod {
+	selected := installed // without modconfig.xml entries every installed mod is listed
+	if slices.ContainsFunc(modConfig.Mods.Entries, func(entry modConfigEntry) bool { return entry.XMLName.Local == "Local" }) {
+		byFolder := installedByFolder(installed)
+		selected = nil
+		for _, entry := range modConfig.Mods.Entries {
+			if mod, ok := byFolder[folderName(entry.Path)]; ok && entry.XMLName.Local == "Local" && entry.Enabled {
+				selected = append(selected, mod)
+			}
+		}
+	}
+
+	mods := []ModPackMod{}
+	for _, mod := range selected {
+		packMod := ModPackMod{
+			Name:           mod.Name,
+			Author:         mod.Author,
+			Version:        mod.Version,
+			Folder:         folderName(mod.Folder),
+			WorkshopHandle: mod.WorkshopHandle,
+		}
+		if mod.WorkshopHandle != "" {
+			packMod.WorkshopURL = workshopItemURL + mod.WorkshopHandle
+		}
+		mods = append(mods, packMod)
+	}
+	return mods
+}
+
+// WriteModPackZip writes the mods of the pack, a modconfig.xml in their load order and the pack itself as modpack.json to a zip
+func WriteModPackZip(w io.Writer, pack ModPack) error {
+	archive := zip.NewWriter(w)
+
+	var modConfig modConfigXML
+	modConfig.Mods.Entries = append(modConfig.Mods.Entries, modConfigEntry{XMLName: xml.Name{Local: "Core"}, Enabled: true})
+	for _, mod := range pack.Mods {
+		root := filepath.Join(modsDir, mod.Folder)
+		err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			relative, err := filepath.Rel(root, file)
+			if err != nil {
+				return err
+			}
+			return addFileToZip(archive, file, path.Join("mods", mod.Folder, filepath.ToSlash(relative)))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to pack mod %s: %w", mod.Folder, err)
+		}
+		modConfig.Mods.Entries = append(modConfig.Mods.Entries, modConfigEntry{XMLName: xml.Name{Local: "Local"}, Enabled: true, Path: path.Join("mods", mod.Folder)})
+	}
+
+	content, err := encodeModConfig(modConfig)
+	if err != nil {
+		return err
+	}
+	manifest, err := json.MarshalIndent(pack, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode mod pack: %w", err)
+	}
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{{"modconfig.xml", content}, {"modpack.json", manifest}} {
+		entry, err := archive.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to the mod pack: %w", file.name, err)
+		}
+		if _, err := entry.Write(file.data); err != nil {
+			return fmt.Errorf("failed to add %s to the mod pack: %w", file.name, err)
+		}
+	}
+	return archive.Close()
+}
+
+func addFileToZip(archive *zip.Writer, file, name string) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name, header.Method = name, zip.Deflate
+	writer, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	source, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	_, err = io.Copy(writer, source)
+	return err
+}
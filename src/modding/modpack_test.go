@@ -0,0 +1,83 @@
+package modding
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModPackRoundTrip(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for folder, about := range map[string]string{
+		"Workshop_100": "<ModMetadata><Name>Base</Name><Version>1.0</Version><WorkshopHandle>100</WorkshopHandle></ModMetadata>",
+		"Workshop_200": "<ModMetadata><Name>Addon</Name><WorkshopHandle>200</WorkshopHandle></ModMetadata>",
+		"Private":      "<ModMetadata><Name>Private</Name></ModMetadata>",
+	} {
+		if err := os.MkdirAll(filepath.Join("mods", folder, "About"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join("mods", folder, "About", "About.xml"), []byte(about), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := `<ModConfig><Mods><Core Enabled="true" />
+		<Local Enabled="true"><Path>/srv/mods/Private</Path></Local>
+		<Local Enabled="false"><Path>/srv/mods/Workshop_200</Path></Local>
+		<Local Enabled="true"><Path>C:\server\mods\Workshop_100</Path></Local>
+	</Mods></ModConfig>`
+	if err := os.WriteFile(modConfigPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pack, err := BuildModPack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pack.Mods) != 2 || pack.Mods[0].Folder != "Private" || pack.Mods[0].WorkshopURL != "" ||
+		pack.Mods[1].WorkshopURL != workshopItemURL+"100" || pack.Mods[1].Version != "1.0" {
+		t.Fatalf("unexpected mod pack: %+v", pack.Mods)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteModPackZip(&buf, pack); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	if got := strings.Join(names, ","); got != "mods/Private/About/About.xml,mods/Workshop_100/About/About.xml,modconfig.xml,modpack.json" {
+		t.Fatalf("unexpected zip entries: %s", got)
+	}
+
+	// importing the zip on another server resolves the relative paths
+	imported := t.TempDir()
+	if err := os.WriteFile(filepath.Join(imported, "pack.zip"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(imported)
+	if err := extractZip("pack.zip", "."); err != nil {
+		t.Fatal(err)
+	}
+	if err := resolveModConfigPaths(); err != nil {
+		t.Fatal(err)
+	}
+	modConfig, err := GetModConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modConfig.Mods) != 2 || !modConfig.Mods[0].Installed || !modConfig.Mods[1].Installed || modConfig.Mods[1].Folder != "Workshop_100" {
+		t.Errorf("imported modconfig.xml: %+v", modConfig)
+	}
+	content, _ := os.ReadFile(modConfigPath)
+	if !strings.Contains(string(content), "<Path>"+filepath.Join(imported, "mods", "Private")+"</Path>") {
+		t.Errorf("paths were not resolved:\n%s", content)
+	}
+}
@@ -12,12 +12,14 @@ import (
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 )
 
+// MaxModPackageSize is the largest mod package zip that is accepted
+const MaxModPackageSize = 500 * 1024 * 1024 // 500 MB
+
 // ProcessModPackageUpload handles the upload and extraction of a mod package zip file
 func ProcessModPackageUpload(r io.Reader) error {
 	logger.Modding.Info("Starting mod package upload process")
 
-	const maxZipSize = 500 * 1024 * 1024 // 500 MB
-	sizeLimitedReader := io.LimitReader(r, maxZipSize+1)
+	sizeLimitedReader := io.LimitReader(r, MaxModPackageSize+1)
 
 	// Read the entire zip file into memory
 	zipBytes, err := io.ReadAll(sizeLimitedReader)
@@ -70,6 +72,11 @@ func ProcessModPackageUpload(r io.Reader) error {
 		return fmt.Errorf("failed to extract mod package: %w", err)
 	}
 
+	// exported mod packs list their mods relative to the package root
+	if err := resolveModConfigPaths(); err != nil {
+		logger.Modding.Warnf("Failed to resolve the mod paths in modconfig.xml: %v", err)
+	}
+
 	// Call ImportModPackage with the zip bytes
 	if err := ImportModPackage(zipBytes); err != nil {
 		logger.Modding.Errorf("ImportModPackage failed: %v", err)
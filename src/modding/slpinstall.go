@@ -0,0 +1,315 @@
+package modding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+/*
+SLP versions and rollback
+- Installs use the release config.SLPVersion selects: the newest stable release ("latest"), the newest release
+  including prereleases ("prerelease") or a pinned release tag
+- The downloaded zip is checked against the size and sha256 digest GitHub publishes for the asset
+- The install an update replaces is moved to config.SLPPreviousFolder, outside BepInEx/plugins so BepInEx does not load it.
+  Until the server reached ready with the new version, a start that fails (the process exits before ready, a slow startup is not a failure)
+  restores the previous install
+*/
+
+const (
+	SLPChannelLatest     = "latest"
+	SLPChannelPrerelease = "prerelease"
+
+	slpVerifiedSHA256 = "sha256"
+	slpVerifiedSize   = "size"
+	slpVerifiedNone   = "none"
+)
+
+// ErrNoPreviousSLP is returned when there is no previous SLP install to roll back to
+var ErrNoPreviousSLP = errors.New("no previous SLP install to roll back to")
+
+// SLPInstall describes one installed SLP release
+type SLPInstall struct {
+	Version     string    `json:"version"` // release tag
+	Prerelease  bool      `json:"prerelease"`
+	Asset       string    `json:"asset,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+	SHA256      string    `json:"sha256,omitempty"`
+	Verified    string    `json:"verified"` // what the download was checked against: "sha256", "size" or "none"
+	InstalledAt time.Time `json:"installedAt"`
+	Confirmed   bool      `json:"confirmed"` // the server reached ready with this install
+}
+
+// SLPInstallRecord is the installed SLP and the one it replaced
+type SLPInstallRecord struct {
+	Current  *SLPInstall `json:"current,omitempty"`
+	Previous *SLPInstall `json:"previous,omitempty"` // kept in config.SLPPreviousFolder for a rollback
+}
+
+type slpRelease struct {
+	TagName    string     `json:"tag_name"`
+	Prerelease bool       `json:"prerelease"`
+	Assets     []slpAsset `json:"assets"`
+}
+
+type slpAsset struct {
+	Name   string `json:"name"`
+	URL    string `json:"browser_download_url"`
+	Size   int64  `json:"size"`
+	Digest string `json:"digest"` // "sha256:<hex>", empty for assets uploaded before GitHub published digests
+}
+
+var (
+	slpMu       sync.Mutex // guards the SLP folder, the previous folder and the install record
+	slpChecking bool       // the current server start checks an unconfirmed install
+)
+
+// selectSLPRelease picks the newest release with a server zip on the channel, or the release tagged version
+func selectSLPRelease(releases []slpRelease, version string) (slpRelease, slpAsset, error) {
+	for _, release := range releases { // GitHub lists the newest release first
+		switch version {
+		case SLPChannelLatest:
+			if release.Prerelease {
+				continue
+			}
+		case SLPChannelPrerelease:
+		default:
+			if !strings.EqualFold(strings.TrimPrefix(release.TagName, "v"), strings.TrimPrefix(version, "v")) {
+				continue
+			}
+		}
+		for _, asset := range release.Assets {
+			if strings.HasPrefix(asset.Name, "StationeersLaunchPad-server-") && strings.HasSuffix(asset.Name, ".zip") {
+				return release, asset, nil
+			}
+		}
+		if version != SLPChannelLatest && version != SLPChannelPrerelease {
+			return slpRelease{}, slpAsset{}, fmt.Errorf("SLP release %s has no StationeersLaunchPad-server-*.zip", release.TagName)
+		}
+	}
+	if version != SLPChannelLatest && version != SLPChannelPrerelease {
+		return slpRelease{}, slpAsset{}, fmt.Errorf("SLP release %s not found", version)
+	}
+	return slpRelease{}, slpAsset{}, fmt.Errorf("no suitable StationeersLaunchPad-server-*.zip found in the %s releases", version)
+}
+
+// verifySLPDownload checks a downloaded asset against the size and digest GitHub published for it
+func verifySLPDownload(path string, asset slpAsset) (SLPInstall, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return SLPInstall{}, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return SLPInstall{}, err
+	}
+	install := SLPInstall{Asset: asset.Name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil)), Verified: slpVerifiedNone}
+
+	if asset.Size > 0 {
+		if size != asset.Size {
+			return SLPInstall{}, fmt.Errorf("downloaded %d bytes, GitHub lists %d for %s", size, asset.Size, asset.Name)
+		}
+		install.Verified = slpVerifiedSize
+	}
+	if expected, ok := strings.CutPrefix(asset.Digest, "sha256:"); ok {
+		if !strings.EqualFold(expected, install.SHA256) {
+			return SLPInstall{}, fmt.Errorf("sha256 of %s is %s, GitHub lists %s", asset.Name, install.SHA256, expected)
+		}
+		install.Verified = slpVerifiedSHA256
+	}
+	return install, nil
+}
+
+// GetSLPInstallRecord returns the installed SLP version and the one kept for a rollback
+func GetSLPInstallRecord() (SLPInstallRecord, error) {
+	slpMu.Lock()
+	defer slpMu.Unlock()
+	return loadSLPInstallRecord()
+}
+
+// BeginSLPStartupCheck starts checking a server start if the installed SLP has not reached ready yet, returns the checked version
+func BeginSLPStartupCheck() (string, bool) {
+	slpMu.Lock()
+	defer slpMu.Unlock()
+
+	record, err := loadSLPInstallRecord()
+	slpChecking = err == nil && record.Current != nil && !record.Current.Confirmed
+	if !slpChecking {
+		return "", false
+	}
+	return record.Current.Version, true
+}
+
+// CancelSLPStartupCheck ends the check without a result, e.g. because the server was stopped by hand
+func CancelSLPStartupCheck() {
+	slpMu.Lock()
+	defer slpMu.Unlock()
+	slpChecking = false
+}
+
+// ConfirmSLPInstall marks the checked SLP install as working, returns the version if one was confirmed
+func ConfirmSLPInstall() (string, bool) {
+	slpMu.Lock()
+	defer slpMu.Unlock()
+
+	if !slpChecking {
+		return "", false
+	}
+	slpChecking = false
+	record, err := loadSLPInstallRecord()
+	if err != nil || record.Current == nil {
+		return "", false
+	}
+	record.Current.Confirmed = true
+	if err := saveSLPInstallRecord(record); err != nil {
+		logger.Install.Warn(err.Error())
+	}
+	return record.Current.Version, true
+}
+
+// FailSLPStartupCheck rolls back the checked SLP install after the server failed to reach ready with it.
+// Returns the failed and the restored version, failed is empty if no start was being checked.
+func FailSLPStartupCheck() (failed string, restored string, err error) {
+	slpMu.Lock()
+	defer slpMu.Unlock()
+
+	if !slpChecking {
+		return "", "", nil
+	}
+	slpChecking = false
+	record, err := loadSLPInstallRecord()
+	if err != nil || record.Current == nil {
+		return "", "", err
+	}
+	failed = record.Current.Version
+	restored, err = rollbackSLP(record)
+	return failed, restored, err
+}
+
+// RollbackSLP swaps the installed SLP with the previous install, returns the restored version
+func RollbackSLP() (string, error) {
+	slpMu.Lock()
+	defer slpMu.Unlock()
+
+	slpChecking = false
+	record, err := loadSLPInstallRecord()
+	if err != nil {
+		return "", err
+	}
+	return rollbackSLP(record)
+}
+
+// rollbackSLP swaps the SLP folder with the previous folder, so a second rollback undoes the first.
+// The restored install counts as confirmed and is not rolled back on a failed start. Caller must hold slpMu.
+func rollbackSLP(record SLPInstallRecord) (string, error) {
+	previousDir := filepath.Clean(config.GetSLPPreviousFolder())
+	if record.Previous == nil {
+		return "", ErrNoPreviousSLP
+	}
+	if _, err := os.Stat(previousDir); err != nil {
+		return "", fmt.Errorf("%w: %s is missing", ErrNoPreviousSLP, previousDir)
+	}
+
+	swapDir := previousDir + ".swap"
+	if err := os.RemoveAll(swapDir); err != nil {
+		return "", fmt.Errorf("failed to clean %s: %w", swapDir, err)
+	}
+	if err := os.Rename(slpDir, swapDir); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to move the installed SLP aside: %w", err)
+	}
+	if err := os.Rename(previousDir, slpDir); err != nil {
+		os.Rename(swapDir, slpDir)
+		return "", fmt.Errorf("failed to restore the previous SLP: %w", err)
+	}
+	if err := os.Rename(swapDir, previousDir); err != nil && !os.IsNotExist(err) {
+		logger.Install.Warn("Could not keep the replaced SLP for another rollback: " + err.Error())
+	}
+
+	record.Current, record.Previous = record.Previous, record.Current
+	record.Current.Confirmed = true
+	if err := saveSLPInstallRecord(record); err != nil {
+		return record.Current.Version, err
+	}
+	return record.Current.Version, nil
+}
+
+// backupSLP moves the installed SLP to the previous folder, false if SLP was not installed. Caller must hold slpMu.
+func backupSLP() (bool, error) {
+	if _, err := os.Stat(slpDir); os.IsNotExist(err) {
+		return false, nil
+	}
+	previousDir := filepath.Clean(config.GetSLPPreviousFolder())
+	if err := os.RemoveAll(previousDir); err != nil {
+		return false, fmt.Errorf("failed to clean %s: %w", previousDir, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(previousDir), os.ModePerm); err != nil {
+		return false, err
+	}
+	if err := os.Rename(slpDir, previousDir); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// restoreSLPBackup puts the install backupSLP moved away back in place after a failed install. Caller must hold slpMu.
+func restoreSLPBackup() {
+	if err := os.RemoveAll(slpDir); err != nil {
+		logger.Install.Warn("Could not clean the partial SLP install: " + err.Error())
+	}
+	if err := os.Rename(filepath.Clean(config.GetSLPPreviousFolder()), slpDir); err != nil {
+		logger.Install.Error("Could not restore the previous SLP install: " + err.Error())
+	}
+}
+
+func loadSLPInstallRecord() (SLPInstallRecord, error) {
+	var record SLPInstallRecord
+	data, err := os.ReadFile(config.GetSLPInstallFilePath())
+	if os.IsNotExist(err) {
+		return record, nil
+	}
+	if err != nil {
+		return record, fmt.Errorf("failed to read SLP install record: %w", err)
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return SLPInstallRecord{}, fmt.Errorf("failed to parse SLP install record: %w", err)
+	}
+	return record, nil
+}
+
+func saveSLPInstallRecord(record SLPInstallRecord) error {
+	path := config.GetSLPInstallFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create SLP install record directory: %w", err)
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode SLP install record: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SLP install record: %w", err)
+	}
+	return nil
+}
+
+func removeSLPInstallRecord() {
+	if err := os.RemoveAll(filepath.Clean(config.GetSLPPreviousFolder())); err != nil {
+		logger.Install.Warn("Could not remove the previous SLP install: " + err.Error())
+	}
+	if err := os.Remove(config.GetSLPInstallFilePath()); err != nil && !os.IsNotExist(err) {
+		logger.Install.Warn("Could not remove the SLP install record: " + err.Error())
+	}
+}
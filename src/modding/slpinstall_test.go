@@ -0,0 +1,116 @@
+package modding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSelectSLPRelease(t *testing.T) {
+	server := func(tag string) []slpAsset {
+		return []slpAsset{{Name: "StationeersLaunchPad-client-" + tag + ".zip"}, {Name: "StationeersLaunchPad-server-" + tag + ".zip"}}
+	}
+	releases := []slpRelease{
+		{TagName: "v0.3.0-beta", Prerelease: true, Assets: server("v0.3.0-beta")},
+		{TagName: "v0.2.1", Assets: server("v0.2.1")},
+		{TagName: "v0.2.0", Assets: []slpAsset{{Name: "StationeersLaunchPad-client-v0.2.0.zip"}}},
+	}
+
+	for version, want := range map[string]string{"latest": "v0.2.1", "prerelease": "v0.3.0-beta", "0.2.1": "v0.2.1"} {
+		release, asset, err := selectSLPRelease(releases, version)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		if release.TagName != want || asset.Name != "StationeersLaunchPad-server-"+want+".zip" {
+			t.Fatalf("%s: selected %s (%s), want %s", version, release.TagName, asset.Name, want)
+		}
+	}
+	for _, version := range []string{"v0.2.0", "v9.9.9"} {
+		if _, _, err := selectSLPRelease(releases, version); err == nil {
+			t.Fatalf("%s: expected an error", version)
+		}
+	}
+}
+
+func TestVerifySLPDownload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slp.zip")
+	content := []byte("not really a zip")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	cases := []struct {
+		asset    slpAsset
+		verified string
+		fails    bool
+	}{
+		{slpAsset{Size: int64(len(content)), Digest: digest}, slpVerifiedSHA256, false},
+		{slpAsset{Size: int64(len(content))}, slpVerifiedSize, false},
+		{slpAsset{}, slpVerifiedNone, false},
+		{slpAsset{Size: 3}, "", true},
+		{slpAsset{Digest: "sha256:" + hex.EncodeToString(make([]byte, 32))}, "", true},
+	}
+	for i, c := range cases {
+		install, err := verifySLPDownload(path, c.asset)
+		if (err != nil) != c.fails {
+			t.Fatalf("case %d: unexpected error result %v", i, err)
+		}
+		if !c.fails && install.Verified != c.verified {
+			t.Fatalf("case %d: verified %q, want %q", i, install.Verified, c.verified)
+		}
+	}
+}
+
+func TestSLPStartupRollback(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeVersion := func(version string) {
+		if err := os.MkdirAll(slpDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(slpDir, "version.txt"), []byte(version), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	installedVersion := func() string {
+		data, err := os.ReadFile(filepath.Join(slpDir, "version.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	writeVersion("v1")
+	if err := saveSLPInstallRecord(SLPInstallRecord{Current: &SLPInstall{Version: "v1", Confirmed: true}}); err != nil {
+		t.Fatal(err)
+	}
+	// what InstallSLP does around the extraction
+	if backedUp, err := backupSLP(); err != nil || !backedUp {
+		t.Fatalf("backup: %v %v", backedUp, err)
+	}
+	writeVersion("v2")
+	if err := saveSLPInstallRecord(SLPInstallRecord{Current: &SLPInstall{Version: "v2"}, Previous: &SLPInstall{Version: "v1", Confirmed: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, ok := BeginSLPStartupCheck(); !ok || version != "v2" {
+		t.Fatalf("expected v2 to be checked, got %q %v", version, ok)
+	}
+	failed, restored, err := FailSLPStartupCheck()
+	if err != nil || failed != "v2" || restored != "v1" {
+		t.Fatalf("rollback: failed %q restored %q err %v", failed, restored, err)
+	}
+	if installedVersion() != "v1" {
+		t.Fatalf("expected v1 files after the rollback, got %s", installedVersion())
+	}
+	if _, ok := BeginSLPStartupCheck(); ok {
+		t.Fatal("the restored install should not be checked again")
+	}
+
+	if restored, err := RollbackSLP(); err != nil || restored != "v2" || installedVersion() != "v2" {
+		t.Fatalf("second rollback should restore v2, got %q %v", restored, err)
+	}
+}
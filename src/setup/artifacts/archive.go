@@ -0,0 +1,182 @@
+package artifacts
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+// Export writes the cached artifacts and their index to a zip, returns the number of artifacts
+func Export(w io.Writer) (int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	entries, err := listCache()
+	if err != nil {
+		return 0, err
+	}
+	archive := zip.NewWriter(w)
+	dir := config.GetArtifactCacheFolder()
+	for _, entry := range entries {
+		if err := addFile(archive, filepath.Join(dir, entry.Name), entry.Name); err != nil {
+			return 0, fmt.Errorf("failed to export %s: %w", entry.Name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, indexFileName)); err == nil {
+		if err := addFile(archive, filepath.Join(dir, indexFileName), indexFileName); err != nil {
+			return 0, fmt.Errorf("failed to export the artifact index: %w", err)
+		}
+	}
+	return len(entries), archive.Close()
+}
+
+// Import adds the artifacts of an exported zip to the cache, returns the number of artifacts.
+// Every artifact is extracted and checked against its index entry in the zip before the first one is moved into the cache.
+func Import(r io.ReaderAt, size int64) (int, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open the artifact zip: %w", err)
+	}
+
+	imported := map[string]Entry{}
+	var files []*zip.File
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if file.Name == indexFileName {
+			entries, err := readIndexFile(file)
+			if err != nil {
+				return 0, err
+			}
+			for _, entry := range entries {
+				imported[entry.Name] = entry
+			}
+			continue
+		}
+		if err := validateName(file.Name); err != nil {
+			return 0, fmt.Errorf("the artifact zip contains %q, artifacts must not be in folders", file.Name)
+		}
+		if slices.ContainsFunc(files, func(f *zip.File) bool { return f.Name == file.Name }) {
+			return 0, fmt.Errorf("the artifact zip contains %q twice", file.Name)
+		}
+		files = append(files, file)
+	}
+
+	// artifact locks before mu like Fetch, in sorted order so two imports cannot deadlock
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		defer lockArtifact(name)()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+
+	dir := config.GetArtifactCacheFolder()
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return 0, fmt.Errorf("failed to create the artifact cache: %w", err)
+	}
+	index, err := loadIndex()
+	if err != nil {
+		return 0, err
+	}
+	// stage and check every artifact first, so a bad file in the zip leaves the cache untouched
+	staged := make([]string, 0, len(files))
+	defer func() {
+		for _, part := range staged {
+			os.Remove(part)
+		}
+	}()
+	for _, file := range files {
+		part := filepath.Join(dir, file.Name) + ".part"
+		staged = append(staged, part)
+		if err := extractFile(file, part); err != nil {
+			return 0, fmt.Errorf("failed to import %s: %w", file.Name, err)
+		}
+		if entry, ok := imported[file.Name]; ok {
+			size, sum, err := hashFile(part)
+			if err != nil || size != entry.Size || sum != entry.SHA256 {
+				return 0, fmt.Errorf("%s in the artifact zip does not match its index entry", file.Name)
+			}
+		}
+	}
+
+	for _, file := range files {
+		target := filepath.Join(dir, file.Name)
+		if err := os.Rename(target+".part", target); err != nil {
+			// keep the index in line with the artifacts that are already in place
+			if saveErr := saveIndex(index); saveErr != nil {
+				err = fmt.Errorf("%w, saving the artifact index failed too: %v", err, saveErr)
+			}
+			return 0, fmt.Errorf("failed to import %s: %w", file.Name, err)
+		}
+		if entry, ok := imported[file.Name]; ok {
+			index[file.Name] = entry
+		} else {
+			delete(index, file.Name) // a stale entry would reject the imported file
+		}
+	}
+	return len(files), saveIndex(index)
+}
+
+func readIndexFile(file *zip.File) ([]Entry, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var entries []Entry
+	if err := json.NewDecoder(rc).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to parse the artifact index in the zip: %w", err)
+	}
+	return entries, nil
+}
+
+func extractFile(file *zip.File, target string) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func addFile(archive *zip.Writer, file, name string) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name, header.Method = name, zip.Deflate
+	writer, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	source, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	_, err = io.Copy(writer, source)
+	return err
+}
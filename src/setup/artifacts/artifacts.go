@@ -0,0 +1,465 @@
+// Package artifacts is the local cache every installer downloads through, so setup works offline and installs are reproducible.
+package artifacts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/update"
+)
+
+/*
+Artifact cache
+- Artifacts are files installers download (SteamCMD, BepInEx, SSCM, SLP, release lists), stored flat in
+  config.ArtifactCacheFolder under a name that identifies their content, e.g. BepInEx_linux_x64_5.4.23.2.zip
+- Fetch looks in the cache first, then asks the mirror (config.ArtifactMirrorURL + "/" + name), then the upstream URL.
+  Refresh is for content that changes under the same URL (branch files, release lists): upstream first, then mirror and cache
+- Whatever is downloaded is stored in the cache with its size and sha256 in index.json; a cached file that no longer
+  matches its index entry is downloaded again. Files dropped into the cache by hand have no entry and are used as they are
+- Downloads are checked against the size and sha256 the artifact expects, if it knows them. Mirror downloads are otherwise
+  checked against the upstream size; when upstream cannot tell, the file is cached without an index entry like a hand dropped one
+- Each artifact is downloaded under its own lock, mu only guards the index, so installers download different artifacts in parallel
+- A populated cache can be exported as a zip and imported on an offline host, or served by any static file server as the mirror
+*/
+
+const indexFileName = "index.json"
+
+// Source is where an artifact came from
+type Source string
+
+const (
+	SourceCache    Source = "cache"
+	SourceMirror   Source = "mirror"
+	SourceUpstream Source = "upstream"
+)
+
+// Artifact is a file an installer downloads
+type Artifact struct {
+	Name    string // file name in the cache and on the mirror
+	URL     string // upstream download URL
+	Mutable bool   // the upstream URL serves changing content, populating the cache refreshes it
+	Size    int64  // expected size if upstream publishes it, 0 if unknown
+	SHA256  string // expected sha256 in hex if upstream publishes it, empty if unknown
+}
+
+// Entry is the index record of a cached artifact
+type Entry struct {
+	Name     string    `json:"name"`
+	URL      string    `json:"url,omitempty"` // empty for files dropped into the cache by hand
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+	StoredAt time.Time `json:"storedAt"`
+	Indexed  bool      `json:"-"`
+}
+
+var (
+	mu         sync.Mutex // guards the index and renames into the cache folder, take it after the artifact lock
+	httpClient = &http.Client{Timeout: 5 * time.Minute}
+
+	locksMu sync.Mutex
+	locks   = map[string]*sync.Mutex{} // artifact name -> lock held while it is looked up or downloaded
+)
+
+// lockArtifact locks one artifact, so it is not downloaded twice at once, and returns the unlock func
+func lockArtifact(name string) func() {
+	locksMu.Lock()
+	lock, ok := locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		locks[name] = lock
+	}
+	locksMu.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+// FromURL names an artifact after the last element of its URL, for URLs whose file name already carries the version
+func FromURL(rawURL string) Artifact {
+	name := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		name = parsed.Path
+	}
+	return Artifact{Name: path.Base(name), URL: rawURL}
+}
+
+// Fetch returns the path of the cached artifact, downloading it from the mirror or upstream if it is not cached
+func Fetch(artifact Artifact) (string, Source, error) {
+	if err := validateName(artifact.Name); err != nil {
+		return "", "", err
+	}
+	defer lockArtifact(artifact.Name)()
+
+	if cached, ok := cachedFile(artifact.Name); ok {
+		logger.Install.Debug("📦 Using cached " + artifact.Name)
+		return cached, SourceCache, nil
+	}
+	var errs []error
+	for _, source := range downloadSources(false) {
+		cached, err := download(artifact, source)
+		if err == nil {
+			return cached, source, nil
+		}
+		errs = append(errs, err)
+	}
+	return "", "", fmt.Errorf("%s is not cached and could not be downloaded: %w", artifact.Name, errors.Join(errs...))
+}
+
+// Refresh downloads the artifact again and returns the path of the cached copy, falling back to the mirror and the cache if upstream fails
+func Refresh(artifact Artifact) (string, Source, error) {
+	if err := validateName(artifact.Name); err != nil {
+		return "", "", err
+	}
+	defer lockArtifact(artifact.Name)()
+
+	var errs []error
+	for _, source := range downloadSources(true) {
+		cached, err := download(artifact, source)
+		if err == nil {
+			return cached, source, nil
+		}
+		errs = append(errs, err)
+	}
+	if cached, ok := cachedFile(artifact.Name); ok {
+		logger.Install.Warn(fmt.Sprintf("⚠️ Could not refresh %s, using the cached copy: %v", artifact.Name, errors.Join(errs...)))
+		return cached, SourceCache, nil
+	}
+	return "", "", fmt.Errorf("%s could not be downloaded and is not cached: %w", artifact.Name, errors.Join(errs...))
+}
+
+// FetchTo copies the artifact to dest, see Fetch
+func FetchTo(artifact Artifact, dest string) (Source, error) {
+	cached, source, err := Fetch(artifact)
+	if err != nil {
+		return "", err
+	}
+	return source, copyFile(cached, dest)
+}
+
+// RefreshTo copies the refreshed artifact to dest, see Refresh
+func RefreshTo(artifact Artifact, dest string) (Source, error) {
+	cached, source, err := Refresh(artifact)
+	if err != nil {
+		return "", err
+	}
+	return source, copyFile(cached, dest)
+}
+
+// Populate stores the artifacts in the cache, downloading mutable ones again. Returns the number of artifacts that had to be downloaded.
+func Populate(list []Artifact) (int, error) {
+	downloaded := 0
+	var errs []error
+	for _, artifact := range list {
+		fetch := Fetch
+		if artifact.Mutable {
+			fetch = Refresh
+		}
+		_, source, err := fetch(artifact)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if source != SourceCache {
+			downloaded++
+		}
+		logger.Install.Info(fmt.Sprintf("✅ %s (%s)", artifact.Name, source))
+	}
+	return downloaded, errors.Join(errs...)
+}
+
+// Remove deletes an artifact from the cache, e.g. after it failed a verification against upstream metadata
+func Remove(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	defer lockArtifact(name)()
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.Remove(filepath.Join(config.GetArtifactCacheFolder(), name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	delete(index, name)
+	return saveIndex(index)
+}
+
+// List returns the cached artifacts by name, including files without an index entry
+func List() ([]Entry, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return listCache()
+}
+
+func listCache() ([]Entry, error) {
+	dir := config.GetArtifactCacheFolder()
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the artifact cache: %w", err)
+	}
+	index, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	entries := []Entry{}
+	for _, file := range files {
+		if file.IsDir() || file.Name() == indexFileName || strings.HasSuffix(file.Name(), ".part") {
+			continue
+		}
+		entry, ok := index[file.Name()]
+		if !ok {
+			info, err := file.Info()
+			if err != nil {
+				continue
+			}
+			entry = Entry{Name: file.Name(), Size: info.Size(), StoredAt: info.ModTime()}
+		}
+		entry.Indexed = ok
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Name, b.Name) })
+	return entries, nil
+}
+
+// downloadSources lists where an artifact can be downloaded from, the mirror only if one is configured
+func downloadSources(upstreamFirst bool) []Source {
+	if config.GetArtifactMirrorURL() == "" {
+		return []Source{SourceUpstream}
+	}
+	if upstreamFirst {
+		return []Source{SourceUpstream, SourceMirror}
+	}
+	return []Source{SourceMirror, SourceUpstream}
+}
+
+// cachedFile returns the path of a cached artifact that matches its index entry. Caller must hold the artifact lock.
+func cachedFile(name string) (string, bool) {
+	cached := filepath.Join(config.GetArtifactCacheFolder(), name)
+	if _, err := os.Stat(cached); err != nil {
+		return "", false
+	}
+	mu.Lock()
+	index, err := loadIndex()
+	mu.Unlock()
+	if err != nil {
+		logger.Install.Warn(err.Error())
+	}
+	entry, indexed := index[name]
+	if !indexed {
+		logger.Install.Debug(name + " is in the artifact cache without an index entry, using it unverified")
+		return cached, true
+	}
+	size, sum, err := hashFile(cached)
+	if err != nil || size != entry.Size || sum != entry.SHA256 {
+		logger.Install.Warn("⚠️ Cached " + name + " does not match its index entry, downloading it again")
+		return "", false
+	}
+	return cached, true
+}
+
+// download stores the artifact from the mirror or upstream in the cache. Caller must hold the artifact lock.
+func download(artifact Artifact, source Source) (string, error) {
+	downloadURL := artifact.URL
+	if source == SourceMirror {
+		downloadURL = strings.TrimSuffix(config.GetArtifactMirrorURL(), "/") + "/" + url.PathEscape(artifact.Name)
+	}
+	if downloadURL == "" {
+		return "", fmt.Errorf("%s has no %s URL", artifact.Name, source)
+	}
+
+	dir := config.GetArtifactCacheFolder()
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create the artifact cache: %w", err)
+	}
+	cached := filepath.Join(dir, artifact.Name)
+	partial := cached + ".part"
+	defer os.Remove(partial)
+
+	logger.Install.Info(fmt.Sprintf("📥 Downloading %s from %s", artifact.Name, source))
+	resp, err := httpClient.Get(downloadURL)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: bad status: %s", source, resp.Status)
+	}
+
+	out, err := os.Create(partial)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	counter := &update.WriteCounter{Total: resp.ContentLength}
+	size, err := io.Copy(io.MultiWriter(out, hash), io.TeeReader(resp.Body, counter))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	verified, err := verifyDownload(artifact, source, size, sum)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.Rename(partial, cached); err != nil {
+		return "", err
+	}
+	index, err := loadIndex()
+	if err != nil {
+		logger.Install.Warn(err.Error() + ", starting a new index")
+		index = map[string]Entry{}
+	}
+	if verified {
+		index[artifact.Name] = Entry{Name: artifact.Name, URL: artifact.URL, Size: size, SHA256: sum, StoredAt: time.Now()}
+	} else {
+		delete(index, artifact.Name)
+	}
+	if err := saveIndex(index); err != nil {
+		logger.Install.Warn(err.Error())
+	}
+	return cached, nil
+}
+
+// verifyDownload checks a download against the size and sha256 the artifact expects, or a mirror download against the upstream size.
+// Returns false for a mirror download nothing could be checked against, which is then cached without an index entry.
+func verifyDownload(artifact Artifact, source Source, size int64, sum string) (bool, error) {
+	if artifact.Size > 0 && size != artifact.Size {
+		return false, fmt.Errorf("downloaded %d bytes of %s, expected %d", size, artifact.Name, artifact.Size)
+	}
+	if artifact.SHA256 != "" && !strings.EqualFold(sum, artifact.SHA256) {
+		return false, fmt.Errorf("sha256 of %s is %s, expected %s", artifact.Name, sum, artifact.SHA256)
+	}
+	if source != SourceMirror || artifact.Size > 0 || artifact.SHA256 != "" {
+		return true, nil
+	}
+	upstreamSize, err := upstreamSize(artifact.URL)
+	if err != nil {
+		logger.Install.Warn(fmt.Sprintf("⚠️ Could not check %s from the mirror against upstream (%v), caching it unverified", artifact.Name, err))
+		return false, nil
+	}
+	if size != upstreamSize {
+		return false, fmt.Errorf("the mirror served %d bytes of %s, upstream has %d", size, artifact.Name, upstreamSize)
+	}
+	return true, nil
+}
+
+// upstreamSize asks upstream for the size of an artifact without downloading it
+func upstreamSize(rawURL string) (int64, error) {
+	if rawURL == "" {
+		return 0, errors.New("no upstream URL")
+	}
+	resp, err := httpClient.Head(rawURL)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("bad status: %s", resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return 0, errors.New("upstream does not report a size")
+	}
+	return resp.ContentLength, nil
+}
+
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || name == indexFileName || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid artifact name %q", name)
+	}
+	return nil
+}
+
+func hashFile(file string) (int64, string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func copyFile(source, dest string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return err
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func loadIndex() (map[string]Entry, error) {
+	index := map[string]Entry{}
+	data, err := os.ReadFile(filepath.Join(config.GetArtifactCacheFolder(), indexFileName))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return index, fmt.Errorf("failed to read the artifact index: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return index, fmt.Errorf("failed to parse the artifact index: %w", err)
+	}
+	for _, entry := range entries {
+		index[entry.Name] = entry
+	}
+	return index, nil
+}
+
+func saveIndex(index map[string]Entry) error {
+	entries := make([]Entry, 0, len(index))
+	for _, entry := range index {
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Name, b.Name) })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the artifact index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(config.GetArtifactCacheFolder(), indexFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write the artifact index: %w", err)
+	}
+	return nil
+}
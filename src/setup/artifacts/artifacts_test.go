@@ -0,0 +1,170 @@
+package artifacts
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+func useCache(t *testing.T, dir, mirror string) {
+	t.Helper()
+	oldDir, oldMirror := config.ArtifactCacheFolder, config.ArtifactMirrorURL
+	config.ArtifactCacheFolder, config.ArtifactMirrorURL = dir, mirror
+	t.Cleanup(func() { config.ArtifactCacheFolder, config.ArtifactMirrorURL = oldDir, oldMirror })
+}
+
+func TestFetchUsesCacheBeforeUpstream(t *testing.T) {
+	requests := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("bepinex archive"))
+	}))
+	defer upstream.Close()
+	useCache(t, t.TempDir(), "")
+
+	artifact := FromURL(upstream.URL + "/download/BepInEx_linux_x64_5.4.23.2.zip")
+	if artifact.Name != "BepInEx_linux_x64_5.4.23.2.zip" {
+		t.Fatalf("unexpected artifact name %q", artifact.Name)
+	}
+	for i, want := range []Source{SourceUpstream, SourceCache} {
+		cached, source, err := Fetch(artifact)
+		if err != nil || source != want {
+			t.Fatalf("fetch %d: source %s, err %v, want %s", i, source, err, want)
+		}
+		if content, _ := os.ReadFile(cached); string(content) != "bepinex archive" {
+			t.Fatalf("fetch %d: unexpected content %q", i, content)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected one upstream request, got %d", requests)
+	}
+
+	// a cached copy that no longer matches the index is downloaded again
+	os.WriteFile(filepath.Join(config.ArtifactCacheFolder, artifact.Name), []byte("tampered"), 0644)
+	if _, source, err := Fetch(artifact); err != nil || source != SourceUpstream {
+		t.Fatalf("expected a new download of the tampered artifact, got %s %v", source, err)
+	}
+}
+
+func TestFetchFallsBackToMirror(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/steamcmd.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("steamcmd"))
+	}))
+	defer mirror.Close()
+	useCache(t, t.TempDir(), "")
+
+	artifact := FromURL(upstream.URL + "/client/installer/steamcmd.zip")
+	if _, _, err := Fetch(artifact); err == nil || !strings.Contains(err.Error(), "503 Service Unavailable") {
+		t.Fatalf("expected the upstream status error, got %v", err)
+	}
+
+	config.ArtifactMirrorURL = mirror.URL + "/"
+	if _, source, err := Fetch(artifact); err != nil || source != SourceMirror {
+		t.Fatalf("expected the mirror copy, got %s %v", source, err)
+	}
+	// upstream still fails, a refresh keeps the cached copy
+	if _, source, err := Refresh(Artifact{Name: "steamcmd.zip", URL: upstream.URL + "/client/installer/steamcmd.zip"}); err != nil || source != SourceMirror {
+		t.Fatalf("expected the refresh to fall back to the mirror, got %s %v", source, err)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("content of " + r.URL.Path))
+	}))
+	defer upstream.Close()
+	useCache(t, t.TempDir(), "")
+
+	if _, err := Populate([]Artifact{FromURL(upstream.URL + "/a.zip"), FromURL(upstream.URL + "/b.zip")}); err != nil {
+		t.Fatal(err)
+	}
+	var exported bytes.Buffer
+	if count, err := Export(&exported); err != nil || count != 2 {
+		t.Fatalf("export: %d %v", count, err)
+	}
+
+	// the offline host
+	upstream.Close()
+	useCache(t, t.TempDir(), "")
+	if count, err := Import(bytes.NewReader(exported.Bytes()), int64(exported.Len())); err != nil || count != 2 {
+		t.Fatalf("import: %d %v", count, err)
+	}
+	cached, source, err := Fetch(FromURL(upstream.URL + "/b.zip"))
+	if err != nil || source != SourceCache {
+		t.Fatalf("expected the imported artifact, got %s %v", source, err)
+	}
+	if content, _ := os.ReadFile(cached); string(content) != "content of /b.zip" {
+		t.Fatalf("unexpected imported content %q", content)
+	}
+	entries, err := List()
+	if err != nil || len(entries) != 2 || !entries[0].Indexed {
+		t.Fatalf("unexpected cache listing %+v %v", entries, err)
+	}
+}
+
+func TestMirrorDownloadsAreVerified(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("the real BepInEx"))
+	}))
+	defer upstream.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("something else"))
+	}))
+	defer mirror.Close()
+	useCache(t, t.TempDir(), mirror.URL)
+
+	artifact := FromURL(upstream.URL + "/BepInEx.zip")
+	if _, source, err := Fetch(artifact); err != nil || source != SourceUpstream {
+		t.Fatalf("a mirror copy with the wrong size must be skipped for upstream, got %s %v", source, err)
+	}
+	if entries, _ := List(); len(entries) != 1 || !entries[0].Indexed || entries[0].Size != int64(len("the real BepInEx")) {
+		t.Fatalf("expected the upstream copy in the index, got %+v", entries)
+	}
+
+	// a known checksum rejects any source that does not match it
+	pinned := Artifact{Name: "slp.zip", URL: upstream.URL + "/slp.zip", SHA256: strings.Repeat("0", 64)}
+	if _, _, err := Fetch(pinned); err == nil || !strings.Contains(err.Error(), "sha256 of slp.zip") {
+		t.Fatalf("expected a checksum error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(config.ArtifactCacheFolder, "slp.zip")); !os.IsNotExist(err) {
+		t.Fatal("a download that fails verification must not enter the cache")
+	}
+}
+
+func TestImportChecksEveryArtifactFirst(t *testing.T) {
+	useCache(t, t.TempDir(), "")
+
+	var exported bytes.Buffer
+	archive := zip.NewWriter(&exported)
+	index, _ := json.Marshal([]Entry{{Name: "b.zip", Size: 1, SHA256: "not the hash"}})
+	for name, content := range map[string][]byte{"a.zip": []byte("good"), "b.zip": []byte("bad"), indexFileName: index} {
+		w, _ := archive.Create(name)
+		w.Write(content)
+	}
+	archive.Close()
+
+	if _, err := Import(bytes.NewReader(exported.Bytes()), int64(exported.Len())); err == nil {
+		t.Fatal("expected the mismatching artifact to fail the import")
+	}
+	// nothing may land in the cache, or a.zip would sit there without its index entry
+	files, _ := os.ReadDir(config.ArtifactCacheFolder)
+	if len(files) != 0 {
+		t.Fatalf("a failed import must leave the cache untouched, found %d files", len(files))
+	}
+}
@@ -0,0 +1,21 @@
+package setup
+
+import (
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/artifacts"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
+)
+
+// InstallerArtifacts lists what SteamCMD, BepInEx and SSCM installs download, for every platform, so a populated
+// artifact cache can set up a host of either platform offline
+func InstallerArtifacts() []artifacts.Artifact {
+	if config.GetBranch() == "release" || config.GetBranch() == "Release" {
+		downloadBranch = "main"
+	} else {
+		downloadBranch = config.GetBranch()
+	}
+
+	list := steamcmd.SteamCMDArtifacts()
+	list = append(list, artifacts.FromURL(bepInExWindowsURL), artifacts.FromURL(bepInExLinuxURL))
+	return append(list, fileArtifact(sscmURL(downloadBranch)))
+}
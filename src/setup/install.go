@@ -5,10 +5,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"os" // Added for filepath.Dir
-	fp "path/filepath"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,6 +14,7 @@ import (
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/artifacts"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/update"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
 )
@@ -119,11 +118,11 @@ func downloadAllFiles(files map[string]string) {
 			defer wg.Done()
 			fileName := filepath[strings.LastIndex(filepath, "/")+1:]
 			logger.Install.Info("Downloading " + fileName + "...")
-			err := downloadFile(filepath, url)
+			source, err := artifacts.FetchTo(fileArtifact(url), filepath)
 			if err != nil {
 				logger.Install.Error("❌Error downloading " + fileName + ": " + err.Error())
 			} else {
-				logger.Install.Info("✅Downloaded " + fileName + " successfully from branch " + downloadBranch)
+				logger.Install.Info("✅Downloaded " + fileName + " successfully from branch " + downloadBranch + " (" + string(source) + ")")
 			}
 		}(filepath, url)
 	}
@@ -156,7 +155,7 @@ func checkAndUpdateFile(filepath, url string) {
 	if _, err := os.Stat(filepath); os.IsNotExist(err) {
 		// File doesn't exist locally, download it
 		logger.Install.Info("Downloading " + fileName + "...")
-		err := downloadFile(filepath, url)
+		_, err := artifacts.FetchTo(fileArtifact(url), filepath)
 		if err != nil {
 			logger.Install.Error("❌Error downloading " + fileName + ": " + err.Error())
 		} else {
@@ -193,7 +192,7 @@ func checkAndUpdateFile(filepath, url string) {
 
 		if localHash != remoteHash {
 			logger.Install.Info("🔄Updating " + fileName + " due to differences...")
-			err := downloadFile(filepath, url)
+			_, err := artifacts.RefreshTo(fileArtifact(url), filepath)
 			if err != nil {
 				logger.Install.Error("❌Error updating " + fileName + ": " + err.Error())
 			} else {
@@ -309,36 +308,13 @@ func handlePrimaryRateLimit(reset, status string) error {
 	return fmt.Errorf("bad status: %s, %s", status, errMsg)
 }
 
-// downloadFile downloads a file from the given URL to the specified filepath
-func downloadFile(filepath, url string) error {
-	client := &http.Client{Timeout: 45 * time.Second}
-	return downloadFileWithClient(client, filepath, url)
-}
-
-func downloadFileWithClient(client *http.Client, filepath, url string) error {
-	// Ensure the directory exists
-	dir := fp.Dir(filepath)
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return err
-	}
-	// Create the file
-	out, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-	// Fetch the data
-	resp, err := client.Get(url)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-	// Write to file
-	_, err = io.Copy(out, resp.Body)
-	return err
+// fileArtifact names a file of this repository by branch, e.g. main-SSCM.dll, so branches do not share a cache entry.
+// The branch file changes under the same URL, populating the cache refreshes it.
+func fileArtifact(url string) artifacts.Artifact {
+	artifact := artifacts.FromURL(url)
+	artifact.Name = strings.ReplaceAll(downloadBranch, "/", "_") + "-" + artifact.Name
+	artifact.Mutable = true
+	return artifact
 }
 
 // checkAndCreateBlacklist ensures Blacklist.txt exists in the root directory
@@ -2,22 +2,30 @@ package setup
 
 import (
 	"fmt"
-	"net/http"
 	"os"
 	"runtime"
 	"sync"
-	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/artifacts"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
 )
 
 // BepInEx version: 5.4.23.2 or v5-lts
 // SSCM version: 1.0.0
 
+const (
+	bepInExWindowsURL = "https://github.com/BepInEx/BepInEx/releases/download/v5.4.23.2/BepInEx_win_x64_5.4.23.2.zip"
+	bepInExLinuxURL   = "https://github.com/BepInEx/BepInEx/releases/download/v5.4.23.2/BepInEx_linux_x64_5.4.23.2.zip"
+)
+
 var installMutex sync.Mutex
 
+func sscmURL(branch string) string {
+	return fmt.Sprintf("https://raw.githubusercontent.com/JacksonTheMaster/StationeersServerUI/%s/sscm/SSCM.dll", branch)
+}
+
 func CheckAndDownloadSSCM() {
 	SSCMPluginDir := config.GetSSCMPluginDir()
 	sscmDir := config.GetSSCMWebDir()
@@ -34,7 +42,7 @@ func CheckAndDownloadSSCM() {
 
 	// Define file mappings
 	files := map[string]string{
-		SSCMPluginDir + "SSCM.dll": fmt.Sprintf("https://raw.githubusercontent.com/JacksonTheMaster/StationeersServerUI/%s/sscm/SSCM.dll", downloadBranch),
+		SSCMPluginDir + "SSCM.dll": sscmURL(downloadBranch),
 	}
 
 	// Check if the directory exists
@@ -92,10 +100,10 @@ func CheckAndInstallBepInEx() error {
 	// Determine the URL based on platform
 	var url string
 	if runtime.GOOS == "windows" {
-		url = "https://github.com/BepInEx/BepInEx/releases/download/v5.4.23.2/BepInEx_win_x64_5.4.23.2.zip"
+		url = bepInExWindowsURL
 		logger.Install.Info("Detected Windows platform, using Windows BepInEx package")
 	} else {
-		url = "https://github.com/BepInEx/BepInEx/releases/download/v5.4.23.2/BepInEx_linux_x64_5.4.23.2.zip"
+		url = bepInExLinuxURL
 		logger.Install.Info("Detected non-Windows platform, using Linux BepInEx package")
 	}
 
@@ -109,37 +117,28 @@ func CheckAndInstallBepInEx() error {
 	return nil
 }
 
-// downloadAndInstallBepInEx downloads the BepInEx zip and extracts it to the current directory
+// downloadAndInstallBepInEx fetches the BepInEx zip through the artifact cache and extracts it to the current directory
 func downloadAndInstallBepInEx(url string) error {
-	// Create a temporary file to store the downloaded zip
-	tempFile, err := os.CreateTemp("", "bepinex_*.zip")
+	// The versioned zip is kept in the artifact cache, so reinstalls work offline
+	logger.Install.Info("📥Fetching BepInEx from: " + url)
+	cached, source, err := artifacts.Fetch(artifacts.FromURL(url))
 	if err != nil {
-		return fmt.Errorf("failed to create temporary file: %w", err)
+		return fmt.Errorf("failed to download BepInEx: %w", err)
 	}
-	defer os.Remove(tempFile.Name()) // Clean up the zip file when done
+	logger.Install.Debug("BepInEx zip from " + string(source) + ": " + cached)
 
-	// Download the BepInEx zip file
-	logger.Install.Info("📥Downloading BepInEx from: " + url)
-	client := &http.Client{Timeout: 45 * time.Second}
-	err = downloadFileWithClient(client, tempFile.Name(), url)
+	zipFile, err := os.Open(cached)
 	if err != nil {
-		return fmt.Errorf("failed to download BepInEx: %w", err)
+		return fmt.Errorf("failed to open downloaded zip: %w", err)
 	}
+	defer zipFile.Close()
 
 	// Get file info for the zip
-	fileInfo, err := tempFile.Stat()
+	fileInfo, err := zipFile.Stat()
 	if err != nil {
 		return fmt.Errorf("failed to get file info: %w", err)
 	}
 
-	// Reopen the file for reading
-	tempFile.Close()
-	zipFile, err := os.Open(tempFile.Name())
-	if err != nil {
-		return fmt.Errorf("failed to open downloaded zip: %w", err)
-	}
-	defer zipFile.Close()
-
 	// Extract the zip file to the current directory
 	logger.Install.Info("📦Extracting BepInEx to current directory")
 	err = steamcmd.Unzip(zipFile, fileInfo.Size(), ".")
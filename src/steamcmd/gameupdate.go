@@ -0,0 +1,133 @@
+package steamcmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+/*
+Game server update detection
+- AppInfoPoller keeps the latest build ID of every branch in branches
+- The installed build ID is read from the app manifest SteamCMD writes next to the game files
+- Every check that finds a newer build of GameBranch is passed to the handler set with SetGameUpdateHandler,
+  the update manager raises the event and schedules the automatic update
+*/
+
+// GameUpdateStatus compares the installed game server build with the latest build of the configured branch
+type GameUpdateStatus struct {
+	Branch           string    `json:"branch"`
+	InstalledBuildID string    `json:"installedBuildId"`
+	LatestBuildID    string    `json:"latestBuildId"`
+	Available        bool      `json:"available"`
+	CheckedAt        time.Time `json:"checkedAt,omitzero"`
+}
+
+var (
+	gameUpdateStatus   GameUpdateStatus
+	gameUpdateMu       sync.RWMutex
+	gameUpdateHandler  func(GameUpdateStatus)
+	manifestBuildIDPat = regexp.MustCompile(`"buildid"\s+"(\d+)"`)
+)
+
+// SetGameUpdateHandler sets the function called whenever a check finds a newer build. It must not block.
+func SetGameUpdateHandler(handler func(GameUpdateStatus)) {
+	gameUpdateMu.Lock()
+	defer gameUpdateMu.Unlock()
+	gameUpdateHandler = handler
+}
+
+// GetGameUpdateStatus returns the result of the last check
+func GetGameUpdateStatus() GameUpdateStatus {
+	gameUpdateMu.RLock()
+	defer gameUpdateMu.RUnlock()
+	return gameUpdateStatus
+}
+
+// InstalledBuildID reads the build ID of the installed game server from steamapps/appmanifest_<appid>.acf
+func InstalledBuildID() (string, error) {
+	manifest := filepath.Join("steamapps", "appmanifest_"+config.GetGameServerAppID()+".acf")
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return "", err
+	}
+	match := manifestBuildIDPat.FindSubmatch(data)
+	if match == nil {
+		return "", fmt.Errorf("no build ID in %s", manifest)
+	}
+	return string(match[1]), nil
+}
+
+// latestBuildID returns the build ID of a branch as last seen by the poller
+func latestBuildID(branch string) (string, bool) {
+	branchesLock.RLock()
+	defer branchesLock.RUnlock()
+	buildID, ok := branches[branch]
+	return buildID, ok
+}
+
+// checkForGameUpdate compares the installed build with the poller's build of GameBranch and calls the update handler if they differ
+func checkForGameUpdate() {
+	branch := config.GetGameBranch()
+	latest, ok := latestBuildID(branch)
+	if !ok {
+		logger.Install.Debug("🔍 No build ID known for branch " + branch + " yet")
+		return
+	}
+
+	installed, err := InstalledBuildID()
+	if err != nil {
+		// without a manifest, e.g. files copied in by hand, the first build seen is taken as installed
+		logger.Install.Debug("🔍 Could not read the installed build ID: " + err.Error())
+		installed = config.GetCurrentBranchBuildID()
+		if installed == "" {
+			installed = latest
+		}
+	}
+	config.SetCurrentBranchBuildID(installed)
+
+	status := GameUpdateStatus{
+		Branch:           branch,
+		InstalledBuildID: installed,
+		LatestBuildID:    latest,
+		Available:        installed != latest,
+		CheckedAt:        time.Now(),
+	}
+	gameUpdateMu.Lock()
+	gameUpdateStatus = status
+	handler := gameUpdateHandler
+	gameUpdateMu.Unlock()
+
+	if status.Available && handler != nil {
+		handler(status)
+	}
+}
+
+// markGameUpdated refreshes the update status after SteamCMD updated the game files
+func markGameUpdated() {
+	if _, err := InstalledBuildID(); err != nil {
+		if latest, ok := latestBuildID(config.GetGameBranch()); ok {
+			config.SetCurrentBranchBuildID(latest)
+		}
+	}
+	checkForGameUpdate()
+}
+
+// RefreshGameUpdateStatus compares the installed build with the latest known build again, e.g. after install files were restored
+func RefreshGameUpdateStatus() {
+	checkForGameUpdate()
+}
+
+// KnownBranches returns the branches and their latest build IDs as last seen by the poller
+func KnownBranches() map[string]string {
+	branchesLock.RLock()
+	defer branchesLock.RUnlock()
+	return maps.Clone(branches)
+}
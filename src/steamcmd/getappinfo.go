@@ -15,8 +15,6 @@ import (
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
-	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/commandmgr"
-	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
 )
 
 var (
@@ -35,8 +33,8 @@ func AppInfoPoller() {
 		// No previous poller running
 	}
 
-	// if AutoGameServerUpdates is disabled, dont start the poller.
-	if !config.GetAllowAutoGameServerUpdates() {
+	// The poller always runs so available updates are reported, AllowAutoGameServerUpdates only decides whether they are applied.
+	if config.GetSkipSteamCMD() {
 		return
 	}
 	// Start new poller
@@ -125,6 +123,8 @@ func getAppInfo() error {
 	//}
 	// Run the command
 	err = cmd.Run()
+	steamMu.Unlock()
+	logger.Core.Debug("🔄 Unlocking SteamMu after SteamCMD AppInfo...")
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			logger.Install.Errorf("❌ SteamCMD app info failed (code %d): %s\n", exitErr.ExitCode(), stderr.String())
@@ -134,9 +134,6 @@ func getAppInfo() error {
 		return fmt.Errorf("failed to run SteamCMD app info: %w", err)
 	}
 
-	steamMu.Unlock()
-	logger.Core.Debug("🔄 Unlocking SteamMu after SteamCMD AppInfo...")
-
 	// Extract branches and build IDs
 	newBranches, err := extractBranches(stdout.String())
 	if err != nil {
@@ -148,43 +145,8 @@ func getAppInfo() error {
 	branchesLock.Lock()
 	maps.Copy(branches, newBranches)
 	branchesLock.Unlock()
-	wasRunning := false
-	currentBranch := config.GetGameBranch()
-	if buildID, ok := branches[currentBranch]; ok {
-		if config.GetCurrentBranchBuildID() != "" && config.GetCurrentBranchBuildID() != buildID {
-			logger.Install.Info("❗New gameserver update detected!")
-			if config.GetAllowAutoGameServerUpdates() {
-				logger.Install.Info("🔍 Updating gameserver via SteamCMD...")
-				if gamemgr.InternalIsServerRunning() {
-					commandmgr.WriteCommand("announce Update found, stopping server in 60 seconds...")
-					logger.Install.Info("❗Stopping server in 60 seconds...")
-					time.Sleep(20 * time.Second)
-					commandmgr.WriteCommand("announce Update found, stopping server in 40 seconds...")
-					time.Sleep(10 * time.Second)
-					commandmgr.WriteCommand("announce Update found, stopping server in 30 seconds...")
-					time.Sleep(10 * time.Second)
-					commandmgr.WriteCommand("announce Update found, saving and stopping server in 20 seconds...")
-					time.Sleep(20 * time.Second)
-
-					commandmgr.WriteCommand("SAVE")
-					time.Sleep(5 * time.Second)
-					commandmgr.WriteCommand("announce Update found, STOPPING SERVER NOW. World was Saved. ")
-					time.Sleep(5 * time.Second)
-					gamemgr.InternalStopServer()
-					wasRunning = true
-				}
-				_, err := InstallAndRunSteamCMD()
-				if err != nil {
-					logger.Install.Error("❌ Failed to update gameserver: " + err.Error() + "\n")
-				}
-				if wasRunning {
-					gamemgr.InternalStartServer()
-				}
-			}
-		}
-		config.SetCurrentBranchBuildID(buildID)
-	}
 
+	checkForGameUpdate()
 	return nil
 }
 
@@ -0,0 +1,106 @@
+package steamcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+// Install history actions
+const (
+	InstallActionUpdate   = "update"   // SteamCMD updated the configured branch
+	InstallActionSwitch   = "switch"   // SteamCMD installed a different branch than the last install
+	InstallActionRollback = "rollback" // an install snapshot was restored
+)
+
+// keep the history readable, nobody scrolls back further than this
+const maxInstallHistory = 200
+
+// InstallRecord is one entry of the install history, which build of which branch was installed when
+type InstallRecord struct {
+	Date     time.Time `json:"date"`
+	Action   string    `json:"action"`
+	Branch   string    `json:"branch"`
+	BuildID  string    `json:"buildId"`
+	JobID    string    `json:"jobId,omitempty"`
+	Snapshot string    `json:"snapshot,omitempty"` // snapshot taken before, or restored by, this install
+}
+
+var installHistoryMu sync.Mutex
+
+// GetInstallHistory returns the install history, newest first
+func GetInstallHistory() []InstallRecord {
+	installHistoryMu.Lock()
+	defer installHistoryMu.Unlock()
+	history := loadInstallHistory()
+	slices.Reverse(history)
+	return history
+}
+
+// RecordInstall appends an entry to the install history
+func RecordInstall(record InstallRecord) error {
+	installHistoryMu.Lock()
+	defer installHistoryMu.Unlock()
+
+	if record.Date.IsZero() {
+		record.Date = time.Now()
+	}
+	history := append(loadInstallHistory(), record)
+	if excess := len(history) - maxInstallHistory; excess > 0 {
+		history = slices.Delete(history, 0, excess)
+	}
+
+	path := config.GetInstallHistoryFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create install history directory: %w", err)
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode install history: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write install history: %w", err)
+	}
+	return nil
+}
+
+// loadInstallHistory reads the history, oldest first. Caller must hold installHistoryMu.
+func loadInstallHistory() []InstallRecord {
+	var history []InstallRecord
+	data, err := os.ReadFile(config.GetInstallHistoryFilePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Install.Error("Failed to read install history: " + err.Error())
+		}
+		return history
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		logger.Install.Error("Failed to parse install history: " + err.Error())
+	}
+	return history
+}
+
+// recordJobInstall adds a successful update job to the history, as a branch switch if the last install was another branch
+func recordJobInstall(job UpdateJob) {
+	action := InstallActionUpdate
+	if history := GetInstallHistory(); len(history) > 0 && history[0].Branch != job.Branch {
+		action = InstallActionSwitch
+	}
+	err := RecordInstall(InstallRecord{
+		Date:    job.FinishedAt,
+		Action:  action,
+		Branch:  job.Branch,
+		BuildID: job.NewBuildID,
+		JobID:   job.ID,
+	})
+	if err != nil {
+		logger.Install.Error("❌ " + err.Error())
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 )
 
-func installSteamCMD(platform string, steamCMDDir string, downloadURL string, extractFunc ExtractorFunc) (int, error) {
+func installSteamCMD(platform string, steamCMDDir string, downloadURL string, extractFunc ExtractorFunc, job *UpdateJob) (int, error) {
 	// Check if SteamCMD is already installed
 	if _, err := os.Stat(steamCMDDir); os.IsNotExist(err) {
 		logger.Install.Warn("⚠️ SteamCMD not found for " + platform + ", downloading...\n")
@@ -58,15 +58,15 @@ func installSteamCMD(platform string, steamCMDDir string, downloadURL string, ex
 	}
 
 	// Run SteamCMD and return its exit status and error
-	return runSteamCMD(steamCMDDir)
+	return runSteamCMD(steamCMDDir, job)
 }
 
 // installSteamCMDLinux downloads and installs SteamCMD on Linux.
-func installSteamCMDLinux() (int, error) {
-	return installSteamCMD("Linux", SteamCMDLinuxDir, SteamCMDLinuxURL, untarWrapper)
+func installSteamCMDLinux(job *UpdateJob) (int, error) {
+	return installSteamCMD("Linux", SteamCMDLinuxDir, SteamCMDLinuxURL, untarWrapper, job)
 }
 
 // installSteamCMDWindows downloads and installs SteamCMD on Windows.
-func installSteamCMDWindows() (int, error) {
-	return installSteamCMD("Windows", SteamCMDWindowsDir, SteamCMDWindowsURL, Unzip)
+func installSteamCMDWindows(job *UpdateJob) (int, error) {
+	return installSteamCMD("Windows", SteamCMDWindowsDir, SteamCMDWindowsURL, Unzip, job)
 }
@@ -0,0 +1,95 @@
+package steamcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+)
+
+// dependencies of dependencies are resolved in rounds, mods rarely chain deeper than this
+const maxDependencyRounds = 5
+
+// ModSyncResult is the result of a mod sync job
+type ModSyncResult struct {
+	Downloaded []string `json:"downloaded"`         // workshop IDs
+	Removed    []string `json:"removed"`            // mod folders
+	Added      []string `json:"added,omitempty"`    // dependencies added to the manifest
+	LoadOrder  []string `json:"loadOrder"`          // enabled workshop IDs as written to modconfig.xml
+	Warnings   []string `json:"warnings,omitempty"` // pins that cannot be met, disabled dependencies, cycles
+	Logs       []string `json:"logs,omitempty"`     // SteamCMD download logs
+}
+
+// StartModSync queues a job that makes ./mods and modconfig.xml match the mod manifest
+func StartModSync() jobs.Job {
+	return jobs.Submit("mod-sync", jobs.LaneSteamCMD, "Sync mods with the mod manifest", func(ctx context.Context, job *jobs.Job) (any, error) {
+		return syncMods(ctx, job)
+	})
+}
+
+func syncMods(ctx context.Context, job *jobs.Job) (ModSyncResult, error) {
+	result := ModSyncResult{Downloaded: []string{}, Removed: []string{}, LoadOrder: []string{}}
+	manifest, err := modding.LoadModManifest()
+	if err != nil {
+		return result, err
+	}
+	if err := manifest.Validate(); err != nil {
+		return result, err
+	}
+	logger.Install.Infof("🔄 Syncing %d manifest mods...", len(manifest.Mods))
+
+	plan, added := modding.ResolveModSync(&manifest)
+	result.Added = append(result.Added, added...)
+	for _, folder := range plan.Remove {
+		job.Log("Removing " + folder + ", it is not in the mod manifest")
+		if err := os.RemoveAll(folder); err != nil {
+			return result, fmt.Errorf("failed to remove %s: %w", folder, err)
+		}
+		result.Removed = append(result.Removed, folder)
+	}
+
+	download := plan.Download
+	for round := 0; len(download) > 0; round++ {
+		logs, err := downloadWorkshopItems(ctx, download, job)
+		result.Logs = append(result.Logs, logs...)
+		result.Downloaded = append(result.Downloaded, download...)
+		if err != nil {
+			return result, err
+		}
+		if round == maxDependencyRounds {
+			result.Warnings = append(result.Warnings, "stopped resolving dependencies after "+fmt.Sprint(maxDependencyRounds)+" rounds")
+			break
+		}
+		// dependencies of the mods just downloaded are only known once their About.xml is on disk
+		added, _ := modding.AddDependencies(&manifest)
+		result.Added = append(result.Added, added...)
+		download = added
+	}
+
+	// pins and disabled dependencies are checked against what is installed now
+	final, added := modding.ResolveModSync(&manifest)
+	result.Added = append(result.Added, added...)
+	result.Warnings = append(result.Warnings, final.Warnings...)
+	order, warnings := modding.LoadOrder(manifest)
+	result.Warnings = append(result.Warnings, warnings...)
+	for _, mod := range order {
+		result.LoadOrder = append(result.LoadOrder, mod.WorkshopID)
+	}
+	job.SetProgress(95, "Writing modconfig.xml")
+	if err := modding.WriteModConfig(manifest, order); err != nil {
+		return result, err
+	}
+	if err := modding.UpdateManifestNames(manifest); err != nil {
+		return result, err
+	}
+
+	for _, warning := range result.Warnings {
+		logger.Install.Warn("⚠️ " + warning)
+		job.Log("Warning: " + warning)
+	}
+	logger.Install.Infof("✅ Mod sync done: %d downloaded, %d removed, %d enabled", len(result.Downloaded), len(result.Removed), len(result.LoadOrder))
+	return result, nil
+}
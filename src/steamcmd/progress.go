@@ -0,0 +1,108 @@
+package steamcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/ssestream"
+)
+
+/*
+SteamCMD progress parsing
+- SteamCMD prints lines like "Update state (0x61) downloading, progress: 45.23 (1234567 / 2727272)" while updating
+- progressWriter passes the output on to the terminal and parses every line into the running UpdateJob
+- Every change of state or percent is broadcast as JSON on the steamcmd/progress SSE stream
+*/
+
+// Progress is the last progress line SteamCMD printed
+type Progress struct {
+	JobID      string  `json:"jobId"`
+	State      string  `json:"state"` // e.g. "downloading", "verifying install", "committing", "done"
+	Percent    float64 `json:"percent"`
+	Bytes      int64   `json:"bytes"`
+	TotalBytes int64   `json:"totalBytes"`
+}
+
+// Error classes of a failed SteamCMD run
+const (
+	ErrorClassDiskFull     = "disk_full"
+	ErrorClassNoConnection = "no_connection"
+	ErrorClassExit8        = "exit_8"
+	ErrorClassUnknown      = "unknown"
+)
+
+var (
+	progressPattern = regexp.MustCompile(`Update state \((0x[0-9a-fA-F]+)\) ([^,]+), progress: ([\d.]+) \((\d+) / (\d+)\)`)
+	appStatePattern = regexp.MustCompile(`Error! App '\d+' state is (0x[0-9a-fA-F]+) after update job`)
+)
+
+// parseProgressLine extracts the progress from a SteamCMD output line
+func parseProgressLine(line string) (Progress, bool) {
+	match := progressPattern.FindStringSubmatch(line)
+	if match == nil {
+		if strings.Contains(line, "Success! App") && strings.Contains(line, "fully installed") {
+			return Progress{State: "done", Percent: 100}, true
+		}
+		return Progress{}, false
+	}
+	percent, _ := strconv.ParseFloat(match[3], 64)
+	done, _ := strconv.ParseInt(match[4], 10, 64)
+	total, _ := strconv.ParseInt(match[5], 10, 64)
+	return Progress{State: strings.TrimSpace(match[2]), Percent: percent, Bytes: done, TotalBytes: total}, true
+}
+
+// classifyErrorLine recognizes SteamCMD output that explains a failure, "" if the line explains nothing
+func classifyErrorLine(line string) string {
+	lower := strings.ToLower(line)
+	switch {
+	case strings.Contains(lower, "not enough disk space"), strings.Contains(lower, "no space left on device"),
+		strings.Contains(lower, "disk write failure"):
+		return ErrorClassDiskFull
+	case strings.Contains(lower, "no connection"), strings.Contains(lower, "connection to steam servers failed"),
+		strings.Contains(lower, "failed to connect"):
+		return ErrorClassNoConnection
+	}
+	// app state 0x202 is SteamCMD's code for a full disk
+	if match := appStatePattern.FindStringSubmatch(line); match != nil && match[1] == "0x202" {
+		return ErrorClassDiskFull
+	}
+	return ""
+}
+
+// progressWriter tees SteamCMD output to out and parses it line by line into job
+type progressWriter struct {
+	job *UpdateJob
+	out io.Writer
+	buf []byte
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.out.Write(p)
+	w.buf = append(w.buf, p...)
+	// progress lines are sometimes ended with \r only
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		line := string(w.buf[:i])
+		w.buf = w.buf[i+1:]
+		if line != "" {
+			w.job.handleOutputLine(line)
+		}
+	}
+	return len(p), nil
+}
+
+// broadcastProgress sends a progress update to the steamcmd/progress stream
+func broadcastProgress(progress Progress) {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return
+	}
+	ssestream.BroadcastSteamCMDProgress(string(data))
+}
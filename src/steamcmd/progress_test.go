@@ -0,0 +1,34 @@
+package steamcmd
+
+import "testing"
+
+func TestParseProgressLine(t *testing.T) {
+	progress, ok := parseProgressLine(" Update state (0x61) downloading, progress: 45.23 (1234567 / 2727272)")
+	if !ok || progress.State != "downloading" || progress.Percent != 45.23 || progress.Bytes != 1234567 || progress.TotalBytes != 2727272 {
+		t.Errorf("got %+v, %v", progress, ok)
+	}
+	if progress, ok := parseProgressLine("Success! App '600760' fully installed."); !ok || progress.State != "done" || progress.Percent != 100 {
+		t.Errorf("got %+v, %v", progress, ok)
+	}
+	if _, ok := parseProgressLine("Loading Steam API...OK"); ok {
+		t.Error("unrelated lines must not be progress")
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		line     string
+		exitCode int
+		want     string
+	}{
+		{"Error! App '600760' state is 0x202 after update job.", 8, ErrorClassDiskFull},
+		{"FAILED (No Connection)", 5, ErrorClassNoConnection},
+		{"Loading Steam API...OK", 8, ErrorClassExit8},
+		{"Loading Steam API...OK", 1, ErrorClassUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifyFailure(tt.exitCode, classifyErrorLine(tt.line)); got != tt.want {
+			t.Errorf("%q exit %d: got %q, want %q", tt.line, tt.exitCode, got, tt.want)
+		}
+	}
+}
@@ -5,10 +5,8 @@ import (
 	"archive/zip"
 	"bytes"
 	"compress/gzip"
-	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
@@ -16,9 +14,9 @@ import (
 	"runtime"
 	"slices"
 	"strings"
-	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/artifacts"
 )
 
 // isRelSymlink ensures `link` resolves to a path within `root`.
@@ -63,7 +61,7 @@ func createSteamCMDDirectory(steamCMDDir string) error {
 	return nil
 }
 
-// downloadAndExtractSteamCMD downloads and extracts SteamCMD.
+// downloadAndExtractSteamCMD fetches SteamCMD through the artifact cache and extracts it.
 func downloadAndExtractSteamCMD(downloadURL string, steamCMDDir string, extractFunc ExtractorFunc) error {
 	// Validate download URL
 	if err := validateURL(downloadURL); err != nil {
@@ -71,32 +69,15 @@ func downloadAndExtractSteamCMD(downloadURL string, steamCMDDir string, extractF
 	}
 	logger.Install.Debug("✅ Validated download URL: " + downloadURL + "\n")
 
-	// Download SteamCMD with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creating HTTP request: %w", err)
-	}
-	logger.Install.Debug("✅ Created HTTP request for download.\n")
-
-	resp, err := http.DefaultClient.Do(req)
+	// The cached copy is used if there is one, SteamCMD updates itself on its first run anyway
+	cached, source, err := artifacts.Fetch(steamCMDArtifact(downloadURL))
 	if err != nil {
 		return fmt.Errorf("error downloading SteamCMD: %w", err)
 	}
-	defer resp.Body.Close()
-	logger.Install.Debug("✅ Successfully downloaded SteamCMD.\n")
-
-	// Check for successful HTTP response
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download SteamCMD: HTTP status %v", resp.Status)
-	}
-
-	logger.Install.Debug("✅ Received HTTP status: " + resp.Status + "\n")
+	logger.Install.Debug("✅ Fetched SteamCMD from " + string(source) + ".\n")
 
 	// Read the downloaded content into memory
-	content, err := io.ReadAll(resp.Body)
+	content, err := os.ReadFile(cached)
 	if err != nil {
 		return fmt.Errorf("error reading SteamCMD content: %w", err)
 	}
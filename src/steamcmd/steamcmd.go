@@ -1,6 +1,8 @@
 package steamcmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -11,14 +13,15 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/artifacts"
 )
 
 var steamMu sync.Mutex
-var isUpdatingMu sync.Mutex
 
 // ExtractorFunc is a type that represents a function for extracting archives.
 // It takes an io.ReaderAt, the size of the content, and the destination directory.
@@ -32,6 +35,18 @@ const (
 	SteamCMDWindowsDir = "C:\\SteamCMD"
 )
 
+// steamCMDArtifact is the SteamCMD bootstrapper, Valve serves new builds under the same URL
+func steamCMDArtifact(downloadURL string) artifacts.Artifact {
+	artifact := artifacts.FromURL(downloadURL)
+	artifact.Mutable = true
+	return artifact
+}
+
+// SteamCMDArtifacts lists the SteamCMD downloads of every platform, for populating the artifact cache
+func SteamCMDArtifacts() []artifacts.Artifact {
+	return []artifacts.Artifact{steamCMDArtifact(SteamCMDLinuxURL), steamCMDArtifact(SteamCMDWindowsURL)}
+}
+
 // isIPv6Enabled returns a heuristic whether the system has non-loopback IPv6 addresses configured.
 // Used for diagnostic warning on SteamCMD exit 2 (0x2).
 func isIPv6Enabled() bool {
@@ -65,17 +80,40 @@ func isIPv6Enabled() bool {
 
 // InstallAndRunSteamCMD installs and runs SteamCMD based on the platform (Windows/Linux).
 // It returns the exit status of the SteamCMD execution and any error encountered.
+// The run is a job in the SteamCMD lane and waits for SteamCMD jobs ahead of it, StartGameUpdate does not wait.
 func InstallAndRunSteamCMD() (int, error) {
-	if isUpdatingMu.TryLock() {
-		// Successfully acquired the lock; we are not updating currently
-		logger.Core.Debug("🔄 Locking isUpdatingMu for SteamCMD Update run...")
-	} else {
-		// already updating, return
-		logger.Core.Warn("🔄 isUpdatingMu is currently locked, cannot update server using SteamCMD right now...")
-		return -1, fmt.Errorf("already updating")
+	return InstallAndRunSteamCMDContext(context.Background())
+}
+
+// InstallAndRunSteamCMDContext is InstallAndRunSteamCMD for callers that run in a job, cancelling ctx cancels the SteamCMD job
+func InstallAndRunSteamCMDContext(ctx context.Context) (int, error) {
+	queued := StartGameUpdate()
+	stop := context.AfterFunc(ctx, func() { jobs.Cancel(queued.ID) })
+	defer stop()
+	job, _ := jobs.Wait(queued.ID)
+	update, ok := GetUpdateJob(job.ID)
+	if !ok {
+		update.ExitCode = -1 // cancelled while queued
 	}
-	defer isUpdatingMu.Unlock()
-	defer logger.Core.Debug("🔄 Unlocking isUpdatingMu after SteamCMD Update run...")
+	if job.State != jobs.StateSucceeded {
+		return update.ExitCode, errors.New(job.Error)
+	}
+	return update.ExitCode, nil
+}
+
+// StartGameUpdate queues a game server update and returns its job right away
+func StartGameUpdate() jobs.Job {
+	return jobs.Submit("steamcmd-update", jobs.LaneSteamCMD, "Update the game server with SteamCMD", func(ctx context.Context, handle *jobs.Job) (any, error) {
+		job := newUpdateJob(ctx, handle)
+		_, err := job.run()
+		result, _ := GetUpdateJob(job.ID)
+		return result, err
+	})
+}
+
+// run updates the game server
+func (job *UpdateJob) run() (exitCode int, err error) {
+	defer func() { job.finish(exitCode, err) }()
 
 	if gamemgr.InternalIsServerRunning() {
 		logger.Core.Warn("Server is running, stopping server first...")
@@ -88,18 +126,22 @@ func InstallAndRunSteamCMD() (int, error) {
 
 	switch runtime.GOOS {
 	case "windows":
-		return installSteamCMDWindows()
+		exitCode, err = installSteamCMDWindows(job)
 	case "linux":
-		return installSteamCMDLinux()
+		exitCode, err = installSteamCMDLinux(job)
 	default:
 		err := fmt.Errorf("SteamCMD installation is not supported on this OS")
 		logger.Install.Error("❌ " + err.Error() + "\n")
 		return -1, err
 	}
+	if err == nil {
+		markGameUpdated()
+	}
+	return exitCode, err
 }
 
 // runSteamCMD runs the SteamCMD command to update the game and returns its exit status and any error.
-func runSteamCMD(steamCMDDir string) (int, error) {
+func runSteamCMD(steamCMDDir string, job *UpdateJob) (int, error) {
 	if steamMu.TryLock() {
 		// Successfully acquired the lock; no other func holds it
 		logger.Core.Debug("🔄 Locking SteamMu for SteamCMD execution...")
@@ -127,11 +169,11 @@ func runSteamCMD(steamCMDDir string) (int, error) {
 	}
 
 	// Build the initial SteamCMD command
-	cmd := buildSteamCMDCommand(steamCMDDir, currentDir)
+	cmd := buildSteamCMDCommand(job.ctx, steamCMDDir, currentDir)
 
-	// Set output to stdout and stderr
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	// Set output to stdout and stderr, parsing the progress on the way
+	cmd.Stdout = &progressWriter{job: job, out: os.Stdout}
+	cmd.Stderr = &progressWriter{job: job, out: os.Stderr}
 
 	// Apply Linux-specific HOME environment variable override
 	if runtime.GOOS == "linux" {
@@ -177,12 +219,12 @@ func runSteamCMD(steamCMDDir string) (int, error) {
 			exitCode = exitErr.ExitCode()
 			logger.Install.Error("❌ SteamCMD exited unsuccessfully: " + runErr.Error() + "\n")
 
-			if exitCode == 8 && attempt == 1 {
+			if exitCode == 8 && attempt == 1 && job.ctx.Err() == nil {
 				logger.Install.Warn("⚠️ SteamCMD failed with exit status 8 on first attempt. Retrying once...")
 				// Rebuild a fresh command for the retry
-				cmd = buildSteamCMDCommand(steamCMDDir, currentDir)
-				cmd.Stdout = os.Stdout
-				cmd.Stderr = os.Stderr
+				cmd = buildSteamCMDCommand(job.ctx, steamCMDDir, currentDir)
+				cmd.Stdout = &progressWriter{job: job, out: os.Stdout}
+				cmd.Stderr = &progressWriter{job: job, out: os.Stderr}
 
 				// Re-apply Linux env modifications
 				if runtime.GOOS == "linux" {
@@ -238,13 +280,13 @@ func runSteamCMD(steamCMDDir string) (int, error) {
 }
 
 // buildSteamCMDCommand constructs the SteamCMD command based on the OS.
-func buildSteamCMDCommand(steamCMDDir, currentDir string) *exec.Cmd {
+func buildSteamCMDCommand(ctx context.Context, steamCMDDir, currentDir string) *exec.Cmd {
 	//print the config.GameBranch and config.GameServerAppID
 	logger.Install.Info("🔍 Game Branch: " + config.GetGameBranch())
 	logger.Install.Debug("🔍 Game Server App ID: " + config.GetGameServerAppID())
 
 	if runtime.GOOS == "windows" {
-		return exec.Command(filepath.Join(steamCMDDir, "steamcmd.exe"), "+force_install_dir", currentDir, "+login", "anonymous", "+app_update", config.GetGameServerAppID(), "-beta", config.GetGameBranch(), "validate", "+quit")
+		return exec.CommandContext(ctx, filepath.Join(steamCMDDir, "steamcmd.exe"), "+force_install_dir", currentDir, "+login", "anonymous", "+app_update", config.GetGameServerAppID(), "-beta", config.GetGameBranch(), "validate", "+quit")
 	}
-	return exec.Command(filepath.Join(steamCMDDir, "steamcmd.sh"), "+force_install_dir", currentDir, "+login", "anonymous", "+app_update", config.GetGameServerAppID(), "-beta", config.GetGameBranch(), "validate", "+quit")
+	return exec.CommandContext(ctx, filepath.Join(steamCMDDir, "steamcmd.sh"), "+force_install_dir", currentDir, "+login", "anonymous", "+app_update", config.GetGameServerAppID(), "-beta", config.GetGameBranch(), "validate", "+quit")
 }
@@ -0,0 +1,154 @@
+package steamcmd
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
+)
+
+// Update job states
+const (
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// keep the last few jobs so their result can be fetched after they finished
+const maxUpdateJobs = 20
+
+// UpdateJob is the SteamCMD side of a game update job, from the web UI, Discord, the CLI or an automatic update.
+// It shares the ID of its job in the job manager and ends up as the job's result.
+type UpdateJob struct {
+	ID         string    `json:"id"`
+	Status     string    `json:"status"`
+	Branch     string    `json:"branch"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt,omitzero"`
+	Duration   string    `json:"duration,omitempty"`
+	OldBuildID string    `json:"oldBuildId,omitempty"`
+	NewBuildID string    `json:"newBuildId,omitempty"`
+	ExitCode   int       `json:"exitCode"`
+	ErrorClass string    `json:"errorClass,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Progress   Progress  `json:"progress"`
+
+	outputClass string // error class seen in the SteamCMD output, used if the run fails
+	ctx         context.Context
+	handle      *jobs.Job
+}
+
+var (
+	updateJobs   []*UpdateJob // oldest first
+	updateJobsMu sync.Mutex
+)
+
+// newUpdateJob registers a running job
+func newUpdateJob(ctx context.Context, handle *jobs.Job) *UpdateJob {
+	oldBuildID, err := InstalledBuildID()
+	if err != nil {
+		oldBuildID = config.GetCurrentBranchBuildID()
+	}
+	job := &UpdateJob{
+		ID:         handle.ID,
+		Status:     JobRunning,
+		Branch:     config.GetGameBranch(),
+		StartedAt:  time.Now(),
+		OldBuildID: oldBuildID,
+		Progress:   Progress{State: "starting"},
+		ctx:        ctx,
+		handle:     handle,
+	}
+	job.Progress.JobID = job.ID
+
+	updateJobsMu.Lock()
+	defer updateJobsMu.Unlock()
+	updateJobs = append(updateJobs, job)
+	if excess := len(updateJobs) - maxUpdateJobs; excess > 0 {
+		updateJobs = slices.Delete(updateJobs, 0, excess)
+	}
+	return job
+}
+
+// GetUpdateJob returns a copy of the job with the given ID, or of the latest job if id is empty
+func GetUpdateJob(id string) (UpdateJob, bool) {
+	updateJobsMu.Lock()
+	defer updateJobsMu.Unlock()
+	for _, job := range slices.Backward(updateJobs) {
+		if id == "" || job.ID == id {
+			return *job, true
+		}
+	}
+	return UpdateJob{}, false
+}
+
+// handleOutputLine updates the job from a line of SteamCMD output
+func (job *UpdateJob) handleOutputLine(line string) {
+	progress, isProgress := parseProgressLine(line)
+	class := classifyErrorLine(line)
+
+	updateJobsMu.Lock()
+	if class != "" {
+		job.outputClass = class
+	}
+	changed := isProgress && (progress.State != job.Progress.State || progress.Percent != job.Progress.Percent)
+	if changed {
+		progress.JobID = job.ID
+		job.Progress = progress
+	}
+	updateJobsMu.Unlock()
+
+	if changed {
+		broadcastProgress(progress)
+		job.handle.SetProgress(progress.Percent, "SteamCMD: "+progress.State)
+	} else if !isProgress {
+		job.handle.Log(line)
+	}
+}
+
+// finish records the outcome of the run
+func (job *UpdateJob) finish(exitCode int, err error) {
+	updateJobsMu.Lock()
+	job.FinishedAt = time.Now()
+	job.Duration = job.FinishedAt.Sub(job.StartedAt).Round(time.Second).String()
+	job.ExitCode = exitCode
+	if err == nil {
+		job.Status = JobSucceeded
+		if job.NewBuildID, err = InstalledBuildID(); err != nil {
+			job.NewBuildID = config.GetCurrentBranchBuildID()
+		}
+		job.Progress.State, job.Progress.Percent = "done", 100
+	} else {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		job.ErrorClass = classifyFailure(exitCode, job.outputClass)
+		job.Progress.State = "failed"
+	}
+	progress, snapshot := job.Progress, *job
+	updateJobsMu.Unlock()
+
+	if snapshot.Status == JobSucceeded {
+		recordJobInstall(snapshot)
+	}
+	broadcastProgress(progress)
+}
+
+// classifyFailure picks the error class of a failed run, what SteamCMD printed explains more than its exit code
+func classifyFailure(exitCode int, outputClass string) string {
+	switch {
+	case outputClass != "":
+		return outputClass
+	case exitCode == 8:
+		return ErrorClassExit8
+	default:
+		return ErrorClassUnknown
+	}
+}
+
+// IsUpdating reports whether SteamCMD is updating the game server right now
+func IsUpdating() bool {
+	return jobs.Busy(jobs.LaneSteamCMD)
+}
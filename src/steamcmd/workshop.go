@@ -2,6 +2,7 @@ package steamcmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -11,14 +12,15 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
 )
 
-// DownloadWorkshopItems downloads all installed workshop mods using SteamCMD
+// UpdateWorkshopItems downloads all installed workshop mods that are not pinned using SteamCMD
 func UpdateWorkshopItems() ([]string, error) {
 	var logs []string
-	workshopHandles := modding.GetModWorkshopHandles()
+	workshopHandles := modding.GetUpdatableWorkshopHandles()
 	if len(workshopHandles) == 0 {
 		logger.Install.Debug("ℹ️  No workshop items to download")
 		logs = append(logs, "No workshop items to download")
@@ -32,7 +34,38 @@ func UpdateWorkshopItems() ([]string, error) {
 }
 
 func DownloadWorkshopItems(workshopHandles []string) ([]string, error) {
-	var logs []string
+	return downloadWorkshopItems(context.Background(), workshopHandles, nil)
+}
+
+// StartWorkshopUpdate queues a job that downloads the given workshop items, or all installed ones that are not pinned if none are given.
+// The job's result are the download logs.
+func StartWorkshopUpdate(workshopHandles []string) jobs.Job {
+	description := "Update all workshop mods"
+	if len(workshopHandles) > 0 {
+		description = "Update workshop mod " + strings.Join(workshopHandles, ", ")
+	}
+	return jobs.Submit("workshop-update", jobs.LaneSteamCMD, description, func(ctx context.Context, job *jobs.Job) (any, error) {
+		handles := workshopHandles
+		if len(handles) == 0 {
+			handles = modding.GetUpdatableWorkshopHandles()
+		}
+		if len(handles) == 0 {
+			job.Log("No workshop items to download")
+			return []string{"No workshop items to download"}, nil
+		}
+		return downloadWorkshopItems(ctx, handles, job)
+	})
+}
+
+// downloadWorkshopItems downloads the items one by one, reporting to job if it is not nil
+func downloadWorkshopItems(ctx context.Context, workshopHandles []string, job *jobs.Job) (logs []string, err error) {
+	if job != nil {
+		defer func() {
+			for _, line := range logs {
+				job.Log(line)
+			}
+		}()
+	}
 	validatedHandles := make([]string, 0, len(workshopHandles))
 	for _, handle := range workshopHandles {
 		workshopID, err := strconv.ParseUint(handle, 10, 64)
@@ -76,10 +109,17 @@ func DownloadWorkshopItems(workshopHandles []string) ([]string, error) {
 
 	// Download each workshop item
 	for i, appID := range workshopHandles {
+		if ctx.Err() != nil {
+			logs = append(logs, "Workshop download cancelled")
+			return logs, ctx.Err()
+		}
 		logger.Install.Infof("📦 Downloading workshop item %d/%d: %s", i+1, len(workshopHandles), appID)
+		if job != nil {
+			job.SetProgress(float64(i)*100/float64(len(workshopHandles)), fmt.Sprintf("Downloading workshop item %d/%d: %s", i+1, len(workshopHandles), appID))
+		}
 
 		// Build SteamCMD command
-		cmd := exec.Command(
+		cmd := exec.CommandContext(ctx,
 			filepath.Join(steamcmddir, executable),
 			"+force_install_dir", "../",
 			"+login", "anonymous",
@@ -34,6 +34,7 @@ func ServeTwoBoxFormTemplate(w http.ResponseWriter, r *http.Request) {
 	type TemplateData struct {
 		IsFirstTimeSetup   bool
 		Path               string
+		BasePath           string // URL prefix behind a reverse proxy, see proxy.go
 		Title              string
 		HeaderTitle        string
 		StepMessage        string
@@ -57,6 +58,14 @@ func ServeTwoBoxFormTemplate(w http.ResponseWriter, r *http.Request) {
 		PrimaryPlaceholderText   string
 		SecondaryPlaceholderText string
 		Steps                    []Step
+		// second login step and /twofactor page
+		TwoFactorLabel                string
+		TwoFactorPlaceholderText      string
+		TwoFactorEnabledMessage       string
+		TwoFactorDisableButtonText    string
+		TwoFactorSecretLabel          string
+		TwoFactorRecoveryCodesMessage string
+		OIDCLoginText                 string // login page only, empty if OIDC login is disabled
 	}
 
 	twoboxformAssetsFS, err := fs.Sub(config.GetV1UIFS(), "UIMod/onboard_bundled/twoboxform")
@@ -319,6 +328,7 @@ func ServeTwoBoxFormTemplate(w http.ResponseWriter, r *http.Request) {
 	data := TemplateData{
 		IsFirstTimeSetup:         config.GetIsFirstTimeSetup(),
 		Path:                     path,
+		BasePath:                 basePath(),
 		Step:                     stepID,
 		FooterText:               localization.GetString("UIText_FooterText"),
 		FooterTextInfo:           localization.GetString("UIText_FooterTextInfo"),
@@ -391,6 +401,21 @@ func ServeTwoBoxFormTemplate(w http.ResponseWriter, r *http.Request) {
 		data.Mode = "changeuser"
 		data.ShowExtraButtons = false
 
+	case path == "/twofactor":
+		data.Title = localization.GetString("UIText_TwoFactor_Title")
+		data.HeaderTitle = localization.GetString("UIText_TwoFactor_HeaderTitle")
+		data.StepMessage = localization.GetString("UIText_TwoFactor_StepMessage")
+		data.PrimaryLabel = localization.GetString("UIText_TwoFactor_PrimaryLabel")
+		data.PrimaryPlaceholderText = localization.GetString("UIText_TwoFactor_PrimaryPlaceholder")
+		data.SecondaryLabelType = "hidden"
+		data.SubmitButtonText = localization.GetString("UIText_TwoFactor_SubmitButton")
+		data.TwoFactorEnabledMessage = localization.GetString("UIText_TwoFactor_EnabledMessage")
+		data.TwoFactorDisableButtonText = localization.GetString("UIText_TwoFactor_DisableButton")
+		data.TwoFactorSecretLabel = localization.GetString("UIText_TwoFactor_SecretLabel")
+		data.TwoFactorRecoveryCodesMessage = localization.GetString("UIText_TwoFactor_RecoveryCodesMessage")
+		data.Mode = "twofactor"
+		data.ShowExtraButtons = false
+
 	default:
 		data.Title = localization.GetString("UIText_Login_Title")
 		data.HeaderTitle = localization.GetString("UIText_Login_HeaderTitle") + config.GetSSUIIdentifier()
@@ -400,6 +425,11 @@ func ServeTwoBoxFormTemplate(w http.ResponseWriter, r *http.Request) {
 		data.SecondaryPlaceholderText = localization.GetString("UIText_Login_SecondaryPlaceholder")
 		data.SecondaryLabelType = "password"
 		data.SubmitButtonText = localization.GetString("UIText_Login_SubmitButton")
+		data.TwoFactorLabel = localization.GetString("UIText_TwoFactor_LoginLabel")
+		data.TwoFactorPlaceholderText = localization.GetString("UIText_TwoFactor_LoginPlaceholder")
+		if config.GetOIDCEnabled() {
+			data.OIDCLoginText = localization.GetString("UIText_Login_OIDCButton")
+		}
 		data.Mode = "login"
 		data.Step = ""
 		data.ShowExtraButtons = false
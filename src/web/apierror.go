@@ -0,0 +1,218 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+/*
+Consistent JSON errors for /api/v2
+- Every error response is an APIError: {"status": "error", "code": "not_found", "message": "...", "error": "..."}
+  "error" repeats the message for clients written against the older {"error": "..."} responses
+- APIMiddleware rewrites whatever error a handler or AuthMiddleware wrote (http.Error text, {"error": ...}, {"status": "error", ...}) into an APIError
+- APIRouteMiddleware runs inside AuthMiddleware, so only authenticated callers learn which API paths exist. It enforces the methods
+  documented in apiRoutes (see openapi.go), answers OPTIONS and returns 404 for unknown API paths
+*/
+
+// Error codes of APIError, derived from the HTTP status unless a handler sets one
+const (
+	ErrCodeBadRequest         = "bad_request"
+	ErrCodeUnauthorized       = "unauthorized"
+	ErrCodeForbidden          = "forbidden"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeMethodNotAllowed   = "method_not_allowed"
+	ErrCodeConflict           = "conflict"
+	ErrCodePayloadTooLarge    = "payload_too_large"
+	ErrCodeTooManyRequests    = "too_many_requests"
+	ErrCodeInternal           = "internal_error"
+	ErrCodeServiceUnavailable = "service_unavailable"
+)
+
+// APIError is the error envelope of /api/v2
+type APIError struct {
+	Status  string         `json:"status"` // always "error"
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Error   string         `json:"error"`             // same as Message, for older clients
+	Details map[string]any `json:"details,omitempty"` // further fields of the handler's error response, e.g. SteamCMD logs
+}
+
+// errorCodeForStatus returns the default error code of an HTTP status
+func errorCodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrCodeForbidden
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrCodeMethodNotAllowed
+	case http.StatusConflict:
+		return ErrCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return ErrCodePayloadTooLarge
+	case http.StatusTooManyRequests:
+		return ErrCodeTooManyRequests
+	case http.StatusServiceUnavailable:
+		return ErrCodeServiceUnavailable
+	}
+	if status < 500 {
+		return ErrCodeBadRequest
+	}
+	return ErrCodeInternal
+}
+
+// writeAPIError writes an APIError, code defaults to the one of the status
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	if code == "" {
+		code = errorCodeForStatus(status)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(APIError{Status: "error", Code: code, Message: message, Error: message})
+}
+
+// isAPIRequest reports whether the request is below /api/v2/, the public OpenAPI document excluded
+func isAPIRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/api/v2/") && r.URL.Path != "/api/v2/openapi.json"
+}
+
+// APIMiddleware turns the error responses of requests below /api/v2/ into an APIError
+func APIMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAPIRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		aw := &apiErrorWriter{ResponseWriter: w}
+		next.ServeHTTP(aw, r)
+		aw.finish()
+	})
+}
+
+// APIRouteMiddleware answers requests below /api/v2/ for unknown paths and undocumented methods
+func APIRouteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAPIRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		route, ok := findAPIRoute(r.URL.Path)
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, "", "Unknown API endpoint "+r.URL.Path)
+			return
+		}
+		allowed := route.allowedMethods()
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !slices.Contains(allowed, r.Method) {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeAPIError(w, http.StatusMethodNotAllowed, "", "Method "+r.Method+" not allowed, use "+strings.Join(allowed, " or "))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiErrorWriter passes successful responses through and buffers error responses to rewrite them into an APIError
+type apiErrorWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *apiErrorWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	if status < 400 {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *apiErrorWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.status >= 400 {
+		return w.body.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush keeps streaming responses streaming, error responses are written by finish
+func (w *apiErrorWriter) Flush() {
+	if w.status >= 400 {
+		return
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the WebSocket API take over the connection
+func (w *apiErrorWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+func (w *apiErrorWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *apiErrorWriter) finish() {
+	if w.status < 400 {
+		return
+	}
+	apiErr := apiErrorFromBody(w.status, w.body.Bytes())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Del("Content-Length")
+	w.Header().Del("X-Content-Type-Options") // set by http.Error for its text/plain body
+	w.ResponseWriter.WriteHeader(w.status)
+	_ = json.NewEncoder(w.ResponseWriter).Encode(apiErr)
+}
+
+// apiErrorFromBody turns the error response of a handler into an APIError. JSON bodies keep their code and extra fields.
+func apiErrorFromBody(status int, body []byte) APIError {
+	apiErr := APIError{Status: "error", Code: errorCodeForStatus(status)}
+	var fields map[string]any
+	if json.Unmarshal(body, &fields) == nil && fields != nil {
+		for _, key := range []string{"message", "error"} {
+			if text, ok := fields[key].(string); ok && text != "" && apiErr.Message == "" {
+				apiErr.Message = text
+			}
+		}
+		if code, ok := fields["code"].(string); ok && code != "" {
+			apiErr.Code = code
+		}
+		for _, key := range []string{"status", "code", "message", "error", "success", "statuscode"} {
+			delete(fields, key)
+		}
+		if details, ok := fields["details"].(map[string]any); ok && len(fields) == 1 {
+			fields = details
+		}
+		if len(fields) > 0 {
+			apiErr.Details = fields
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	apiErr.Error = apiErr.Message
+	return apiErr
+}
@@ -0,0 +1,114 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+func TestAPIMiddlewareErrors(t *testing.T) {
+	handler := APIMiddleware(APIRouteMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/backups":
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+		case "/api/v2/steamcmd/updatemods":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]any{"success": false, "error": "steamcmd failed", "logs": []string{"line"}})
+		default:
+			w.Write([]byte(`{"status":"success"}`))
+		}
+	})))
+
+	tests := []struct {
+		method, path string
+		status       int
+		code         string
+		message      string
+	}{
+		{http.MethodGet, "/api/v2/nope", http.StatusNotFound, ErrCodeNotFound, "Unknown API endpoint /api/v2/nope"},
+		{http.MethodGet, "/api/v2/backups/restore", http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "Method GET not allowed, use POST"},
+		{http.MethodGet, "/api/v2/backups", http.StatusBadRequest, ErrCodeBadRequest, "Invalid limit parameter"},
+		{http.MethodPost, "/api/v2/steamcmd/updatemods", http.StatusInternalServerError, ErrCodeInternal, "steamcmd failed"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		var apiErr APIError
+		if err := json.Unmarshal(rec.Body.Bytes(), &apiErr); err != nil {
+			t.Fatalf("%s %s: expected a JSON error, got %q", tt.method, tt.path, rec.Body.String())
+		}
+		if rec.Code != tt.status || apiErr.Status != "error" || apiErr.Code != tt.code || apiErr.Message != tt.message || apiErr.Error != tt.message {
+			t.Errorf("%s %s: got %d %+v", tt.method, tt.path, rec.Code, apiErr)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s %s: Content-Type %q", tt.method, tt.path, ct)
+		}
+		if tt.path == "/api/v2/steamcmd/updatemods" && apiErr.Details["logs"] == nil {
+			t.Errorf("extra fields must be kept in details, got %+v", apiErr.Details)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/api/v2/discord/routing", nil))
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Allow") != "GET, POST" {
+		t.Errorf("OPTIONS: got %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/server/status", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"status":"success"}` {
+		t.Errorf("successful responses must pass through unchanged, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAPIRoutesDocumentedAndRegistered(t *testing.T) {
+	_, protectedMux := SetupRoutes() // panics on API routes missing in apiRoutes
+	for _, route := range apiRoutes {
+		if _, pattern := protectedMux.Handler(httptest.NewRequest(http.MethodGet, route.Path, nil)); pattern != route.Path {
+			t.Errorf("%s is documented but not registered (matched %q)", route.Path, pattern)
+		}
+	}
+
+	data, err := json.Marshal(openAPIDocument("/ssui"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Servers []struct{ URL string }    `json:"servers"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.1.0" || doc.Servers[0].URL != "/ssui" || len(doc.Paths) != len(apiRoutes) {
+		t.Errorf("unexpected document header %q %+v with %d paths", doc.OpenAPI, doc.Servers, len(doc.Paths))
+	}
+	if _, ok := doc.Paths["/api/v2/backups/restore"]["post"]; !ok {
+		t.Errorf("restore must be documented as POST, got %+v", doc.Paths["/api/v2/backups/restore"])
+	}
+}
+
+func TestAPIRoutesNeedAuthBeforeNotFound(t *testing.T) {
+	config.ConfigMu.Lock()
+	config.AuthEnabled = true
+	config.ConfigMu.Unlock()
+	t.Cleanup(func() {
+		config.ConfigMu.Lock()
+		config.AuthEnabled = false
+		config.ConfigMu.Unlock()
+	})
+	handler := APIMiddleware(AuthMiddleware(APIRouteMiddleware(http.NotFoundHandler())))
+
+	for _, path := range []string{"/api/v2/nope", "/api/v2/auth/check"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var apiErr APIError
+		if err := json.Unmarshal(rec.Body.Bytes(), &apiErr); err != nil || rec.Code != http.StatusUnauthorized || apiErr.Code != ErrCodeUnauthorized {
+			t.Errorf("%s without a token: got %d %q", path, rec.Code, rec.Body.String())
+		}
+	}
+}
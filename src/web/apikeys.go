@@ -0,0 +1,79 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+)
+
+type createAPIKeyRequest struct {
+	Name           string   `json:"name"`
+	Scopes         []string `json:"scopes"`
+	DurationMonths int      `json:"durationMonths"`
+}
+
+type revokeAPIKeyRequest struct {
+	ID string `json:"id"`
+}
+
+// APIKeysHandler lists API keys on GET and creates a named, scoped key on POST.
+// The key itself is only part of the POST response and cannot be retrieved later.
+func APIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(map[string]any{"keys": security.ListAPIKeys()})
+	case http.MethodPost:
+		var req createAPIKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - Invalid JSON"})
+			return
+		}
+		if req.DurationMonths == 0 {
+			req.DurationMonths = 1
+		}
+		key, apikey, err := security.CreateAPIKey(req.Name, req.Scopes, req.DurationMonths)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - " + err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{
+			"message": "APIKey registered successfully",
+			"apikey":  apikey,
+			"key":     key,
+			"expires": key.ExpiresAt.Format(time.RFC3339),
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method Not Allowed"})
+	}
+}
+
+// RevokeAPIKeyHandler revokes an API key by ID, it stops working immediately
+func RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method Not Allowed"})
+		return
+	}
+
+	var req revokeAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - id is required"})
+		return
+	}
+	if err := security.RevokeAPIKey(req.ID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - " + err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "APIKey revoked", "id": req.ID})
+}
@@ -0,0 +1,207 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+)
+
+const (
+	auditMaxBodyBytes  = 64 * 1024 // larger request bodies (uploads) are not copied into the audit log
+	auditMaxErrorBytes = 1024
+	auditDefaultLimit  = 200
+	auditMaxLimit      = 5000
+)
+
+// auditResponseWriter records the status code and the start of an error response for the audit log
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	errBody bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.status >= 400 && w.errBody.Len() < auditMaxErrorBytes {
+		w.errBody.Write(p[:min(len(p), auditMaxErrorBytes-w.errBody.Len())])
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the original writer, e.g. to flush
+func (w *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// auditedPaths change state, requests to them are audited whatever the method, e.g. an old client still starting the server with GET
+var auditedPaths = []string{"/start", "/stop"}
+
+// AuditMiddleware records every state-changing request (anything but GET, HEAD and OPTIONS, and every request to auditedPaths)
+// in the audit log. It runs inside AuthMiddleware, so the caller is known.
+func AuditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+		if readOnly && !slices.Contains(auditedPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entry := requestAuditEntry(r, r.Method+" "+r.URL.Path)
+		entry.Params = auditRequestParams(r)
+		rec := &auditResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if rec.status >= 400 {
+			entry.Result = audit.ResultError
+			entry.Error = auditErrorMessage(rec.status, rec.errBody.Bytes())
+		}
+		audit.Record(entry)
+	})
+}
+
+// requestAuditEntry returns an audit entry with the actor of a request filled in
+func requestAuditEntry(r *http.Request, action string) audit.Entry {
+	entry := audit.Entry{ActorType: audit.ActorWeb, Actor: "anonymous", IP: clientIP(r), Action: action}
+	if info, ok := requestTokenInfo(r); ok {
+		entry.Actor = info.Subject
+		if strings.HasPrefix(info.Subject, security.APIKeyPrefix) {
+			entry.ActorType = audit.ActorAPIKey
+		}
+	}
+	return entry
+}
+
+// auditRequestParams collects the query and, for small JSON and form bodies, the body of a request. The body is restored for the handler.
+func auditRequestParams(r *http.Request) map[string]any {
+	params := make(map[string]any)
+	for key, values := range r.URL.Query() {
+		params[key] = strings.Join(values, ",")
+	}
+	if r.Body == nil {
+		return params
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" && mediaType != "application/x-www-form-urlencoded" {
+		if r.ContentLength > 0 {
+			params["bodyBytes"] = r.ContentLength
+		}
+		return params
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, auditMaxBodyBytes+1))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil || len(body) == 0 {
+		return params
+	}
+	if len(body) > auditMaxBodyBytes {
+		params["body"] = "(too large to record)"
+		return params
+	}
+
+	if mediaType == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for key, values := range form {
+				params[key] = strings.Join(values, ",")
+			}
+		}
+		return params
+	}
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return params
+	}
+	if object, ok := decoded.(map[string]any); ok {
+		for key, value := range object {
+			params[key] = value
+		}
+	} else {
+		params["body"] = decoded
+	}
+	return params
+}
+
+// auditErrorMessage extracts the message of a JSON error response, falling back to the status text
+func auditErrorMessage(status int, body []byte) string {
+	var response struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &response) == nil {
+		if response.Error != "" {
+			return response.Error
+		}
+		if response.Message != "" {
+			return response.Message
+		}
+	}
+	if text := strings.TrimSpace(string(body)); text != "" && len(text) < 200 && !strings.Contains(text, "<") {
+		return text
+	}
+	return strconv.Itoa(status) + " " + http.StatusText(status)
+}
+
+// AuditLogHandler returns audit log entries, newest first.
+// Filters: actorType, actor, action (substring), result, since and until (RFC 3339), limit.
+func AuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"status":"error","message":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := audit.Filter{
+		ActorType: query.Get("actorType"),
+		Actor:     query.Get("actor"),
+		Action:    query.Get("action"),
+		Result:    query.Get("result"),
+		Limit:     auditDefaultLimit,
+	}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": name + " must be an RFC 3339 timestamp, e.g. 2025-01-31T18:00:00Z"})
+				return
+			}
+			*target = parsed
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": "limit must be a positive number"})
+			return
+		}
+		filter.Limit = min(limit, auditMaxLimit)
+	}
+
+	entries, err := audit.Query(filter)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "entries": entries})
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
+)
+
+func TestAuditMiddlewareRecordsStateChanges(t *testing.T) {
+	t.Chdir(t.TempDir())
+	handler := AuditMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/backups/restore" {
+			http.Error(w, "backup not found", http.StatusNotFound)
+		}
+	}))
+
+	requests := []struct{ method, path, body string }{
+		{http.MethodGet, "/api/v2/server/status", ""},
+		{http.MethodHead, "/api/v2/backups", ""},
+		{http.MethodOptions, "/api/v2/mods/sync", ""},
+		{http.MethodPost, "/api/v2/backups/restore", `{"index": 3}`},
+		{http.MethodGet, "/stop", ""},
+		{http.MethodPost, "/start", ""},
+	}
+	for _, req := range requests {
+		r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+		r.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	entries, err := audit.Query(audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	slices.Sort(actions)
+	want := []string{"GET /stop", "POST /api/v2/backups/restore", "POST /start"}
+	if !slices.Equal(actions, want) {
+		t.Fatalf("recorded %v, want %v", actions, want)
+	}
+	for _, entry := range entries {
+		if entry.Action == "POST /api/v2/backups/restore" && (entry.Result != audit.ResultError || entry.Params["index"] == nil) {
+			t.Errorf("the failed restore must be recorded with its params and error, got %+v", entry)
+		}
+	}
+}
@@ -217,6 +217,7 @@ func ServeConfigPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := ConfigTemplateData{
+		BasePath: basePath(),
 		// Config values
 		DiscordToken:                            config.GetDiscordToken(),
 		ControlChannelID:                        config.GetControlChannelID(),
@@ -482,6 +483,9 @@ func ServeConfigPage(w http.ResponseWriter, r *http.Request) {
 		IsStationeersLaunchPadAutoUpdatesEnabled: fmt.Sprintf("%v", config.GetIsStationeersLaunchPadAutoUpdatesEnabled()),
 		IsStationeersLaunchPadAutoUpdatesEnabledTrueSelected:  isStationeersLaunchPadAutoUpdatesEnabledTrueSelected,
 		IsStationeersLaunchPadAutoUpdatesEnabledFalseSelected: isStationeersLaunchPadAutoUpdatesEnabledFalseSelected,
+		SLPVersion:          config.GetSLPVersion(),
+		ArtifactCacheFolder: config.GetArtifactCacheFolder(),
+		ArtifactMirrorURL:   config.GetArtifactMirrorURL(),
 	}
 
 	err = tmpl.Execute(w, data)
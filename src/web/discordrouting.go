@@ -0,0 +1,38 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+// DiscordRoutingHandler returns the Discord notification routing rules on GET and replaces them on POST.
+// Rules take effect immediately, no backend reload is needed.
+func DiscordRoutingHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"rules":  config.GetDiscordRoutingRules(),
+		})
+	case http.MethodPost:
+		var rules []config.DiscordRoutingRule
+		if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+			http.Error(w, `{"status":"error","message":"Invalid request, expected a JSON array of routing rules"}`, http.StatusBadRequest)
+			return
+		}
+		if err := config.SetDiscordRoutingRules(rules); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": err.Error()})
+			return
+		}
+		logger.Web.Infof("Discord routing rules updated (%d rules)", len(rules))
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "Discord routing rules saved"})
+	default:
+		http.Error(w, `{"status":"error","message":"Method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
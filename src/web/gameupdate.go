@@ -0,0 +1,125 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gameupdatemgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
+)
+
+// GameInstallHistory lists the install history, the snapshots to roll back to and the branches to switch to
+type GameInstallHistory struct {
+	Status    string                   `json:"status"`
+	Branch    string                   `json:"branch"`
+	Branches  map[string]string        `json:"branches"` // branch name to latest build ID
+	History   []steamcmd.InstallRecord `json:"history"`
+	Snapshots []gameupdatemgr.Snapshot `json:"snapshots"`
+}
+
+// BranchSwitchRequest selects the branch to switch to
+type BranchSwitchRequest struct {
+	Branch string `json:"branch"`
+}
+
+// RollbackRequest selects the snapshot to restore, the latest if empty
+type RollbackRequest struct {
+	Snapshot string `json:"snapshot"`
+}
+
+// HandleGameUpdateStatus reports whether a game server update is available and what a scheduled automatic update waits for
+func HandleGameUpdateStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gameupdatemgr.GetStatus())
+}
+
+// HandleGameInstallHistory returns the install history and snapshots
+func HandleGameInstallHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	history := GameInstallHistory{
+		Status:    "success",
+		Branch:    gameupdatemgr.GetStatus().Branch,
+		Branches:  steamcmd.KnownBranches(),
+		History:   steamcmd.GetInstallHistory(),
+		Snapshots: gameupdatemgr.ListSnapshots(),
+	}
+	if history.History == nil {
+		history.History = []steamcmd.InstallRecord{}
+	}
+	if history.Snapshots == nil {
+		history.Snapshots = []gameupdatemgr.Snapshot{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// HandleSwitchGameBranch queues switching the game branch, the outcome is reported as an event
+func HandleSwitchGameBranch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req BranchSwitchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	branch := strings.ToLower(strings.TrimSpace(req.Branch))
+	if err := gameupdatemgr.CheckBranch(branch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// snapshotting and SteamCMD take minutes
+	job := jobs.Submit("game-branch-switch", jobs.LaneInstall, "Switch the game branch to "+branch, func(ctx context.Context, job *jobs.Job) (any, error) {
+		return nil, gameupdatemgr.SwitchBranch(ctx, branch)
+	})
+	writeJobAccepted(w, job, "Switching to branch "+branch+", the server is snapshotted and updated in the background")
+}
+
+// HandleRollbackGameInstall queues restoring a snapshot, the outcome is reported as an event
+func HandleRollbackGameInstall(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req RollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	snapshots := gameupdatemgr.ListSnapshots()
+	if len(snapshots) == 0 {
+		http.Error(w, "There is no snapshot to roll back to", http.StatusNotFound)
+		return
+	}
+	target := snapshots[0]
+	if req.Snapshot != "" {
+		found := false
+		for _, snapshot := range snapshots {
+			if snapshot.ID == req.Snapshot {
+				target, found = snapshot, true
+			}
+		}
+		if !found {
+			http.Error(w, "Snapshot "+req.Snapshot+" not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	job := jobs.Submit("game-rollback", jobs.LaneInstall, "Roll back to snapshot "+target.ID, func(ctx context.Context, job *jobs.Job) (any, error) {
+		return nil, gameupdatemgr.Rollback(ctx, target.ID)
+	})
+	writeJobAccepted(w, job, "Rolling back to branch "+target.Branch+" build "+target.BuildID+" from snapshot "+target.ID)
+}
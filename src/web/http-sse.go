@@ -36,6 +36,11 @@ func GetBackendLogOutput(w http.ResponseWriter, r *http.Request) {
 	StartBackendLogStream()(w, r)
 }
 
+// handler for the /steamcmd/progress endpoint, SteamCMD progress as JSON
+func GetSteamCMDProgressOutput(w http.ResponseWriter, r *http.Request) {
+	StartSteamCMDProgressStream()(w, r)
+}
+
 // StartConsoleStream creates an HTTP handler for console log SSE streaming
 func StartConsoleStream() http.HandlerFunc {
 	return ssestream.ConsoleStreamManager.CreateStreamHandler("Console")
@@ -65,3 +70,7 @@ func StartErrorLogStream() http.HandlerFunc {
 func StartBackendLogStream() http.HandlerFunc {
 	return ssestream.BackendLogStreamManager.CreateStreamHandler("Full Backend Log")
 }
+
+func StartSteamCMDProgressStream() http.HandlerFunc {
+	return ssestream.SteamCMDStreamManager.CreateStreamHandler("SteamCMD Progress")
+}
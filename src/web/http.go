@@ -18,6 +18,18 @@ import (
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
 )
 
+// postOnly rejects everything but POST, for legacy routes that are not in apiRoutes and change state
+func postOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		handler(w, r)
+	}
+}
+
 // StartServer HTTP handler
 func StartServer(w http.ResponseWriter, r *http.Request) {
 	logger.Web.Debug("Received start request from API")
@@ -154,28 +166,31 @@ func HandleIsSSCMEnabled(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-// run SteamCMD from API, but only allow once every 5 minutes to "kinda" prevent concurrent executions although that woluldnt hurn.
-// If the user has a 5mbit connection, I cannot help them anyways.
+// run SteamCMD from API, a request while SteamCMD is busy is queued behind the running job
 func HandleRunSteamCMD(w http.ResponseWriter, r *http.Request) {
 
-	// Only allow GET requests
-	if r.Method != http.MethodGet {
-		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+	// Only allow POST requests, running SteamCMD changes the server files
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	logger.Core.Info("Running SteamCMD")
-	_, err := steamcmd.InstallAndRunSteamCMD()
-
-	// Update last execution time
+	// SteamCMD takes minutes, progress is on /steamcmd/progress and the outcome on /api/v2/jobs and /api/v2/steamcmd/job
+	writeJobAccepted(w, steamcmd.StartGameUpdate(), "SteamCMD started, updating the gameserver files...")
+}
 
-	// Success: return 202 Accepted and JSON
-	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", "application/json")
-	if err == nil {
-		json.NewEncoder(w).Encode(map[string]string{"statuscode": "202", "status": "Success", "message": "SteamCMD ran successfully, gameserver files are up-to-date!"})
+// HandleSteamCMDJob returns an update job, the latest one if no id is given
+func HandleSteamCMDJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	job, ok := steamcmd.GetUpdateJob(r.URL.Query().Get("id"))
+	if !ok {
+		http.Error(w, "Update job not found", http.StatusNotFound)
 		return
 	}
-	// Failure: return 202 Accepted and JSON with the error message
-	json.NewEncoder(w).Encode(map[string]string{"statuscode": "202", "status": "Failed", "message": "SteamCMD ran unsuccessfully:" + err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
 }
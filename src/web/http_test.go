@@ -0,0 +1,18 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLegacyServerControlNeedsPost(t *testing.T) {
+	_, protectedMux := SetupRoutes()
+	for _, path := range []string{"/start", "/stop"} {
+		rec := httptest.NewRecorder()
+		protectedMux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+			t.Errorf("GET %s: got %d, the server must not be started or stopped", path, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
+)
+
+// JobList is the response of /api/v2/jobs without an id
+type JobList struct {
+	Jobs []jobs.Job `json:"jobs"`
+}
+
+// CancelJobRequest selects the job to cancel
+type CancelJobRequest struct {
+	ID string `json:"id"`
+}
+
+// writeJobAccepted answers a request that submitted a job with 202, queued or not
+func writeJobAccepted(w http.ResponseWriter, job jobs.Job, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(jobs.NewAccepted(job, message))
+}
+
+// HandleJobs lists all background jobs, or returns one job with its logs if an id is given
+func HandleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if id := r.URL.Query().Get("id"); id != "" {
+		job, ok := jobs.Get(id)
+		if !ok {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(job)
+		return
+	}
+	json.NewEncoder(w).Encode(JobList{Jobs: jobs.List()})
+}
+
+// HandleCancelJob cancels a queued or running job
+func HandleCancelJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req CancelJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == "" {
+		http.Error(w, "Invalid request body, id is required", http.StatusBadRequest)
+		return
+	}
+	if err := jobs.Cancel(req.ID); err != nil {
+		status := http.StatusConflict
+		if errors.Is(err, jobs.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusMessage{Status: "success", Message: "Job " + req.ID + " cancelled"})
+}
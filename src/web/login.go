@@ -2,17 +2,21 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config/configchanger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/loader"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/discordbot"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
-	"github.com/google/uuid"
 )
 
 // LoginHandler issues a JWT cookie
@@ -26,6 +30,19 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Throttle brute-force attempts before spending time on bcrypt
+	ip := clientIP(r)
+	attempt, wait := security.BeginLoginAttempt(ip, creds.Username)
+	if wait > 0 {
+		seconds := int(wait.Round(time.Second).Seconds())
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Too Many Requests - try again in %d seconds", max(seconds, 1))})
+		return
+	}
+	defer attempt.End()
+
 	// Check credentials using security package
 	valid, err := security.ValidateCredentials(creds)
 	if err != nil {
@@ -35,14 +52,78 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !valid {
+		logger.Security.Warnf("Failed login for user %q from %s", creds.Username, ip)
+		for _, lockout := range attempt.Failed(creds.Username) {
+			alertLoginLockout(lockout)
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Unauthorized - Invalid credentials"})
 		return
 	}
 
-	// Generate JWT
-	tokenString, err := security.GenerateJWT(creds.Username)
+	// Users with 2FA get a short-lived challenge instead of a session, completed in LoginTwoFactorHandler
+	if security.IsTwoFactorEnabled(creds.Username) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]any{
+			"twoFactorRequired": true,
+			"challenge":         security.NewLoginChallenge(security.PendingLogin{Username: creds.Username, Method: "password"}, ip),
+			"message":           "Enter the code from your authenticator app or a recovery code",
+		})
+		return
+	}
+	attempt.Succeeded(creds.Username)
+	startSession(w, r, security.PendingLogin{Username: creds.Username, Method: "password"}, ip)
+}
+
+// LoginTwoFactorHandler is the second login step for users with 2FA, it exchanges a login challenge and a code for a session
+func LoginTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Challenge string `json:"challenge"`
+		Code      string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - Invalid JSON"})
+		return
+	}
+
+	ip := clientIP(r)
+	attempt, wait := security.BeginLoginAttempt(ip, "")
+	if wait > 0 {
+		seconds := max(int(wait.Round(time.Second).Seconds()), 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Too Many Requests - try again in %d seconds", seconds)})
+		return
+	}
+	defer attempt.End()
+
+	login, err := security.CompleteLoginChallenge(req.Challenge, ip, req.Code)
+	username := login.Username
+	if err != nil {
+		if username != "" {
+			logger.Security.Warnf("Failed two-factor login for user %q from %s", username, ip)
+			for _, lockout := range attempt.Failed(username) {
+				alertLoginLockout(lockout)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Unauthorized - " + err.Error()})
+		return
+	}
+	attempt.Succeeded(username)
+	startSession(w, r, login, ip)
+}
+
+// startSession creates a server-side session for a user who passed all login steps and sets the AuthToken cookie
+func startSession(w http.ResponseWriter, r *http.Request, login security.PendingLogin, ip string) {
+	username := login.Username
+	session, tokenString, err := security.CreateSession(username, login.Role, ip, r.UserAgent())
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -50,23 +131,28 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set cookie
+	setAuthCookie(w, r, tokenString, session.ExpiresAt)
+	audit.Record(audit.Entry{ActorType: audit.ActorWeb, Actor: username, IP: ip, Action: "auth.login", Params: map[string]any{"method": login.Method, "session": session.ID}})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"token": tokenString})
+}
+
+// setAuthCookie stores the JWT of a session in the AuthToken cookie
+func setAuthCookie(w http.ResponseWriter, r *http.Request, tokenString string, expires time.Time) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "AuthToken",
 		Value:    tokenString,
-		Expires:  time.Now().Add(time.Duration(config.GetAuthTokenLifetime()) * time.Minute),
+		Expires:  expires,
 		HttpOnly: true,
-		Secure:   true,
-		Path:     "/",
+		Secure:   secureCookies(r),
+		Path:     basePath() + "/",
 		SameSite: http.SameSiteStrictMode,
 	})
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"token": tokenString})
 }
 
-// AuthMiddleware protects routes with cookie-based JWT
+// AuthMiddleware protects routes with JWTs from the AuthToken cookie or a Bearer header. API keys are limited to their scopes.
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Log request details for debugging
@@ -84,8 +170,8 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		cookie, err := r.Cookie("AuthToken")
-		if err != nil {
+		tokenString := requestToken(r)
+		if tokenString == "" {
 			// Browser redirect check
 			accept := r.Header.Get("Accept")
 			if accept != "" && strings.Contains(accept, "text/html") {
@@ -99,8 +185,8 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		valid, err := security.ValidateJWT(cookie.Value)
-		if err != nil || !valid {
+		info, err := security.ValidateToken(tokenString)
+		if err != nil {
 			// Browser redirect check
 			accept := r.Header.Get("Accept")
 			if accept != "" && strings.Contains(accept, "text/html") {
@@ -111,25 +197,77 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			json.NewEncoder(w).Encode(map[string]string{"error": "Unauthorized - Invalid token"})
-			logger.Security.Warn("Unauthorized Request - Invalid token")
+			logger.Security.Warn("Unauthorized Request - Invalid token: " + err.Error())
+			return
+		}
+
+		if subject := info.Subject; strings.HasPrefix(subject, security.APIKeyPrefix) {
+			if !security.APIKeyAllows(subject, r.Method, r.URL.Path) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]string{"error": "Forbidden - API key scope does not allow this request"})
+				logger.Security.Warnf("API key %s denied %s %s (insufficient scope)", subject, r.Method, r.URL.Path)
+				return
+			}
+			security.TouchAPIKey(subject)
+		} else if info.Role != "" && !security.RoleAllows(info.Role, r.Method, r.URL.Path) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Forbidden - your role does not allow this request"})
+			logger.Security.Warnf("User %s (role %s) denied %s %s", subject, info.Role, r.Method, r.URL.Path)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tokenInfoKey, info)))
 	})
 }
 
+type contextKey string
+
+// tokenInfoKey holds the security.TokenInfo of an authenticated request, set by AuthMiddleware
+const tokenInfoKey contextKey = "tokenInfo"
+
+// requestTokenInfo returns who made an authenticated request. ok is false if auth is disabled.
+func requestTokenInfo(r *http.Request) (info security.TokenInfo, ok bool) {
+	info, ok = r.Context().Value(tokenInfoKey).(security.TokenInfo)
+	return info, ok
+}
+
+// alertLoginLockout logs a brute-force lockout and sends it to Discord
+func alertLoginLockout(lockout security.LoginLockout) {
+	what := "User " + lockout.Key
+	if lockout.IsIP {
+		what = "IP " + lockout.Key
+	}
+	message := fmt.Sprintf("🔐 %s locked out of the web UI until %s after too many failed logins", what, lockout.Until.Format("15:04:05"))
+	logger.Security.Warn(message)
+	discordbot.SendEventMessage("LOGIN_LOCKOUT", message)
+}
+
+// requestToken returns the JWT of a request, from an "Authorization: Bearer" header (preferred, for scripts) or the AuthToken cookie
+func requestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	if cookie, err := r.Cookie("AuthToken"); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
 func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	// End the server-side session so the token stops working even if it was copied
+	if info, err := security.ValidateToken(requestToken(r)); err == nil && info.SessionID != "" {
+		if err := security.RevokeSession(info.SessionID); err != nil {
+			logger.Security.Warn("Failed to end session on logout: " + err.Error())
+		}
+		audit.Record(audit.Entry{ActorType: audit.ActorWeb, Actor: info.Subject, IP: clientIP(r), Action: "auth.logout", Params: map[string]any{"session": info.SessionID}})
+	}
+
 	// Clear the cookie by setting it with an expired time
-	http.SetCookie(w, &http.Cookie{
-		Name:     "AuthToken",
-		Value:    "",
-		Expires:  time.Now().Add(-time.Hour), // Set to past time to expire immediately
-		HttpOnly: true,
-		Secure:   true,
-		Path:     "/",
-		SameSite: http.SameSiteStrictMode,
-	})
+	clearAuthCookie(w, r)
 	accept := r.Header.Get("Accept")
 	if accept != "" && strings.Contains(accept, "text/html") {
 		http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
@@ -161,7 +299,9 @@ func RegisterUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.HasPrefix(creds.Username, "apikey-") {
+	// API key IDs, OIDC subjects and the recovery account, which may reset 2FA of everyone and is only created on the host, are reserved
+	if strings.HasPrefix(creds.Username, security.APIKeyPrefix) || strings.HasPrefix(creds.Username, security.OIDCUserPrefix) ||
+		creds.Username == security.RecoveryUsername {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - Invalid Username"})
@@ -183,8 +323,16 @@ func RegisterUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Add or update the user
+	_, passwordChanged := config.GetUsers()[creds.Username]
 	config.SetUsers(map[string]string{creds.Username: hashedPassword})
 
+	// A changed password ends every session of the user immediately
+	if passwordChanged {
+		if _, err := security.RevokeUserSessions(creds.Username); err != nil {
+			logger.Security.Error("Failed to end sessions after password change: " + err.Error())
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]string{
@@ -281,13 +429,8 @@ func RegisterAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
 		durationMonths = *reqBody.DurationMonths
 	}
 
-	var creds security.UserCredentials
-
-	// Generate a random UUID as the username
-	creds.Username = "apikey-" + uuid.NewString()
-
-	// Hash a random UUID as the password
-	hashedPassword, err := security.HashPassword(uuid.NewString())
+	// Keys from the setup endpoint are unnamed admin keys, use /api/v2/auth/apikeys for named and scoped keys
+	key, apikey, err := security.CreateAPIKey("setup key", []string{security.ScopeAdmin}, durationMonths)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -295,28 +438,12 @@ func RegisterAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Initialize Users map if nil
-	if config.GetUsers() == nil {
-		config.SetUsers(make(map[string]string))
-	}
-
-	// Add or update the user
-	config.SetUsers(map[string]string{creds.Username: hashedPassword})
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	apikey, err := security.GenerateJWT(creds.Username, durationMonths)
-	expires := time.Now().AddDate(0, durationMonths, 0)
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Internal Server Error"})
-		return
-	}
 	json.NewEncoder(w).Encode(map[string]string{
 		"message": "APIKey registered successfully",
 		"apikey":  apikey,
-		"expires": expires.Format(time.RFC3339),
+		"id":      key.ID,
+		"expires": key.ExpiresAt.Format(time.RFC3339),
 	})
-	logger.Security.Infof("APIKey %s registered successfully. Expires: %s ", creds.Username, expires.Format(time.RFC3339))
 }
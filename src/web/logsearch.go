@@ -0,0 +1,81 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+const (
+	logSearchDefaultLimit = 200
+	logSearchMaxLimit     = 5000
+)
+
+// LogSearchHandler greps the persisted logs, oldest match first.
+// Filters: source (ssui or gameserver), subsystem, level, regex, since and until (RFC 3339), limit (newest matches are kept).
+func LogSearchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"status":"error","message":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+	badRequest := func(message string) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": message})
+	}
+
+	query := r.URL.Query()
+	opts := logger.SearchOptions{
+		Source:    query.Get("source"),
+		Subsystem: query.Get("subsystem"),
+		Level:     query.Get("level"),
+		Limit:     logSearchDefaultLimit,
+	}
+	for name, target := range map[string]*time.Time{"since": &opts.Since, "until": &opts.Until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				badRequest(name + " must be an RFC 3339 timestamp, e.g. 2025-01-31T18:00:00Z")
+				return
+			}
+			*target = parsed
+		}
+	}
+	if value := query.Get("regex"); value != "" {
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			badRequest("invalid regex: " + err.Error())
+			return
+		}
+		opts.Pattern = pattern
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			badRequest("limit must be a positive number")
+			return
+		}
+		opts.Limit = min(limit, logSearchMaxLimit)
+	}
+
+	matches, truncated, err := logger.Search(opts)
+	if errors.Is(err, logger.ErrInvalidSearch) {
+		badRequest(err.Error())
+		return
+	}
+	if err != nil {
+		// the logs could not be read
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": err.Error()})
+		return
+	}
+	if matches == nil {
+		matches = []logger.SearchMatch{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "matches": matches, "truncated": truncated})
+}
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+func TestLogSearchSkipsPastLongLines(t *testing.T) {
+	dir := t.TempDir() + "/"
+	config.ConfigMu.Lock()
+	oldFolder := config.LogFolder
+	config.LogFolder = dir
+	config.ConfigMu.Unlock()
+	t.Cleanup(func() {
+		config.ConfigMu.Lock()
+		config.LogFolder = oldFolder
+		config.ConfigMu.Unlock()
+	})
+	log := "2025-01-31 18:00:00 [WEB/INFO] " + strings.Repeat("x", 2*1024*1024) + "\n" +
+		"2025-01-31 18:00:01 [WEB/INFO] after the long line\n"
+	if err := os.WriteFile(dir+"ssui.log", []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		LogSearchHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v2/logs/search?"+query, nil))
+		return rec
+	}
+	rec := search("regex=after")
+	var result struct {
+		Matches []logger.SearchMatch `json:"matches"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&result); rec.Code != http.StatusOK || err != nil || len(result.Matches) != 1 || result.Matches[0].Line != 2 {
+		t.Fatalf("expected the line after the long one, got %d %+v (%v)", rec.Code, result, err)
+	}
+	if rec := search("source=nope"); rec.Code != http.StatusBadRequest {
+		t.Errorf("an unknown source is a bad request, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,169 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+)
+
+// ModConfigResponse is the load order of modconfig.xml after a change, with the problems found with it
+type ModConfigResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	modding.ModConfig
+}
+
+// ModEnableRequest enables or disables the mod in a folder of ./mods
+type ModEnableRequest struct {
+	Folder  string `json:"folder"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ModReorderRequest lists mod folders to move to the front of the load order
+type ModReorderRequest struct {
+	Order []string `json:"order"`
+}
+
+// ModRemoveRequest selects the mod folder to delete
+type ModRemoveRequest struct {
+	Folder string `json:"folder"`
+}
+
+// HandleModConfig returns the mods of modconfig.xml in load order
+func HandleModConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	modConfig, err := modding.GetModConfig()
+	writeModConfigResult(w, modConfig, "", err)
+}
+
+// HandleEnableMod enables or disables a single mod
+func HandleEnableMod(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req ModEnableRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Folder) == "" {
+		http.Error(w, "Invalid request body, a folder is required", http.StatusBadRequest)
+		return
+	}
+	modConfig, err := modding.SetModEnabled(strings.TrimSpace(req.Folder), req.Enabled)
+	state := "disabled"
+	if req.Enabled {
+		state = "enabled"
+	}
+	writeModConfigResult(w, modConfig, "Mod "+req.Folder+" "+state+", takes effect on the next server start", err)
+}
+
+// HandleReorderMods changes the load order
+func HandleReorderMods(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req ModReorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Order) == 0 {
+		http.Error(w, "Invalid request body, order must list at least one folder", http.StatusBadRequest)
+		return
+	}
+	modConfig, err := modding.ReorderMods(req.Order)
+	writeModConfigResult(w, modConfig, "Load order changed, takes effect on the next server start", err)
+}
+
+// HandleRemoveMod deletes a single mod from ./mods and modconfig.xml
+func HandleRemoveMod(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req ModRemoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Folder) == "" {
+		http.Error(w, "Invalid request body, a folder is required", http.StatusBadRequest)
+		return
+	}
+	modConfig, err := modding.RemoveMod(strings.TrimSpace(req.Folder))
+	writeModConfigResult(w, modConfig, "Mod "+req.Folder+" removed, takes effect on the next server start", err)
+}
+
+func writeModConfigResult(w http.ResponseWriter, modConfig modding.ModConfig, message string, err error) {
+	switch {
+	case errors.Is(err, modding.ErrModNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ModConfigResponse{Status: "success", Message: message, ModConfig: modConfig})
+}
+
+// ModCompatStatus lists the error counters of the installed mods on the current build
+type ModCompatStatus struct {
+	Status  string              `json:"status"`
+	BuildID string              `json:"buildId"`
+	Flagged int                 `json:"flagged"` // mods likely incompatible with the build
+	Mods    []modding.ModCompat `json:"mods"`
+}
+
+// ModCompatResetRequest selects the mod folder whose error counters are cleared, all mods if empty
+type ModCompatResetRequest struct {
+	Folder string `json:"folder"`
+}
+
+// HandleModCompat returns the exceptions attributed to each mod and which mods are flagged as incompatible
+func HandleModCompat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	records, err := modding.GetModCompat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	status := ModCompatStatus{Status: "success", BuildID: config.GetCurrentBranchBuildID(), Mods: records}
+	for _, record := range records {
+		if record.Flagged {
+			status.Flagged++
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// HandleResetModCompat clears the error counters and the incompatible flag of a mod, e.g. after checking it by hand
+func HandleResetModCompat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req ModCompatResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	folder := strings.TrimSpace(req.Folder)
+	if err := modding.ResetModCompat(folder); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, modding.ErrModNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	message := "Error counters of all mods reset"
+	if folder != "" {
+		message = "Error counters of " + folder + " reset"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusMessage{Status: "success", Message: message})
+}
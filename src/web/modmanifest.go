@@ -0,0 +1,64 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
+)
+
+// ModManifestStatus is the mod manifest with what a sync would change
+type ModManifestStatus struct {
+	Status   string              `json:"status"`
+	Manifest modding.ModManifest `json:"manifest"`
+	Plan     modding.SyncPlan    `json:"plan"`
+}
+
+// HandleModManifest returns the mod manifest on GET and replaces it on POST, a sync applies it
+func HandleModManifest(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		manifest, err := modding.LoadModManifest()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if manifest.Mods == nil {
+			manifest.Mods = []modding.ManifestMod{}
+		}
+		// plan on a copy, like a sync would, without listing the dependencies it adds in the stored manifest
+		planned := modding.ModManifest{Mods: slices.Clone(manifest.Mods)}
+		plan, _ := modding.ResolveModSync(&planned)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ModManifestStatus{Status: "success", Manifest: manifest, Plan: plan})
+	case http.MethodPost:
+		var manifest modding.ModManifest
+		if err := json.NewDecoder(r.Body).Decode(&manifest); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := manifest.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := modding.SaveModManifest(manifest); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(statusMessage{Status: "success", Message: "Mod manifest saved, sync to apply it"})
+	default:
+		http.Error(w, "Only GET and POST requests are allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleSyncMods queues a job that makes ./mods and modconfig.xml match the manifest
+func HandleSyncMods(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJobAccepted(w, steamcmd.StartModSync(), "Syncing mods with the mod manifest")
+}
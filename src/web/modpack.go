@@ -0,0 +1,69 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/discordbot"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+)
+
+// HandleModPack returns the mods players need to join, with workshop links, versions and load order
+func HandleModPack(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	pack, err := modding.BuildModPack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pack)
+}
+
+// HandleModPackZip downloads the mods of the mod pack as a zip that an upload to /api/v2/slp/upload accepts
+func HandleModPackZip(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	pack, err := modding.BuildModPack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"modpack-%s.zip\"", pack.ExportedAt.Format("2006-01-02")))
+	// the zip is streamed, an error halfway through can only end the download
+	if err := modding.WriteModPackZip(w, pack); err != nil {
+		logger.Web.Error("Failed to export the mod pack: " + err.Error())
+	}
+}
+
+// HandlePostModPackToDiscord posts the list of required mods to the Discord event log channel
+func HandlePostModPackToDiscord(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !config.GetIsDiscordEnabled() {
+		http.Error(w, "Discord is not enabled", http.StatusConflict)
+		return
+	}
+	pack, err := modding.BuildModPack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := discordbot.SendModPackEmbed(pack); err != nil {
+		http.Error(w, "Failed to post the mod pack to Discord: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusMessage{Status: "success", Message: fmt.Sprintf("Posted %d required mods to Discord", len(pack.Mods))})
+}
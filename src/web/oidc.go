@@ -0,0 +1,184 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+// suggestedOIDCRedirectURL is the callback URL of the request's host, only shown as a hint for the redirect URL setting.
+// Logins always use the configured URL, the Host header is chosen by the client.
+func suggestedOIDCRedirectURL(r *http.Request) string {
+	return requestScheme(r) + "://" + r.Host + basePath() + "/auth/oidc/callback"
+}
+
+// oidcStateCookie binds a pending OIDC login to the browser that started it, so a callback URL made for someone else's
+// login cannot log this browser into their account (login CSRF). Lax, because the callback is a navigation from the provider.
+const oidcStateCookie = "OIDCState"
+
+func setOIDCStateCookie(w http.ResponseWriter, r *http.Request, state string, maxAge time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   secureCookies(r),
+		Path:     basePath() + "/auth/oidc/",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// oidcStateMatches reports whether the callback's state is the one this browser started
+func oidcStateMatches(r *http.Request, state string) bool {
+	cookie, err := r.Cookie(oidcStateCookie)
+	return err == nil && state != "" && subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) == 1
+}
+
+// oidcLoginFailed sends the browser back to the login page, which shows the error
+func oidcLoginFailed(w http.ResponseWriter, r *http.Request, message string) {
+	http.Redirect(w, r, "/login?error="+url.QueryEscape(message), http.StatusSeeOther)
+}
+
+// OIDCLoginHandler redirects the browser to the identity provider
+func OIDCLoginHandler(w http.ResponseWriter, r *http.Request) {
+	if !config.GetAuthEnabled() || !config.GetOIDCEnabled() {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	redirectURL := config.GetOIDCRedirectURL()
+	if redirectURL == "" {
+		logger.Security.Error("OIDC login is enabled but no redirect URL is configured")
+		oidcLoginFailed(w, r, "Single sign-on is not configured completely, set the OIDC redirect URL")
+		return
+	}
+	authURL, state, err := security.BeginOIDCLogin(redirectURL)
+	if err != nil {
+		logger.Security.Error("Failed to start OIDC login: " + err.Error())
+		oidcLoginFailed(w, r, "Single sign-on is unavailable, check the backend log")
+		return
+	}
+	setOIDCStateCookie(w, r, state, security.OIDCLoginTTL)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OIDCCallbackHandler completes a login the identity provider sent back and starts a session like a password login
+func OIDCCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if providerError := query.Get("error"); providerError != "" {
+		logger.Security.Warnf("OIDC login failed at the provider: %s %s", providerError, query.Get("error_description"))
+		oidcLoginFailed(w, r, "Single sign-on failed: "+providerError)
+		return
+	}
+
+	matches := oidcStateMatches(r, query.Get("state"))
+	setOIDCStateCookie(w, r, "", -time.Second) // the state is single use either way
+	if !matches {
+		logger.Security.Warn("OIDC callback rejected, its state was not started by this browser")
+		oidcLoginFailed(w, r, "Single sign-on was not started in this browser, please try again")
+		return
+	}
+
+	identity, err := security.CompleteOIDCLogin(query.Get("state"), query.Get("code"))
+	if err != nil {
+		logger.Security.Warn("OIDC login failed: " + err.Error())
+		oidcLoginFailed(w, r, err.Error())
+		return
+	}
+
+	// The identity provider does not replace SSUI's own second factor, users with 2FA enter their code on the login page
+	if security.IsTwoFactorEnabled(identity.Username) {
+		challenge := security.NewLoginChallenge(security.PendingLogin{Username: identity.Username, Role: identity.Role, Method: "oidc"}, clientIP(r))
+		logger.Security.Infof("OIDC login of %s (subject %s) waits for the second factor", identity.Username, identity.Subject)
+		oidcContinuePage(w, "/login?challenge="+url.QueryEscape(challenge))
+		return
+	}
+
+	session, tokenString, err := security.CreateSession(identity.Username, identity.Role, clientIP(r), r.UserAgent())
+	if err != nil {
+		oidcLoginFailed(w, r, "Internal Server Error")
+		return
+	}
+	setAuthCookie(w, r, tokenString, session.ExpiresAt)
+	audit.Record(audit.Entry{ActorType: audit.ActorWeb, Actor: identity.Username, IP: clientIP(r), Action: "auth.login", Params: map[string]any{
+		"method": "oidc", "session": session.ID, "subject": identity.Subject, "role": identity.Role,
+	}})
+	role := identity.Role
+	if role == "" {
+		role = security.ScopeAdmin
+	}
+	logger.Security.Infof("OIDC login of %s (subject %s) as %s with role %s", identity.Username, identity.Subject, session.Username, role)
+
+	oidcContinuePage(w, "/")
+}
+
+// oidcContinuePage sends the browser on to path. It arrives from the provider's site, so a redirect would not send
+// the SameSite=Strict cookie yet. A same-site navigation from this page does.
+func oidcContinuePage(w http.ResponseWriter, path string) {
+	w.Header().Set("Content-Type", "text/html")
+	target := html.EscapeString(basePath() + path)
+	w.Write([]byte(`<!DOCTYPE html><html><head><meta http-equiv="refresh" content="0;url=` + target + `"></head><body><a href="` + target + `">Continue</a></body></html>`))
+}
+
+// OIDCSettingsHandler returns the OIDC login settings on GET and replaces them on POST. The client secret is never returned.
+func OIDCSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	type settings struct {
+		Enabled       bool                      `json:"enabled"`
+		IssuerURL     string                    `json:"issuerURL"`
+		ClientID      string                    `json:"clientID"`
+		ClientSecret  *string                   `json:"clientSecret,omitempty"` // omitted on POST keeps the current secret
+		RedirectURL   string                    `json:"redirectURL"`
+		GroupMappings []config.OIDCGroupMapping `json:"groupMappings"`
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"settings": settings{
+				Enabled:       config.GetOIDCEnabled(),
+				IssuerURL:     config.GetOIDCIssuerURL(),
+				ClientID:      config.GetOIDCClientID(),
+				RedirectURL:   config.GetOIDCRedirectURL(),
+				GroupMappings: config.GetOIDCGroupMappings(),
+			},
+			"hasClientSecret": config.GetOIDCClientSecret() != "",
+			"callbackURL":     suggestedOIDCRedirectURL(r),
+		})
+	case http.MethodPost:
+		var req settings
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, `{"status":"error","message":"Invalid request, expected a JSON object of OIDC settings"}`, http.StatusBadRequest)
+			return
+		}
+		secret := config.GetOIDCClientSecret()
+		if req.ClientSecret != nil {
+			secret = *req.ClientSecret
+		}
+		if req.GroupMappings != nil {
+			if err := config.SetOIDCGroupMappings(req.GroupMappings); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": err.Error()})
+				return
+			}
+		}
+		if err := config.SetOIDCSettings(req.Enabled, req.IssuerURL, req.ClientID, secret, req.RedirectURL); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": err.Error()})
+			return
+		}
+		logger.Web.Infof("OIDC login settings updated (enabled: %v, %d group mappings)", req.Enabled, len(config.GetOIDCGroupMappings()))
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "OIDC settings saved"})
+	default:
+		http.Error(w, `{"status":"error","message":"Method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
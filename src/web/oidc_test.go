@@ -0,0 +1,29 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOIDCCallbackNeedsStateCookie(t *testing.T) {
+	callback := func(cookie string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?state=attacker-state&code=attacker-code", nil)
+		if cookie != "" {
+			r.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: cookie})
+		}
+		rec := httptest.NewRecorder()
+		OIDCCallbackHandler(rec, r)
+		return rec
+	}
+	for _, cookie := range []string{"", "victim-state"} {
+		rec := callback(cookie)
+		if location := rec.Header().Get("Location"); rec.Code != http.StatusSeeOther || !strings.Contains(location, "not+started+in+this+browser") {
+			t.Errorf("cookie %q: a callback for another browser's login must be refused, got %d %s", cookie, rec.Code, location)
+		}
+		if !strings.Contains(rec.Header().Get("Set-Cookie"), oidcStateCookie+"=;") {
+			t.Errorf("cookie %q: the state cookie must be cleared, got %q", cookie, rec.Header().Get("Set-Cookie"))
+		}
+	}
+}
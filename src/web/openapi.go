@@ -0,0 +1,560 @@
+package web
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/backupmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/detectionmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gameupdatemgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
+)
+
+/*
+OpenAPI document of /api/v2
+- apiRoutes is the single list of API endpoints: SetupRoutes registers exactly these paths, APIMiddleware enforces their methods,
+  and OpenAPIHandler serves them as an OpenAPI 3.1 document at /api/v2/openapi.json
+- Request and response schemas are generated from the Go types the handlers decode and encode
+- apiVersion is bumped whenever an endpoint changes incompatibly
+*/
+
+const apiVersion = "2.2.0"
+
+type apiParam struct {
+	Name        string
+	Description string
+	Type        string // string (default), integer or boolean
+	Required    bool
+}
+
+type apiOperation struct {
+	Summary     string
+	Query       []apiParam
+	Request     any    // value of the JSON request body type, nil if there is no body
+	RequestType string // content type of a non-JSON request body, e.g. multipart/form-data
+	Response    any    // value of the JSON response type, nil for no documented body
+	ContentType string // content type of a non-JSON response, e.g. text/event-stream
+	Status      int    // success status, 200 if unset
+}
+
+type apiRoute struct {
+	Path   string
+	Tag    string
+	Prefix bool // the path is a subtree, e.g. /api/v2/custom-detections/delete/
+	Ops    map[string]apiOperation
+}
+
+// statusMessage is the {"status": "success", "message": "..."} response of many endpoints
+type statusMessage struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type authMessage struct {
+	Message string `json:"message"`
+}
+
+var limitParam = apiParam{Name: "limit", Type: "integer", Description: "maximum number of results"}
+
+var apiRoutes = []apiRoute{
+	// Server
+	{Path: "/api/v2/server/start", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Start the game server", ContentType: "text/plain"},
+	}},
+	{Path: "/api/v2/server/stop", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Stop the game server", ContentType: "text/plain"},
+	}},
+	{Path: "/api/v2/server/status", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Run state and uptime of the game server", Response: struct {
+			IsRunning bool   `json:"isRunning"`
+			State     string `json:"state"`
+			Uptime    string `json:"uptime"`
+			UUID      string `json:"uuid"`
+		}{}},
+	}},
+	{Path: "/api/v2/server/status/connectedplayers", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Connected players, one object per player keyed by username", Response: []map[string]map[string]string{}},
+	}},
+	{Path: "/api/v2/monitor/gameserver/status", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Whether the game server is running", Response: struct {
+			IsRunning bool `json:"isRunning"`
+		}{}},
+	}},
+	{Path: "/api/v2/SSCM/run", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Run a console command through SSCM", Request: CommandRequest{}, Response: CommandResponse{}},
+	}},
+	{Path: "/api/v2/SSCM/enabled", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "200 if SSCM is enabled, 403 if not"},
+	}},
+	{Path: "/api/v2/steamcmd/run", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that updates the game server files with SteamCMD, progress is streamed on /steamcmd/progress", Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/steamcmd/job", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Progress and outcome of a SteamCMD update job", Query: []apiParam{
+			{Name: "id", Description: "job ID returned by /api/v2/steamcmd/run, the latest job if omitted"},
+		}, Response: steamcmd.UpdateJob{}},
+	}},
+	{Path: "/api/v2/gameupdate", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Installed and latest game server build and the state of the automatic update", Response: gameupdatemgr.Status{}},
+	}},
+	{Path: "/api/v2/gameupdate/history", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Install history, snapshots to roll back to and available branches", Response: GameInstallHistory{}},
+	}},
+	{Path: "/api/v2/gameupdate/branch", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that snapshots the install and world save, then switches to another game branch", Request: BranchSwitchRequest{}, Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/gameupdate/rollback", Tag: "Server", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that restores an install snapshot with its branch and world save, the latest by default", Request: RollbackRequest{}, Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/jobs", Tag: "Jobs", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Background jobs, newest first, or one job with its logs", Query: []apiParam{
+			{Name: "id", Description: "job ID returned by the endpoint that queued the job"},
+		}, Response: JobList{}},
+	}},
+	{Path: "/api/v2/jobs/cancel", Tag: "Jobs", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Cancel a queued or running job", Request: CancelJobRequest{}, Response: statusMessage{}},
+	}},
+
+	// Live streams and logs
+	{Path: "/api/v2/ws", Tag: "Streams", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "WebSocket with all live streams and commands", Status: http.StatusSwitchingProtocols},
+	}},
+	{Path: "/api/v2/logs/search", Tag: "Streams", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Search the persisted SSUI and game server logs", Query: []apiParam{
+			{Name: "source", Description: "ssui (default) or gameserver"},
+			{Name: "subsystem", Description: "SSUI subsystem, e.g. WEB"},
+			{Name: "level", Description: "DEBUG, INFO, WARN or ERROR"},
+			{Name: "regex", Description: "regular expression the line must match"},
+			{Name: "since", Description: "RFC 3339 timestamp"},
+			{Name: "until", Description: "RFC 3339 timestamp"},
+			limitParam,
+		}, Response: struct {
+			Status    string               `json:"status"`
+			Matches   []logger.SearchMatch `json:"matches"`
+			Truncated bool                 `json:"truncated"`
+		}{}},
+	}},
+
+	// Backups
+	{Path: "/api/v2/backups", Tag: "Backups", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "List backups, newest first", Query: []apiParam{limitParam}, Response: []backupmgr.BackupSaveFile{}},
+	}},
+	{Path: "/api/v2/backups/restore", Tag: "Backups", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that stops the server and restores a backup", Query: []apiParam{{Name: "index", Type: "integer", Required: true}}, Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/backups/download", Tag: "Backups", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Download a backup file", Request: backupmgr.DownloadBackupRequest{}, ContentType: "application/octet-stream"},
+	}},
+	{Path: "/api/v2/backups/pin", Tag: "Backups", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Pin or unpin a backup so cleanup keeps it", Request: backupmgr.PinBackupRequest{}, Response: backupmgr.PinBackupRequest{}},
+	}},
+
+	// Configuration
+	{Path: "/api/v2/saveconfig", Tag: "Configuration", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Change configuration values, omitted fields keep their value", Request: config.JsonConfig{}, Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/advertiser/override", Tag: "Configuration", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Override the address announced to the server list", Request: advertiserOverrideRequest{}, Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/discord/routing", Tag: "Configuration", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Discord notification routing rules", Response: struct {
+			Status string                      `json:"status"`
+			Rules  []config.DiscordRoutingRule `json:"rules"`
+		}{}},
+		http.MethodPost: {Summary: "Replace the Discord notification routing rules", Request: []config.DiscordRoutingRule{}, Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/loader/reloadbackend", Tag: "Configuration", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Reload the configuration and restart all backend services", Response: struct {
+			Status string `json:"status"`
+		}{}, Status: http.StatusAccepted},
+	}},
+
+	// TLS
+	{Path: "/api/v2/tls/certificate", Tag: "TLS", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Replace the TLS certificate, form fields certificate and privateKey", RequestType: "multipart/form-data", Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/tls/acme", Tag: "TLS", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "ACME settings and the state of the served certificate", Response: struct {
+			Status       string                    `json:"status"`
+			Settings     config.ACMESettings       `json:"settings"`
+			ACME         security.ACMEStatus       `json:"acme"`
+			Certificate  *security.CertificateInfo `json:"certificate"`
+			DNSProviders []string                  `json:"dnsProviders"`
+		}{}},
+		http.MethodPost: {Summary: "Replace the ACME settings", Request: config.ACMESettings{}, Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/tls/acme/renew", Tag: "TLS", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Order a certificate now", Response: statusMessage{}, Status: http.StatusAccepted},
+	}},
+
+	// Custom detections
+	{Path: "/api/v2/custom-detections", Tag: "Detections", Ops: map[string]apiOperation{
+		http.MethodGet:  {Summary: "List custom detections", Response: []detectionmgr.CustomDetection{}},
+		http.MethodPost: {Summary: "Add a custom detection", Request: detectionmgr.CustomDetection{}, Response: detectionmgr.CustomDetection{}, Status: http.StatusCreated},
+	}},
+	{Path: "/api/v2/custom-detections/delete/", Tag: "Detections", Prefix: true, Ops: map[string]apiOperation{
+		http.MethodDelete: {Summary: "Delete a custom detection", Query: []apiParam{{Name: "id", Required: true}}, Status: http.StatusNoContent},
+	}},
+
+	// Authentication
+	{Path: "/api/v2/auth/check", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "200 if the caller is authenticated, 401 if not", Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/auth/whoami", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "The caller", Response: map[string]string{}},
+	}},
+	{Path: "/api/v2/auth/adduser", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Add a user or change a password", Request: security.UserCredentials{}, Response: authMessage{}},
+	}},
+	{Path: "/api/v2/auth/apikeys", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "List API keys", Response: struct {
+			Keys []security.APIKey `json:"keys"`
+		}{}},
+		http.MethodPost: {Summary: "Create a named, scoped API key, the key is only returned once", Request: createAPIKeyRequest{}, Response: struct {
+			Message string          `json:"message"`
+			APIKey  string          `json:"apikey"`
+			Key     security.APIKey `json:"key"`
+			Expires time.Time       `json:"expires"`
+		}{}, Status: http.StatusCreated},
+	}},
+	{Path: "/api/v2/auth/apikeys/revoke", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Revoke an API key", Request: revokeAPIKeyRequest{}, Response: authMessage{}},
+	}},
+	{Path: "/api/v2/auth/sessions", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Active sessions of the caller, or of all users", Query: []apiParam{{Name: "all", Type: "boolean"}}, Response: struct {
+			Sessions []sessionResponse `json:"sessions"`
+		}{}},
+	}},
+	{Path: "/api/v2/auth/sessions/end", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "End one session or all sessions of a user", Request: endSessionsRequest{}, Response: authMessage{}},
+	}},
+	{Path: "/api/v2/auth/2fa", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "2FA state of the caller", Response: struct {
+			Username          string `json:"username"`
+			Enabled           bool   `json:"enabled"`
+			RecoveryCodesLeft int    `json:"recoveryCodesLeft"`
+		}{}},
+	}},
+	{Path: "/api/v2/auth/2fa/enroll", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Start a 2FA enrolment", Response: struct {
+			Secret string `json:"secret"`
+			URI    string `json:"uri"`
+		}{}},
+	}},
+	{Path: "/api/v2/auth/2fa/confirm", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Enable 2FA with a code of the authenticator app", Request: twoFactorCodeRequest{}, Response: struct {
+			RecoveryCodes []string `json:"recoveryCodes"`
+		}{}},
+	}},
+	{Path: "/api/v2/auth/2fa/disable", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Disable 2FA of the caller", Request: twoFactorCodeRequest{}, Response: authMessage{}},
+	}},
+	{Path: "/api/v2/auth/2fa/reset", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Reset 2FA of a locked out user, only the recovery account may reset", Request: struct {
+			Username string `json:"username"`
+		}{}, Response: authMessage{}},
+	}},
+	{Path: "/api/v2/auth/oidc", Tag: "Authentication", Ops: map[string]apiOperation{
+		http.MethodGet:  {Summary: "OIDC single sign-on settings"},
+		http.MethodPost: {Summary: "Replace the OIDC settings", Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/auth/setup/register", Tag: "Setup", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Register the first user", Request: security.UserCredentials{}, Response: authMessage{}},
+	}},
+	{Path: "/api/v2/auth/setup/apikey", Tag: "Setup", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Create an API key valid for one month", Response: map[string]string{}},
+		http.MethodPost: {Summary: "Create an API key", Request: struct {
+			DurationMonths int `json:"durationMonths"`
+		}{}, Response: map[string]string{}},
+	}},
+	{Path: "/api/v2/auth/setup/finalize", Tag: "Setup", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Finish the setup and reload the backend", Response: map[string]string{}},
+	}},
+
+	// Audit
+	{Path: "/api/v2/audit", Tag: "Audit", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Audit log, newest first", Query: []apiParam{
+			{Name: "actorType"}, {Name: "actor"}, {Name: "action", Description: "substring"}, {Name: "result"},
+			{Name: "since", Description: "RFC 3339 timestamp"}, {Name: "until", Description: "RFC 3339 timestamp"}, limitParam,
+		}, Response: struct {
+			Status  string        `json:"status"`
+			Entries []audit.Entry `json:"entries"`
+		}{}},
+	}},
+
+	// Update
+	{Path: "/api/v2/update/check", Tag: "Update", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Whether an SSUI update is available", Response: map[string]string{}},
+	}},
+	{Path: "/api/v2/update/trigger", Tag: "Update", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Check for an SSUI update and apply it if allowed", Request: UpdateTriggerRequest{}, Response: statusMessage{}},
+	}},
+
+	// SLP & Modding
+	{Path: "/api/v2/slp/install", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that installs StationeersLaunchPad", Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/slp/uninstall", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that uninstalls StationeersLaunchPad", Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/slp/reinstall", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that reinstalls StationeersLaunchPad, the job's result is the version", Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/slp/version", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodGet:  {Summary: "The SLP release installs use and the installed and previous SLP versions", Response: SLPVersionStatus{}},
+		http.MethodPost: {Summary: "Select the SLP release installs use: latest, prerelease or a release tag", Request: SLPVersionRequest{}, Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/slp/rollback", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that swaps the installed SLP with the install it replaced, the job's result is the restored version", Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/slp/upload", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Upload a mod package zip and queue a job that extracts it", RequestType: "application/zip", Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/slp/mods", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Installed mods", Response: struct {
+			Success bool                  `json:"success"`
+			Mods    []modding.ModMetadata `json:"mods"`
+		}{}},
+	}},
+	{Path: "/api/v2/mods/manifest", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodGet:  {Summary: "Declared workshop mods in load order, with what a sync would download and remove", Response: ModManifestStatus{}},
+		http.MethodPost: {Summary: "Replace the mod manifest, a sync applies it", Request: modding.ModManifest{}, Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/mods/config", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Mods of modconfig.xml in load order, with warnings about missing folders, duplicate workshop handles and dependencies", Response: ModConfigResponse{}},
+	}},
+	{Path: "/api/v2/mods/enable", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Enable or disable a single mod by its folder in ./mods, takes effect on the next server start", Request: ModEnableRequest{}, Response: ModConfigResponse{}},
+	}},
+	{Path: "/api/v2/mods/reorder", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Move the listed mod folders to the front of the load order, takes effect on the next server start", Request: ModReorderRequest{}, Response: ModConfigResponse{}},
+	}},
+	{Path: "/api/v2/mods/remove", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Delete a single mod from ./mods, modconfig.xml and the mod manifest", Request: ModRemoveRequest{}, Response: ModConfigResponse{}},
+	}},
+	{Path: "/api/v2/mods/compat", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Exceptions attributed to each mod from stack traces, and the mods flagged as likely incompatible with the current build", Response: ModCompatStatus{}},
+	}},
+	{Path: "/api/v2/mods/compat/reset", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Clear the error counters and incompatible flag of a mod, or of all mods without a folder", Request: ModCompatResetRequest{}, Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/mods/modpack", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Mod pack for players: the enabled mods in load order with workshop links and versions", Response: modding.ModPack{}},
+	}},
+	{Path: "/api/v2/mods/modpack/zip", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodGet: {Summary: "Download the mod pack's mods with a modconfig.xml as a zip, in the format /api/v2/slp/upload accepts", ContentType: "application/zip"},
+	}},
+	{Path: "/api/v2/mods/modpack/discord", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Post the list of required mods as an embed to the Discord event log channel", Response: statusMessage{}},
+	}},
+	{Path: "/api/v2/mods/sync", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that downloads, updates and removes mods to match the manifest, resolves dependencies and writes modconfig.xml; the job's result is a steamcmd.ModSyncResult", Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/steamcmd/updatemods", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that updates all workshop mods, the job's result are the download logs", Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+	{Path: "/api/v2/steamcmd/updatemod", Tag: "Modding", Ops: map[string]apiOperation{
+		http.MethodPost: {Summary: "Queue a job that updates one workshop mod", Request: struct {
+			WorkshopHandle string `json:"workshopHandle"`
+		}{}, Response: jobs.Accepted{}, Status: http.StatusAccepted},
+	}},
+}
+
+// findAPIRoute returns the route of an API path
+func findAPIRoute(path string) (apiRoute, bool) {
+	for _, route := range apiRoutes {
+		if path == route.Path || route.Prefix && strings.HasPrefix(path, route.Path) {
+			return route, true
+		}
+	}
+	return apiRoute{}, false
+}
+
+// handleAPI registers the handler of an API path, every API path must be documented in apiRoutes
+func handleAPI(mux *http.ServeMux, path string, handler http.HandlerFunc) {
+	if _, ok := findAPIRoute(path); !ok {
+		panic("API route " + path + " is missing in apiRoutes")
+	}
+	mux.HandleFunc(path, handler)
+}
+
+// allowedMethods lists the methods of a route
+func (route apiRoute) allowedMethods() []string {
+	methods := slices.Collect(maps.Keys(route.Ops))
+	slices.Sort(methods)
+	return methods
+}
+
+// OpenAPIHandler serves the OpenAPI document, it is public so clients can be generated without credentials
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeAPIError(w, http.StatusMethodNotAllowed, "", "Method "+r.Method+" not allowed, use GET")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(openAPIDocument(basePath()))
+}
+
+// openAPIDocument builds the OpenAPI document, paths are relative to the server URL base
+func openAPIDocument(base string) map[string]any {
+	errorResponse := map[string]any{
+		"description": "Error",
+		"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/APIError"}}},
+	}
+	paths := map[string]any{}
+	for _, route := range apiRoutes {
+		item := map[string]any{}
+		for method, op := range route.Ops {
+			status := op.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			success := map[string]any{"description": http.StatusText(status)}
+			switch {
+			case op.ContentType != "":
+				success["content"] = map[string]any{op.ContentType: map[string]any{}}
+			case op.Response != nil:
+				success["content"] = map[string]any{"application/json": map[string]any{"schema": jsonSchema(reflect.TypeOf(op.Response), 0)}}
+			}
+			operation := map[string]any{
+				"summary":     op.Summary,
+				"tags":        []string{route.Tag},
+				"operationId": strings.ToLower(method) + operationName(route.Path),
+				"responses":   map[string]any{strconv.Itoa(status): success, "default": errorResponse},
+			}
+			var params []map[string]any
+			for _, param := range op.Query {
+				paramType := param.Type
+				if paramType == "" {
+					paramType = "string"
+				}
+				p := map[string]any{"name": param.Name, "in": "query", "required": param.Required, "schema": map[string]any{"type": paramType}}
+				if param.Description != "" {
+					p["description"] = param.Description
+				}
+				params = append(params, p)
+			}
+			if params != nil {
+				operation["parameters"] = params
+			}
+			switch {
+			case op.RequestType != "":
+				operation["requestBody"] = map[string]any{"required": true, "content": map[string]any{op.RequestType: map[string]any{}}}
+			case op.Request != nil:
+				operation["requestBody"] = map[string]any{"required": true, "content": map[string]any{
+					"application/json": map[string]any{"schema": jsonSchema(reflect.TypeOf(op.Request), 0)},
+				}}
+			}
+			item[strings.ToLower(method)] = operation
+		}
+		paths[route.Path] = item
+	}
+
+	server := base
+	if server == "" {
+		server = "/"
+	}
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "StationeersServerUI API",
+			"version":     apiVersion,
+			"description": "Authenticate with the AuthToken cookie of a login or with an API key as Bearer token. Errors use the APIError envelope.",
+		},
+		"servers": []map[string]any{{"url": server}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": map[string]any{"APIError": jsonSchema(reflect.TypeOf(APIError{}), 0)},
+			"securitySchemes": map[string]any{
+				"cookieAuth": map[string]any{"type": "apiKey", "in": "cookie", "name": "AuthToken"},
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []map[string]any{{"cookieAuth": []string{}}, {"bearerAuth": []string{}}},
+	}
+}
+
+// operationName turns /api/v2/auth/2fa/enroll into Auth2faEnroll
+func operationName(path string) string {
+	var name strings.Builder
+	for _, part := range strings.FieldsFunc(strings.TrimPrefix(path, "/api/v2/"), func(r rune) bool { return r == '/' || r == '-' }) {
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return name.String()
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// jsonSchema describes how encoding/json encodes a type
+func jsonSchema(t reflect.Type, depth int) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if depth > 8 {
+		return map[string]any{}
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]any{"type": "number"}
+	case t.Kind() == reflect.String:
+		return map[string]any{"type": "string"}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem(), depth+1)}
+	case t.Kind() == reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchema(t.Elem(), depth+1)}
+	case t.Kind() == reflect.Struct:
+		properties := map[string]any{}
+		addStructFields(t, properties, depth)
+		return map[string]any{"type": "object", "properties": properties}
+	}
+	return map[string]any{}
+}
+
+// addStructFields adds the JSON fields of a struct, fields of embedded structs are promoted like encoding/json does
+func addStructFields(t reflect.Type, properties map[string]any, depth int) {
+	for field := range t.Fields() {
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(embedded, properties, depth)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = jsonSchema(field.Type, depth+1)
+	}
+}
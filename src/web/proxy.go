@@ -0,0 +1,156 @@
+package web
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+/*
+Reverse proxy support
+- clientIP and requestScheme believe X-Forwarded-For and X-Forwarded-Proto only from config TrustedProxies
+- BasePathMiddleware serves SSUI under config SSUIWebBasePath (e.g. /ssui): the prefix is stripped from requests and added to redirects.
+  Response bodies are never rewritten: pages get the prefix as BasePath in their template data and set window.BASE_PATH,
+  which the frontend adds to its requests; stylesheets only use relative URLs
+- Requests without the prefix are served as well, for proxies that strip it themselves
+*/
+
+// isTrustedProxy reports whether ip is one of the configured reverse proxies
+func isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, proxy := range config.GetTrustedProxies() {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			if network.Contains(parsed) {
+				return true
+			}
+		} else if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the IP address of the client that sent the request. Behind trusted proxies, this is the
+// right-most X-Forwarded-For hop that is not a trusted proxy itself, so clients cannot spoof it by sending the header.
+func clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !isTrustedProxy(ip) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !isTrustedProxy(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// requestScheme returns "https" or "http" as seen by the browser, which differs from the connection behind a TLS terminating proxy
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil && isTrustedProxy(host) {
+		proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ",")
+		if proto = strings.ToLower(strings.TrimSpace(proto)); proto == "https" || proto == "http" {
+			return proto
+		}
+	}
+	return "http"
+}
+
+// basePath returns the configured URL prefix, "" when SSUI is served at the root
+func basePath() string {
+	return config.GetSSUIWebBasePath()
+}
+
+// BasePathMiddleware serves next under the configured base path, see the package comment above
+func BasePathMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := basePath()
+		if base == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.URL.Path == base {
+			http.Redirect(w, r, base+"/", http.StatusMovedPermanently)
+			return
+		}
+		if rest, ok := strings.CutPrefix(r.URL.Path, base+"/"); ok {
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = "/" + rest
+			r2.URL.RawPath = ""
+			r = r2
+		}
+
+		next.ServeHTTP(&basePathWriter{ResponseWriter: w, base: base}, r)
+	})
+}
+
+// basePathWriter adds the base path to root-relative redirects
+type basePathWriter struct {
+	http.ResponseWriter
+	base        string
+	wroteHeader bool
+}
+
+func (w *basePathWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	header := w.Header()
+	if location := header.Get("Location"); strings.HasPrefix(location, "/") && !strings.HasPrefix(location, "//") && !hasBasePath(location, w.base) {
+		header.Set("Location", w.base+location)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *basePathWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush keeps server-sent events streaming
+func (w *basePathWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the WebSocket API take over the connection
+func (w *basePathWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+func (w *basePathWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// hasBasePath reports whether a root-relative path already starts with the base path
+func hasBasePath(path, base string) bool {
+	return path == base || strings.HasPrefix(path, base+"/") || strings.HasPrefix(path, base+"?")
+}
+
+// secureCookies reports whether cookies get the Secure flag. Only plain HTTP mode reached without TLS, e.g. directly in a LAN, needs them without it.
+func secureCookies(r *http.Request) bool {
+	return !config.GetSSUIWebPlainHTTP() || requestScheme(r) == "https"
+}
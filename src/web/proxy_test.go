@@ -0,0 +1,96 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+)
+
+func setProxyConfig(t *testing.T, base string, trusted []string) {
+	t.Helper()
+	config.ConfigMu.Lock()
+	config.SSUIWebBasePath, config.TrustedProxies = base, trusted
+	config.ConfigMu.Unlock()
+	t.Cleanup(func() {
+		config.ConfigMu.Lock()
+		config.SSUIWebBasePath, config.TrustedProxies = "", nil
+		config.ConfigMu.Unlock()
+	})
+}
+
+func TestClientIPTrustedProxies(t *testing.T) {
+	setProxyConfig(t, "", []string{"10.0.0.0/8", "192.0.2.1"})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{name: "direct client ignores header", remoteAddr: "203.0.113.5:4000", forwarded: "198.51.100.1", want: "203.0.113.5"},
+		{name: "single proxy", remoteAddr: "10.1.2.3:4000", forwarded: "198.51.100.1", want: "198.51.100.1"},
+		{name: "spoofed hop before real client", remoteAddr: "10.1.2.3:4000", forwarded: "1.2.3.4, 198.51.100.1", want: "198.51.100.1"},
+		{name: "proxy chain", remoteAddr: "192.0.2.1:4000", forwarded: "198.51.100.1, 10.9.9.9", want: "198.51.100.1"},
+		{name: "proxy without header", remoteAddr: "10.1.2.3:4000", want: "10.1.2.3"},
+		{name: "garbage hop", remoteAddr: "10.1.2.3:4000", forwarded: "not-an-ip", want: "10.1.2.3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = test.remoteAddr
+			if test.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", test.forwarded)
+			}
+			if got := clientIP(r); got != test.want {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "203.0.113.5:4000"
+	r.Header.Set("X-Forwarded-Proto", "https")
+	if requestScheme(r) != "http" {
+		t.Fatal("X-Forwarded-Proto from an untrusted client must be ignored")
+	}
+	r.RemoteAddr = "10.1.2.3:4000"
+	if requestScheme(r) != "https" {
+		t.Fatal("X-Forwarded-Proto from a trusted proxy must be used")
+	}
+}
+
+func TestBasePathMiddleware(t *testing.T) {
+	setProxyConfig(t, "/ssui", nil)
+
+	mux := http.NewServeMux()
+	page := `<html><link href="/ssui/static/css/a.css"><script>fetch(BASE_PATH + '/api/v2/backups'); const re = /api/; url("/static/x")</script></html>`
+	mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	})
+	mux.HandleFunc("/login-required", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
+	})
+	handler := BasePathMiddleware(mux)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ssui/config", nil))
+	// pages carry the base path themselves, the middleware must not touch response bodies
+	if body, _ := io.ReadAll(rec.Body); string(body) != page {
+		t.Fatalf("response body was rewritten:\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ssui/login-required", nil))
+	if location := rec.Header().Get("Location"); location != "/ssui/login" {
+		t.Fatalf("expected redirect to /ssui/login, got %q", location)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ssui", nil))
+	if location := rec.Header().Get("Location"); location != "/ssui/" {
+		t.Fatalf("expected redirect to /ssui/, got %q", location)
+	}
+}
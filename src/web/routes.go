@@ -6,6 +6,7 @@ import (
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config/configchanger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/backupmgr"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/detectionmgr"
 )
@@ -18,9 +19,16 @@ func SetupRoutes() (*http.ServeMux, *http.ServeMux) {
 	// Unprotected auth routes
 	twoboxformAssetsFS, _ := fs.Sub(config.GetV1UIFS(), "UIMod/onboard_bundled/twoboxform")
 	mux.Handle("/twoboxform/", http.StripPrefix("/twoboxform/", http.FileServer(http.FS(twoboxformAssetsFS))))
-	mux.HandleFunc("/auth/login", LoginHandler) // Token issuer
+	mux.HandleFunc("/auth/login", LoginHandler)                // Token issuer
+	mux.HandleFunc("/auth/login/2fa", LoginTwoFactorHandler)   // second login step for users with 2FA
+	mux.HandleFunc("/auth/oidc/login", OIDCLoginHandler)       // redirect to the identity provider
+	mux.HandleFunc("/auth/oidc/callback", OIDCCallbackHandler) // identity provider redirects back here
 	mux.HandleFunc("/auth/logout", LogoutHandler)
 	mux.HandleFunc("/login", ServeTwoBoxFormTemplate)
+	mux.Handle("/.well-known/acme-challenge/", security.HTTP01ChallengeHandler(http.NotFoundHandler())) // ACME http-01, e.g. when port 80 is forwarded here
+
+	// API description, public so clients can be generated without credentials
+	mux.HandleFunc("/api/v2/openapi.json", OpenAPIHandler)
 
 	// Protected routes (wrapped with middleware)
 	protectedMux := http.NewServeMux()
@@ -36,7 +44,7 @@ func SetupRoutes() (*http.ServeMux, *http.ServeMux) {
 	protectedMux.HandleFunc("/v2", ServeSvelteUI)
 	svelteAssetsFS, _ := fs.Sub(config.V1UIFS, "UIMod/onboard_bundled/v2/assets")
 	protectedMux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.FS(svelteAssetsFS))))
-	protectedMux.HandleFunc("/api/v2/loader/reloadbackend", HandleReloadAll)
+	handleAPI(protectedMux, "/api/v2/loader/reloadbackend", HandleReloadAll)
 
 	// SSE routes
 	protectedMux.HandleFunc("/console", GetLogOutput)
@@ -46,59 +54,101 @@ func SetupRoutes() (*http.ServeMux, *http.ServeMux) {
 	protectedMux.HandleFunc("/logs/warn", GetWarnLogOutput)
 	protectedMux.HandleFunc("/logs/error", GetErrorLogOutput)
 	protectedMux.HandleFunc("/logs/backend", GetBackendLogOutput)
+	protectedMux.HandleFunc("/steamcmd/progress", GetSteamCMDProgressOutput)
+	handleAPI(protectedMux, "/api/v2/ws", WebSocketHandler)          // all of the above streams plus commands over one WebSocket
+	handleAPI(protectedMux, "/api/v2/logs/search", LogSearchHandler) // grep the persisted SSUI and game server logs
 
 	// Server Control
-	protectedMux.HandleFunc("/start", StartServer)
-	protectedMux.HandleFunc("/stop", StopServer)
-	protectedMux.HandleFunc("/api/v2/server/start", StartServer)
-	protectedMux.HandleFunc("/api/v2/server/stop", StopServer)
-	protectedMux.HandleFunc("/api/v2/server/status", GetGameServerRunState)
-	protectedMux.HandleFunc("/api/v2/server/status/connectedplayers", HandleConnectedPlayersList)
+	protectedMux.HandleFunc("/start", postOnly(StartServer))
+	protectedMux.HandleFunc("/stop", postOnly(StopServer))
+	handleAPI(protectedMux, "/api/v2/server/start", StartServer)
+	handleAPI(protectedMux, "/api/v2/server/stop", StopServer)
+	handleAPI(protectedMux, "/api/v2/server/status", GetGameServerRunState)
+	handleAPI(protectedMux, "/api/v2/server/status/connectedplayers", HandleConnectedPlayersList)
 
 	backupHandler := backupmgr.NewHTTPHandler(backupmgr.GlobalBackupManager)
-	protectedMux.HandleFunc("/api/v2/backups", backupHandler.ListBackupsHandler)
-	protectedMux.HandleFunc("/api/v2/backups/restore", backupHandler.RestoreBackupHandler)
-	protectedMux.HandleFunc("/api/v2/backups/download", backupHandler.DownloadBackupHandler)
+	handleAPI(protectedMux, "/api/v2/backups", backupHandler.ListBackupsHandler)
+	handleAPI(protectedMux, "/api/v2/backups/restore", backupHandler.RestoreBackupHandler)
+	handleAPI(protectedMux, "/api/v2/backups/download", backupHandler.DownloadBackupHandler)
+	handleAPI(protectedMux, "/api/v2/backups/pin", backupHandler.PinBackupHandler)
 
 	// Configuration
 	protectedMux.HandleFunc("/saveconfigasjson", configchanger.SaveConfigForm)     // legacy, used on config page
-	protectedMux.HandleFunc("/api/v2/saveconfig", configchanger.SaveConfigRestful) // used on twoboxform
-	protectedMux.HandleFunc("/api/v2/advertiser/override", SaveAdvertiserOverrideHandler)
-	protectedMux.HandleFunc("/api/v2/tls/certificate", SaveTLSCertificateHandler)
-	protectedMux.HandleFunc("/api/v2/SSCM/run", HandleCommand)           // Command execution via SSCM (needs to be enable, config.IsSSCMEnabled)
-	protectedMux.HandleFunc("/api/v2/SSCM/enabled", HandleIsSSCMEnabled) // Check if SSCM is enabled
-	protectedMux.HandleFunc("/api/v2/steamcmd/run", HandleRunSteamCMD)   // Run SteamCMD
+	handleAPI(protectedMux, "/api/v2/saveconfig", configchanger.SaveConfigRestful) // used on twoboxform
+	handleAPI(protectedMux, "/api/v2/advertiser/override", SaveAdvertiserOverrideHandler)
+	handleAPI(protectedMux, "/api/v2/tls/certificate", SaveTLSCertificateHandler)
+	handleAPI(protectedMux, "/api/v2/tls/acme", ACMESettingsHandler)
+	handleAPI(protectedMux, "/api/v2/tls/acme/renew", ACMERenewHandler)
+	handleAPI(protectedMux, "/api/v2/discord/routing", DiscordRoutingHandler)
+	handleAPI(protectedMux, "/api/v2/SSCM/run", HandleCommand)            // Command execution via SSCM (needs to be enable, config.IsSSCMEnabled)
+	handleAPI(protectedMux, "/api/v2/SSCM/enabled", HandleIsSSCMEnabled)  // Check if SSCM is enabled
+	handleAPI(protectedMux, "/api/v2/steamcmd/run", HandleRunSteamCMD)    // Start a SteamCMD update job
+	handleAPI(protectedMux, "/api/v2/steamcmd/job", HandleSteamCMDJob)    // Progress and outcome of an update job
+	handleAPI(protectedMux, "/api/v2/gameupdate", HandleGameUpdateStatus) // Installed vs. latest build and the scheduled auto update
+	handleAPI(protectedMux, "/api/v2/gameupdate/history", HandleGameInstallHistory)
+	handleAPI(protectedMux, "/api/v2/gameupdate/branch", HandleSwitchGameBranch)      // snapshot, then install another branch
+	handleAPI(protectedMux, "/api/v2/gameupdate/rollback", HandleRollbackGameInstall) // restore a snapshot
+	handleAPI(protectedMux, "/api/v2/jobs", HandleJobs)                               // background jobs, one job with its logs by ?id=
+	handleAPI(protectedMux, "/api/v2/jobs/cancel", HandleCancelJob)                   // cancel a queued or running job
 	// /api/v2/steamcmd/updatemods is defined in the SLP & Modding section below
 
 	// Custom Detections
-	protectedMux.HandleFunc("/api/v2/custom-detections", detectionmgr.HandleCustomDetection)
-	protectedMux.HandleFunc("/api/v2/custom-detections/delete/", detectionmgr.HandleDeleteCustomDetection)
+	handleAPI(protectedMux, "/api/v2/custom-detections", detectionmgr.HandleCustomDetection)
+	handleAPI(protectedMux, "/api/v2/custom-detections/delete/", detectionmgr.HandleDeleteCustomDetection)
 	// Authentication
 	protectedMux.HandleFunc("/changeuser", ServeTwoBoxFormTemplate)
-	protectedMux.HandleFunc("/api/v2/auth/adduser", RegisterUserHandler) // user registration and change password
-	protectedMux.HandleFunc("/api/v2/auth/whoami", WhoAmIHandler)
+	handleAPI(protectedMux, "/api/v2/auth/adduser", RegisterUserHandler) // user registration and change password
+	handleAPI(protectedMux, "/api/v2/auth/check", AuthCheckHandler)      // the Svelte UI checks its login with this
+	handleAPI(protectedMux, "/api/v2/auth/whoami", WhoAmIHandler)
+	handleAPI(protectedMux, "/api/v2/auth/apikeys", APIKeysHandler)
+	handleAPI(protectedMux, "/api/v2/auth/apikeys/revoke", RevokeAPIKeyHandler)
+	handleAPI(protectedMux, "/api/v2/auth/sessions", SessionsHandler)
+	handleAPI(protectedMux, "/api/v2/auth/sessions/end", EndSessionsHandler) // end one session or log out everywhere
+	protectedMux.HandleFunc("/twofactor", ServeTwoBoxFormTemplate)
+	handleAPI(protectedMux, "/api/v2/auth/2fa", TwoFactorStatusHandler)
+	handleAPI(protectedMux, "/api/v2/auth/2fa/enroll", TwoFactorEnrollHandler)
+	handleAPI(protectedMux, "/api/v2/auth/2fa/confirm", TwoFactorConfirmHandler)
+	handleAPI(protectedMux, "/api/v2/auth/2fa/disable", TwoFactorDisableHandler)
+	handleAPI(protectedMux, "/api/v2/auth/2fa/reset", TwoFactorResetHandler) // reset 2FA of a locked out user
+	handleAPI(protectedMux, "/api/v2/auth/oidc", OIDCSettingsHandler)
+
+	// Audit log
+	handleAPI(protectedMux, "/api/v2/audit", AuditLogHandler)
 
 	// Setup
 	protectedMux.HandleFunc("/setup", ServeTwoBoxFormTemplate)
-	protectedMux.HandleFunc("/api/v2/auth/setup/register", RegisterUserHandler) // user registration
-	protectedMux.HandleFunc("/api/v2/auth/setup/apikey", RegisterAPIKeyHandler) // API Key registration
-	protectedMux.HandleFunc("/api/v2/auth/setup/finalize", SetupFinalizeHandler)
+	handleAPI(protectedMux, "/api/v2/auth/setup/register", RegisterUserHandler) // user registration
+	handleAPI(protectedMux, "/api/v2/auth/setup/apikey", RegisterAPIKeyHandler) // API Key registration
+	handleAPI(protectedMux, "/api/v2/auth/setup/finalize", SetupFinalizeHandler)
 
 	// Update
-	protectedMux.HandleFunc("/api/v2/update/trigger", TriggerUpdateHandler)
-	protectedMux.HandleFunc("/api/v2/update/check", CheckUpdateHandler)
+	handleAPI(protectedMux, "/api/v2/update/trigger", TriggerUpdateHandler)
+	handleAPI(protectedMux, "/api/v2/update/check", CheckUpdateHandler)
 
 	// Monitoring
-	protectedMux.HandleFunc("/api/v2/monitor/gameserver/status", HandleMonitorStatus)
+	handleAPI(protectedMux, "/api/v2/monitor/gameserver/status", HandleMonitorStatus)
 
 	// SLP & Modding
-	protectedMux.HandleFunc("/api/v2/slp/install", InstallSLPHandler)
-	protectedMux.HandleFunc("/api/v2/slp/uninstall", UninstallSLPHandler)
-	protectedMux.HandleFunc("/api/v2/slp/reinstall", ReinstallSLPHandler)
-	protectedMux.HandleFunc("/api/v2/slp/upload", UploadModPackageHandler)
-	protectedMux.HandleFunc("/api/v2/slp/mods", GetInstalledModDetailsHandler)
-	protectedMux.HandleFunc("/api/v2/steamcmd/updatemods", UpdateWorkshopModsHandler)
-	protectedMux.HandleFunc("/api/v2/steamcmd/updatemod", UpdateSingleWorkshopModHandler)
+	handleAPI(protectedMux, "/api/v2/slp/install", InstallSLPHandler)
+	handleAPI(protectedMux, "/api/v2/slp/uninstall", UninstallSLPHandler)
+	handleAPI(protectedMux, "/api/v2/slp/reinstall", ReinstallSLPHandler)
+	handleAPI(protectedMux, "/api/v2/slp/version", HandleSLPVersion)
+	handleAPI(protectedMux, "/api/v2/slp/rollback", HandleSLPRollback)
+	handleAPI(protectedMux, "/api/v2/slp/upload", UploadModPackageHandler)
+	handleAPI(protectedMux, "/api/v2/slp/mods", GetInstalledModDetailsHandler)
+	handleAPI(protectedMux, "/api/v2/steamcmd/updatemods", UpdateWorkshopModsHandler)
+	handleAPI(protectedMux, "/api/v2/steamcmd/updatemod", UpdateSingleWorkshopModHandler)
+	handleAPI(protectedMux, "/api/v2/mods/manifest", HandleModManifest) // declared workshop mods, pins and load order
+	handleAPI(protectedMux, "/api/v2/mods/sync", HandleSyncMods)        // apply the manifest
+	handleAPI(protectedMux, "/api/v2/mods/config", HandleModConfig)     // modconfig.xml load order
+	handleAPI(protectedMux, "/api/v2/mods/enable", HandleEnableMod)
+	handleAPI(protectedMux, "/api/v2/mods/reorder", HandleReorderMods)
+	handleAPI(protectedMux, "/api/v2/mods/remove", HandleRemoveMod)
+	handleAPI(protectedMux, "/api/v2/mods/compat", HandleModCompat) // exceptions attributed to mods
+	handleAPI(protectedMux, "/api/v2/mods/compat/reset", HandleResetModCompat)
+	handleAPI(protectedMux, "/api/v2/mods/modpack", HandleModPack) // shareable list of required mods
+	handleAPI(protectedMux, "/api/v2/mods/modpack/zip", HandleModPackZip)
+	handleAPI(protectedMux, "/api/v2/mods/modpack/discord", HandlePostModPackToDiscord)
 
 	return mux, protectedMux
 }
@@ -0,0 +1,105 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+)
+
+type sessionResponse struct {
+	security.Session
+	Current bool `json:"current"`
+}
+
+type endSessionsRequest struct {
+	ID       string `json:"id"`       // end a single session
+	Username string `json:"username"` // end all sessions of a user, defaults to the caller
+}
+
+// SessionsHandler lists active sessions. By default only the caller's sessions are listed, ?all=true lists the sessions of all users.
+func SessionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method Not Allowed"})
+		return
+	}
+
+	info, authenticated := requestTokenInfo(r)
+	username := ""
+	if authenticated && info.SessionID != "" && r.URL.Query().Get("all") != "true" {
+		username = info.Subject
+	}
+
+	list := []sessionResponse{}
+	for _, s := range security.ListSessions(username) {
+		list = append(list, sessionResponse{Session: s, Current: authenticated && s.ID == info.SessionID})
+	}
+	json.NewEncoder(w).Encode(map[string]any{"sessions": list})
+}
+
+// EndSessionsHandler ends a single session by ID, or all sessions of a user ("log out everywhere", the caller if no username is given)
+func EndSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method Not Allowed"})
+		return
+	}
+
+	var req endSessionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - Invalid JSON"})
+		return
+	}
+	info, _ := requestTokenInfo(r)
+
+	if req.ID != "" {
+		if err := security.RevokeSession(req.ID); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Not Found - " + err.Error()})
+			return
+		}
+		if req.ID == info.SessionID {
+			clearAuthCookie(w, r)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"message": "Session ended", "ended": 1})
+		return
+	}
+
+	username := req.Username
+	if username == "" {
+		if info.SessionID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - id or username is required"})
+			return
+		}
+		username = info.Subject
+	}
+	count, err := security.RevokeUserSessions(username)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Internal Server Error"})
+		return
+	}
+	if username == info.Subject {
+		clearAuthCookie(w, r)
+	}
+	json.NewEncoder(w).Encode(map[string]any{"message": "Sessions ended", "ended": count})
+}
+
+// clearAuthCookie expires the AuthToken cookie in the browser
+func clearAuthCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "AuthToken",
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour), // Set to past time to expire immediately
+		HttpOnly: true,
+		Secure:   secureCookies(r),
+		Path:     basePath() + "/",
+		SameSite: http.SameSiteStrictMode,
+	})
+}
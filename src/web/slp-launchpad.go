@@ -1,82 +1,72 @@
 package web
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/steamcmd"
 )
 
 func InstallSLPHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	if _, err := modding.InstallSLP(); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   err.Error(),
-		})
-		return
-	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"success": true}`))
+	job := jobs.Submit("slp-install", jobs.LaneModding, "Install StationeersLaunchPad", func(ctx context.Context, job *jobs.Job) (any, error) {
+		return modding.InstallSLP()
+	})
+	writeJobAccepted(w, job, "Installing StationeersLaunchPad")
 }
 
 func UninstallSLPHandler(w http.ResponseWriter, r *http.Request) {
-
 	w.Header().Set("Content-Type", "application/json")
-	if _, err := modding.UninstallSLP(); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   err.Error(),
-		})
-		return
-	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"success": true}`))
+	job := jobs.Submit("slp-uninstall", jobs.LaneModding, "Uninstall StationeersLaunchPad", func(ctx context.Context, job *jobs.Job) (any, error) {
+		return modding.UninstallSLP()
+	})
+	writeJobAccepted(w, job, "Uninstalling StationeersLaunchPad")
 }
 
 func ReinstallSLPHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	version, err := modding.ReinstallSLP()
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   err.Error(),
-		})
-		return
-	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"version": version,
-		"message": "SLP reinstalled successfully",
+	// the job's result is the installed version
+	job := jobs.Submit("slp-reinstall", jobs.LaneModding, "Reinstall StationeersLaunchPad", func(ctx context.Context, job *jobs.Job) (any, error) {
+		return modding.ReinstallSLP()
 	})
+	writeJobAccepted(w, job, "Reinstalling StationeersLaunchPad")
 }
 
 func UploadModPackageHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	if err := modding.ProcessModPackageUpload(r.Body); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	// the upload has to be read before the request ends, extracting it is the job
+	zipBytes, err := io.ReadAll(io.LimitReader(r.Body, modding.MaxModPackageSize+1))
+	if err != nil || len(zipBytes) > modding.MaxModPackageSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   "failed to read mod package zip file, filesize might exceed 500mb",
+		})
+		return
+	}
+	if len(zipBytes) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"success": false,
-			"error":   err.Error(),
+			"error":   "mod package is empty",
 		})
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "Mod package uploaded and extracted successfully",
+	job := jobs.Submit("modpackage-import", jobs.LaneModding, "Import uploaded mod package", func(ctx context.Context, job *jobs.Job) (any, error) {
+		return nil, modding.ProcessModPackageUpload(bytes.NewReader(zipBytes))
 	})
+	writeJobAccepted(w, job, "Mod package uploaded, extracting it")
 }
 
 func GetInstalledModDetailsHandler(w http.ResponseWriter, r *http.Request) {
@@ -94,37 +84,12 @@ func GetInstalledModDetailsHandler(w http.ResponseWriter, r *http.Request) {
 func UpdateWorkshopModsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	logs, err := steamcmd.UpdateWorkshopItems()
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   err.Error(),
-			"logs":    logs,
-		})
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "Workshop mods updated successfully",
-		"logs":    logs,
-	})
+	writeJobAccepted(w, steamcmd.StartWorkshopUpdate(nil), "Updating workshop mods")
 }
 
 func UpdateSingleWorkshopModHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	if r.Method != http.MethodPost {
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   "method not allowed",
-		})
-		return
-	}
-
 	var req struct {
 		WorkshopHandle string `json:"workshopHandle"`
 	}
@@ -146,21 +111,5 @@ func UpdateSingleWorkshopModHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logs, err := steamcmd.DownloadWorkshopItems([]string{req.WorkshopHandle})
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"error":   err.Error(),
-			"logs":    logs,
-		})
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "Workshop mod updated successfully",
-		"logs":    logs,
-	})
+	writeJobAccepted(w, steamcmd.StartWorkshopUpdate([]string{req.WorkshopHandle}), "Updating workshop mod "+req.WorkshopHandle)
 }
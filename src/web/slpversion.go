@@ -0,0 +1,68 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/jobs"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/modding"
+)
+
+// SLPVersionStatus is the configured SLP release selection and the installed versions
+type SLPVersionStatus struct {
+	Status  string `json:"status"`
+	Version string `json:"version"` // "latest", "prerelease" or a pinned release tag
+	modding.SLPInstallRecord
+}
+
+// SLPVersionRequest selects the SLP release later installs use
+type SLPVersionRequest struct {
+	Version string `json:"version"`
+}
+
+// HandleSLPVersion returns (GET) or sets (POST) the SLP release selection
+func HandleSLPVersion(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		record, err := modding.GetSLPInstallRecord()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SLPVersionStatus{Status: "success", Version: config.GetSLPVersion(), SLPInstallRecord: record})
+	case http.MethodPost:
+		var req SLPVersionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := config.SetSLPVersion(req.Version); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(statusMessage{Status: "success", Message: "SLP installs now use " + config.GetSLPVersion()})
+	default:
+		http.Error(w, "Only GET and POST requests are allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleSLPRollback queues a job that swaps the installed SLP with the install it replaced
+func HandleSLPRollback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if config.GetIsGameServerRunning() {
+		http.Error(w, "Stop the server before rolling back SLP", http.StatusConflict)
+		return
+	}
+	// the job's result is the restored version
+	job := jobs.Submit("slp-rollback", jobs.LaneModding, "Roll back StationeersLaunchPad", func(ctx context.Context, job *jobs.Job) (any, error) {
+		return modding.RollbackSLP()
+	})
+	writeJobAccepted(w, job, "Rolling back StationeersLaunchPad")
+}
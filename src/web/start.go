@@ -2,13 +2,16 @@
 package web
 
 import (
+	"crypto/tls"
 	"log"
+	"net"
 	"net/http"
 	"net/http/pprof"
 	"sync"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/discordbot"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
 )
 
@@ -26,27 +29,48 @@ func StartWebServer(wg *sync.WaitGroup) {
 	mux, protectedMux := SetupRoutes()
 
 	// Apply middleware only to protected routes
-	mux.Handle("/", AuthMiddleware(protectedMux)) // Wrap protected routes under root
+	mux.Handle("/", APIMiddleware(AuthMiddleware(APIRouteMiddleware(AuditMiddleware(protectedMux))))) // Wrap protected routes under root, state-changing requests are audited, API errors share one JSON format
 
 	httpLogger := log.New(&webServerLogger{}, "", 0)
 	// Start HTTP server
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
+		addr := net.JoinHostPort(config.GetSSUIWebBindAddress(), config.GetSSUIWebPort())
+
+		if config.GetSSUIWebPlainHTTP() {
+			// Reverse proxy mode, the proxy in front terminates TLS
+			logger.Web.Warn("⚠️ Serving plain HTTP on " + addr + ", use a TLS terminating reverse proxy in front of SSUI")
+			server := &http.Server{Addr: addr, Handler: BasePathMiddleware(mux), ErrorLog: httpLogger}
+			if err := server.ListenAndServe(); err != nil {
+				logger.Web.Error("Error starting HTTP server: " + err.Error())
+			}
+			return
+		}
+
 		// Ensure TLS certs are ready
 		if err := security.EnsureTLSCerts(); err != nil {
 			logger.Web.Error("Error setting up TLS certificates: " + err.Error())
 			return
 		}
+		if err := security.LoadServingCertificate(); err != nil {
+			logger.Web.Error("Error loading TLS certificate: " + err.Error())
+			return
+		}
+		startACMEChallengeServer()
+		security.StartCertificateMonitor(func(message string) {
+			discordbot.SendEventMessage("TLS_CERTIFICATE", message)
+		})
 
-		// Create an HTTP server with a custom logger
+		// Create an HTTP server with a custom logger. The certificate is looked up per handshake, so renewed certificates apply without a restart.
 		server := &http.Server{
-			Addr:     "0.0.0.0:" + config.GetSSUIWebPort(),
-			Handler:  mux,
-			ErrorLog: httpLogger,
+			Addr:      addr,
+			Handler:   BasePathMiddleware(mux),
+			ErrorLog:  httpLogger,
+			TLSConfig: &tls.Config{GetCertificate: security.GetServingCertificate},
 		}
 
-		err := server.ListenAndServeTLS(config.GetTLSCertPath(), config.GetTLSKeyPath())
+		err := server.ListenAndServeTLS("", "")
 		if err != nil {
 			logger.Web.Error("Error starting HTTPS server: " + err.Error())
 		}
@@ -68,3 +92,42 @@ func StartWebServer(wg *sync.WaitGroup) {
 		}()
 	}
 }
+
+var (
+	acmeChallengeMu     sync.Mutex
+	acmeChallengeServer *http.Server
+)
+
+// startACMEChallengeServer (re)starts the plain HTTP listener answering ACME http-01 challenges if the settings need it,
+// every other request on it is redirected to HTTPS
+func startACMEChallengeServer() {
+	acmeChallengeMu.Lock()
+	defer acmeChallengeMu.Unlock()
+
+	settings := config.GetACMESettings()
+	addr := net.JoinHostPort(config.GetSSUIWebBindAddress(), settings.HTTPPort)
+	wanted := settings.Enabled && settings.Challenge == security.ChallengeHTTP01 && !config.GetSSUIWebPlainHTTP()
+	if acmeChallengeServer != nil && (!wanted || acmeChallengeServer.Addr != addr) {
+		acmeChallengeServer.Close()
+		acmeChallengeServer = nil
+	}
+	if !wanted || acmeChallengeServer != nil {
+		return
+	}
+
+	redirect := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		http.Redirect(w, r, "https://"+net.JoinHostPort(host, config.GetSSUIWebPort())+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+	server := &http.Server{Addr: addr, Handler: security.HTTP01ChallengeHandler(redirect), ErrorLog: log.New(&webServerLogger{}, "", 0)}
+	acmeChallengeServer = server
+	go func() {
+		logger.Web.Info("Answering ACME http-01 challenges on " + addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Web.Error("Error starting ACME challenge server, http-01 validation will fail: " + err.Error())
+		}
+	}()
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"io/fs"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
@@ -28,22 +30,25 @@ func ServeSvelteUI(w http.ResponseWriter, r *http.Request) {
 	}
 	defer htmlFile.Close()
 
-	// Stream the file content to the response
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	_, err = io.Copy(w, htmlFile)
+	htmlContent, err := io.ReadAll(htmlFile)
 	if err != nil {
-		http.Error(w, "Error writing Svelte UI: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Error reading Svelte UI: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// the frontend prefixes its requests with the base path, see proxy.go
+	page := strings.Replace(string(htmlContent), "<head>", "<head>\n    <script>window.BASE_PATH = "+strconv.Quote(basePath())+";</script>", 1)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, page)
 }
 
 func HandleReloadAll(w http.ResponseWriter, r *http.Request) {
 	logger.Web.Debug("Received reloadbackend request from API")
 	reloadMu.Lock()
 	defer reloadMu.Unlock()
-	// accept only GET requests
-	if r.Method != http.MethodGet {
-		http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+	// accept only POST requests
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
 		return
 	}
 	// Reload all loaders
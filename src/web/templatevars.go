@@ -2,6 +2,7 @@ package web
 
 // TemplateData holds data to be passed to templates
 type IndexTemplateData struct {
+	BasePath                       string // URL prefix behind a reverse proxy, see proxy.go
 	UIText_UpdateAvailable         string
 	UIText_UpdateLater             string
 	UIText_UpdateNow               string
@@ -55,6 +56,7 @@ type IndexTemplateData struct {
 
 // ConfigTemplateData holds data for the config page template
 type ConfigTemplateData struct {
+	BasePath string // URL prefix behind a reverse proxy, see proxy.go
 	// Config values
 	DiscordToken                            string
 	ControlChannelID                        string
@@ -331,4 +333,7 @@ type ConfigTemplateData struct {
 	IsStationeersLaunchPadAutoUpdatesEnabled              string
 	IsStationeersLaunchPadAutoUpdatesEnabledTrueSelected  string
 	IsStationeersLaunchPadAutoUpdatesEnabledFalseSelected string
+	SLPVersion                                            string
+	ArtifactCacheFolder                                   string
+	ArtifactMirrorURL                                     string
 }
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -8,13 +9,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/discordbot"
 	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
-	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/setup/update"
 )
 
 const maxTLSUploadSize = 4 << 20
@@ -70,33 +70,6 @@ func normalizePrivateKey(data []byte) ([]byte, error) {
 	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), nil
 }
 
-func replaceTLSFiles(certPEM, keyPEM []byte) error {
-	certPath, keyPath := config.GetTLSCertPath(), config.GetTLSKeyPath()
-	if filepath.Dir(certPath) != filepath.Dir(keyPath) {
-		return fmt.Errorf("certificate and key paths must share a directory")
-	}
-	if err := os.MkdirAll(filepath.Dir(certPath), 0755); err != nil {
-		return err
-	}
-	oldCert, certReadErr := os.ReadFile(certPath)
-	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
-		return err
-	}
-	if err := os.Chmod(certPath, 0644); err != nil {
-		return err
-	}
-	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
-		if certReadErr == nil {
-			_ = os.WriteFile(certPath, oldCert, 0644)
-		}
-		return err
-	}
-	if err := os.Chmod(keyPath, 0600); err != nil {
-		return err
-	}
-	return nil
-}
-
 func SaveTLSCertificateHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	if r.Method != http.MethodPost {
@@ -140,17 +113,77 @@ func SaveTLSCertificateHandler(w http.ResponseWriter, r *http.Request) {
 		writeTLSError(w, fmt.Errorf("certificate and private key do not form a valid matching pair: %w", err))
 		return
 	}
-	if err := replaceTLSFiles(certPEM, keyPEM); err != nil {
+	if err := security.InstallCertificate(certPEM, keyPEM); err != nil {
+		logger.Security.Error("Failed to install TLS certificate: " + err.Error())
 		http.Error(w, `{"status":"error","message":"Failed to save TLS files"}`, http.StatusInternalServerError)
 		return
 	}
-	w.WriteHeader(http.StatusAccepted)
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "restarting", "message": "TLS certificate saved. SSUI is restarting."})
+	logger.Security.Info("TLS certificate replaced and loaded without a restart")
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "TLS certificate saved and loaded."})
+}
+
+// ACMESettingsHandler returns the ACME settings with the state of the served certificate on GET, and replaces the settings on POST
+func ACMESettingsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+		certificate, _ := security.ServingCertificateInfo()
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status":       "success",
+			"settings":     config.GetACMESettings(),
+			"acme":         security.GetACMEStatus(),
+			"certificate":  certificate,
+			"dnsProviders": security.DNSProviderNames(),
+		})
+	case http.MethodPost:
+		settings := config.GetACMESettings()
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			writeTLSError(w, fmt.Errorf("invalid request, expected a JSON object of ACME settings"))
+			return
+		}
+		if settings.Enabled && settings.Challenge == security.ChallengeDNS01 {
+			if _, err := security.NewDNSProvider(settings.DNSProvider, settings.DNSProviderSettings); err != nil {
+				writeTLSError(w, err)
+				return
+			}
+		}
+		if err := config.SetACMESettings(settings); err != nil {
+			writeTLSError(w, err)
+			return
+		}
+		startACMEChallengeServer()
+		logger.Security.Infof("ACME settings updated (enabled: %v, domains: %v, challenge: %s)", settings.Enabled, settings.Domains, settings.Challenge)
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "ACME settings saved"})
+	default:
+		http.Error(w, `{"status":"error","message":"Method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+// ACMERenewHandler starts a certificate order right away, e.g. after changing the domains. Progress is reported by ACMESettingsHandler.
+func ACMERenewHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		http.Error(w, `{"status":"error","message":"Method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+	if !config.GetACMEEnabled() {
+		writeTLSError(w, fmt.Errorf("ACME certificates are disabled"))
+		return
+	}
+	if security.GetACMEStatus().Running {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "error", "message": "A certificate order is already running"})
+		return
+	}
 	go func() {
-		time.Sleep(750 * time.Millisecond)
-		logger.Security.Info("TLS certificate changed; restarting SSUI")
-		update.RestartMySelf()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		if err := security.ObtainACMECertificate(ctx); err != nil {
+			discordbot.SendEventMessage("TLS_CERTIFICATE", "🔐 Requesting a new HTTPS certificate failed: "+err.Error())
+		}
 	}()
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "accepted", "message": "Certificate order started"})
 }
 
 func writeTLSError(w http.ResponseWriter, err error) {
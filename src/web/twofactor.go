@@ -0,0 +1,149 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+)
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// twoFactorUser returns the logged in user of a request made with method. 2FA is managed per user, so API keys and disabled auth are rejected.
+func twoFactorUser(w http.ResponseWriter, r *http.Request, method string) (security.TokenInfo, bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != method {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method Not Allowed"})
+		return security.TokenInfo{}, false
+	}
+	info, ok := requestTokenInfo(r)
+	if !ok || info.SessionID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - two-factor authentication is managed per user, log in with a user account"})
+		return security.TokenInfo{}, false
+	}
+	return info, true
+}
+
+// TwoFactorStatusHandler returns whether the caller has 2FA enabled
+func TwoFactorStatusHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := twoFactorUser(w, r, http.MethodGet)
+	if !ok {
+		return
+	}
+	username := info.Subject
+	json.NewEncoder(w).Encode(map[string]any{
+		"username":          username,
+		"enabled":           security.IsTwoFactorEnabled(username),
+		"recoveryCodesLeft": security.RemainingRecoveryCodes(username),
+	})
+}
+
+// TwoFactorEnrollHandler starts a 2FA enrolment for the caller and returns the secret and otpauth:// URI for the authenticator app
+func TwoFactorEnrollHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := twoFactorUser(w, r, http.MethodPost)
+	if !ok {
+		return
+	}
+	username := info.Subject
+	secret, uri, err := security.BeginTwoFactorEnrolment(username)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - " + err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"secret": secret, "uri": uri})
+}
+
+// TwoFactorConfirmHandler enables 2FA once the caller entered a valid code and returns the recovery codes
+func TwoFactorConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := twoFactorUser(w, r, http.MethodPost)
+	if !ok {
+		return
+	}
+	username := info.Subject
+	var req twoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - Invalid JSON"})
+		return
+	}
+	codes, err := security.ConfirmTwoFactorEnrolment(username, req.Code)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - " + err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"message":       "Two-factor authentication enabled. Store the recovery codes somewhere safe, each works once.",
+		"recoveryCodes": codes,
+	})
+}
+
+// TwoFactorDisableHandler disables 2FA for the caller, a valid code or recovery code is required
+func TwoFactorDisableHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := twoFactorUser(w, r, http.MethodPost)
+	if !ok {
+		return
+	}
+	username := info.Subject
+	var req twoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - Invalid JSON"})
+		return
+	}
+	if !security.VerifyTwoFactorCode(username, req.Code) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Unauthorized - invalid code"})
+		return
+	}
+	if err := security.ResetTwoFactor(username); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Internal Server Error"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "Two-factor authentication disabled"})
+}
+
+// TwoFactorResetHandler disables 2FA for a user who lost their authenticator and their recovery codes. Only the recovery account
+// may reset, it is created with the -RecoveryPassword flag on the host and cannot be registered over the API.
+// Users who still have a code disable 2FA themselves with TwoFactorDisableHandler.
+func TwoFactorResetHandler(w http.ResponseWriter, r *http.Request) {
+	info, ok := twoFactorUser(w, r, http.MethodPost)
+	if !ok {
+		return
+	}
+	caller := info.Subject
+	var req struct {
+		Username string `json:"username"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Username == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Bad Request - username is required"})
+		return
+	}
+	if !canResetTwoFactor(info) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Forbidden - only the recovery account can reset two-factor authentication, disable your own with a code instead"})
+		logger.Security.Warnf("%s tried to reset two-factor authentication of %s", caller, req.Username)
+		return
+	}
+	if err := security.ResetTwoFactor(req.Username); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Internal Server Error"})
+		return
+	}
+	logger.Security.Warnf("Two-factor authentication of %s reset by %s", req.Username, caller)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Two-factor authentication reset for " + req.Username})
+}
+
+// canResetTwoFactor reports whether the session may reset 2FA: only a full access session of the recovery user may.
+// A reset skips the code check, so a stolen session of the account itself must not be enough.
+func canResetTwoFactor(info security.TokenInfo) bool {
+	return info.Subject == security.RecoveryUsername && (info.Role == "" || info.Role == security.ScopeAdmin)
+}
@@ -0,0 +1,46 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+)
+
+func TestTwoFactorResetNeedsRecovery(t *testing.T) {
+	reset := func(info security.TokenInfo, method, username string) int {
+		r := httptest.NewRequest(method, "/api/v2/auth/2fa/reset", strings.NewReader(`{"username":"`+username+`"}`))
+		r = r.WithContext(context.WithValue(r.Context(), tokenInfoKey, info))
+		rec := httptest.NewRecorder()
+		TwoFactorResetHandler(rec, r)
+		return rec.Code
+	}
+	alice := security.TokenInfo{Subject: "alice", SessionID: "s1"}
+	if code := reset(alice, http.MethodPost, "bob"); code != http.StatusForbidden {
+		t.Errorf("a user must not reset 2FA of another user, got %d", code)
+	}
+	if code := reset(alice, http.MethodPost, "alice"); code != http.StatusForbidden {
+		t.Errorf("a session alone must not strip 2FA of its own account, got %d", code)
+	}
+	if code := reset(alice, http.MethodGet, "alice"); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET must not reset 2FA, got %d", code)
+	}
+	if !canResetTwoFactor(security.TokenInfo{Subject: security.RecoveryUsername}) {
+		t.Error("the recovery account must be allowed to reset 2FA")
+	}
+	if canResetTwoFactor(security.TokenInfo{Subject: security.RecoveryUsername, Role: security.ScopeRead}) {
+		t.Error("a read-only recovery session must not reset 2FA")
+	}
+}
+
+func TestRegisterUserRejectsRecoveryAccount(t *testing.T) {
+	rec := httptest.NewRecorder()
+	body := `{"username":"` + security.RecoveryUsername + `","password":"hunter2"}`
+	RegisterUserHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v2/auth/adduser", strings.NewReader(body)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("the recovery account must not be registered over the API, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,362 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/config"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/audit"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/ssestream"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/localization"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/logger"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/commandmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/detectionmgr"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/managers/gamemgr"
+	"github.com/gorilla/websocket"
+)
+
+/*
+WebSocket API at /api/v2/ws
+- One connection carries any set of the SSE streams (console, events, logs/*), so browsers do not run into their limit of ~6 connections per domain
+- Client -> server: {"id": "1", "type": "subscribe"|"unsubscribe", "streams": ["console", "logs/info"], "lastEventIds": {"console": 42}}
+                    {"id": "2", "type": "command", "command": "SAY hello"}, {"id": "3", "type": "start"|"stop"|"ping"}
+- Server -> client: {"type": "welcome", "streams": [...]}, {"type": "message", "stream": "console", "eventId": 43, "data": "..."}
+                    {"type": "response", "id": "2", "status": "success"|"error", "message": "..."}
+- Subscribing replays the scrollback of a stream first, after lastEventIds like Last-Event-ID on the SSE endpoints
+- Every request is checked against the scopes of the equivalent REST endpoint, so API keys and roles mean the same here
+- The session or API key is re-checked on every request and every wsAuthCheckInterval, so idle subscribers are dropped after a logout too
+- The SSE endpoints stay for compatibility
+*/
+
+const (
+	wsWriteTimeout    = 10 * time.Second
+	wsPongTimeout     = 60 * time.Second
+	wsPingInterval    = 25 * time.Second
+	wsMaxRequestBytes = 16 * 1024
+	wsSendBuffer      = 256
+)
+
+// wsAuthCheckInterval is how often writeLoop re-checks the token of a connection, a var so tests can shorten it
+var wsAuthCheckInterval = 30 * time.Second
+
+// wsUpgrader keeps the default origin check, so other websites cannot open a socket with the user's cookie
+var wsUpgrader = websocket.Upgrader{ReadBufferSize: 4096, WriteBufferSize: 4096}
+
+// wsRequest is a message from a client. ID is echoed in the response.
+type wsRequest struct {
+	ID           string            `json:"id,omitempty"`
+	Type         string            `json:"type"`
+	Streams      []string          `json:"streams,omitempty"`
+	LastEventIDs map[string]uint64 `json:"lastEventIds,omitempty"`
+	Command      string            `json:"command,omitempty"`
+}
+
+// wsMessage is a message to a client
+type wsMessage struct {
+	Type    string   `json:"type"`
+	ID      string   `json:"id,omitempty"`
+	Stream  string   `json:"stream,omitempty"`
+	EventID uint64   `json:"eventId,omitempty"`
+	Data    string   `json:"data,omitempty"`
+	Status  string   `json:"status,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Streams []string `json:"streams,omitempty"`
+}
+
+// wsClient is one WebSocket connection. Only writeLoop writes to conn.
+type wsClient struct {
+	conn      *websocket.Conn
+	r         *http.Request
+	send      chan wsMessage
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu            sync.Mutex
+	subscriptions map[string]func() // stream name -> unsubscribe
+}
+
+// WebSocketHandler upgrades the request and serves the WebSocket API until the client disconnects
+func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already sent an error response
+		logger.Web.Debug("WebSocket upgrade failed: " + err.Error())
+		return
+	}
+	c := &wsClient{
+		conn:          conn,
+		r:             r,
+		send:          make(chan wsMessage, wsSendBuffer),
+		done:          make(chan struct{}),
+		subscriptions: make(map[string]func()),
+	}
+	go c.writeLoop()
+	c.queue(wsMessage{Type: "welcome", Streams: wsStreamNames()})
+	c.readLoop()
+	c.close()
+}
+
+// wsStreamNames returns the names of all streams, sorted
+func wsStreamNames() []string {
+	names := make([]string, 0, len(ssestream.Streams))
+	for name := range ssestream.Streams {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (c *wsClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.mu.Lock()
+		for name, unsubscribe := range c.subscriptions {
+			unsubscribe()
+			delete(c.subscriptions, name)
+		}
+		c.mu.Unlock()
+		c.conn.Close()
+	})
+}
+
+// queue hands msg to writeLoop, waiting if the client is slow
+func (c *wsClient) queue(msg wsMessage) {
+	select {
+	case c.send <- msg:
+	case <-c.done:
+	}
+}
+
+// queueLive is queue for live stream messages, which are dropped for slow clients like on the SSE endpoints
+func (c *wsClient) queueLive(msg wsMessage) {
+	select {
+	case c.send <- msg:
+	case <-c.done:
+	default:
+	}
+}
+
+func (c *wsClient) respond(id string, err error, message string) {
+	if err != nil {
+		c.queue(wsMessage{Type: "response", ID: id, Status: "error", Message: err.Error()})
+		return
+	}
+	c.queue(wsMessage{Type: "response", ID: id, Status: "success", Message: message})
+}
+
+func (c *wsClient) writeLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	authTicker := time.NewTicker(wsAuthCheckInterval)
+	defer authTicker.Stop()
+	defer c.close()
+	for {
+		select {
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case <-authTicker.C:
+			if !c.stillAuthenticated() {
+				c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session ended, log in again"), time.Now().Add(time.Second))
+				return
+			}
+		case <-c.done:
+			c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			return
+		}
+	}
+}
+
+func (c *wsClient) readLoop() {
+	c.conn.SetReadLimit(wsMaxRequestBytes)
+	c.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		c.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+
+		var req wsRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			c.respond("", errors.New("invalid JSON message"), "")
+			continue
+		}
+		if !c.stillAuthenticated() {
+			c.respond(req.ID, errors.New("session ended, log in again"), "")
+			return
+		}
+		switch req.Type {
+		case "subscribe":
+			c.respond(req.ID, c.subscribe(req.Streams, req.LastEventIDs), strings.Join(c.subscribed(), ","))
+		case "unsubscribe":
+			c.unsubscribe(req.Streams)
+			c.respond(req.ID, nil, strings.Join(c.subscribed(), ","))
+		case "ping":
+			c.respond(req.ID, nil, "pong")
+		case "command", "start", "stop":
+			// starting and stopping can take a while, keep reading meanwhile
+			go c.runCommand(req)
+		default:
+			c.respond(req.ID, errors.New("unknown message type "+req.Type), "")
+		}
+	}
+}
+
+// stillAuthenticated re-checks the token of the connection, so logging out or revoking an API key also ends open sockets
+func (c *wsClient) stillAuthenticated() bool {
+	if _, ok := requestTokenInfo(c.r); !ok {
+		return true // auth disabled
+	}
+	_, err := security.ValidateToken(requestToken(c.r))
+	return err == nil
+}
+
+// allowed reports whether the caller may perform the REST request equivalent to a WebSocket request
+func (c *wsClient) allowed(method, path string) bool {
+	info, ok := requestTokenInfo(c.r)
+	if !ok {
+		return true
+	}
+	if strings.HasPrefix(info.Subject, security.APIKeyPrefix) {
+		if !security.APIKeyAllows(info.Subject, method, path) {
+			logger.Security.Warnf("API key %s denied %s %s over WebSocket (insufficient scope)", info.Subject, method, path)
+			return false
+		}
+		security.TouchAPIKey(info.Subject)
+		return true
+	}
+	if info.Role != "" && !security.RoleAllows(info.Role, method, path) {
+		logger.Security.Warnf("User %s (role %s) denied %s %s over WebSocket", info.Subject, info.Role, method, path)
+		return false
+	}
+	return true
+}
+
+func (c *wsClient) subscribe(names []string, lastEventIDs map[string]uint64) error {
+	if len(names) == 0 {
+		return errors.New("no streams given")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// close() marks done before it drops the subscriptions under mu, anything added after that would leak
+	select {
+	case <-c.done:
+		return errors.New("connection closed")
+	default:
+	}
+	var failed []string
+	for _, name := range names {
+		manager, ok := ssestream.Streams[name]
+		if !ok || !c.allowed(http.MethodGet, "/"+name) {
+			failed = append(failed, name+" (unknown or not allowed)")
+			continue
+		}
+		if _, ok := c.subscriptions[name]; ok {
+			continue
+		}
+		replay, messages, unsubscribe, ok := manager.Subscribe(lastEventIDs[name])
+		if !ok {
+			failed = append(failed, name+" (too many clients)")
+			continue
+		}
+		c.subscriptions[name] = unsubscribe
+		go c.forward(name, replay, messages)
+	}
+	if len(failed) > 0 {
+		return errors.New("could not subscribe to " + strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// forward tags the scrollback and then the messages of one stream until it is unsubscribed, which closes messages
+func (c *wsClient) forward(name string, replay []ssestream.Event, messages <-chan ssestream.Event) {
+	for _, event := range replay {
+		c.queue(wsMessage{Type: "message", Stream: name, EventID: event.ID, Data: event.Data})
+	}
+	for event := range messages {
+		c.queueLive(wsMessage{Type: "message", Stream: name, EventID: event.ID, Data: event.Data})
+	}
+}
+
+func (c *wsClient) unsubscribe(names []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		if unsubscribe, ok := c.subscriptions[name]; ok {
+			unsubscribe()
+			delete(c.subscriptions, name)
+		}
+	}
+}
+
+func (c *wsClient) subscribed() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := make([]string, 0, len(c.subscriptions))
+	for name := range c.subscriptions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// runCommand executes a command, start or stop request like the REST handlers do, and records it in the audit log
+func (c *wsClient) runCommand(req wsRequest) {
+	path := map[string]string{"command": "/api/v2/SSCM/run", "start": "/api/v2/server/start", "stop": "/api/v2/server/stop"}[req.Type]
+	if !c.allowed(http.MethodPost, path) {
+		c.respond(req.ID, errors.New("forbidden, your role or API key scope does not allow "+req.Type), "")
+		return
+	}
+
+	entry := requestAuditEntry(c.r, "POST "+path)
+	entry.Params = map[string]any{"via": "websocket"}
+	var message string
+	var err error
+	switch req.Type {
+	case "command":
+		entry.Params["command"] = req.Command
+		switch {
+		case !config.GetIsSSCMEnabled():
+			err = errors.New("SSCM is disabled, cannot execute commands")
+		case req.Command == "":
+			err = errors.New("command cannot be empty")
+		default:
+			if err = commandmgr.WriteCommand(req.Command); err == nil {
+				message = "Command passed to server"
+			}
+		}
+	case "start":
+		if err = gamemgr.InternalStartServer(); err == nil {
+			message = localization.GetString("BackendText_ServerStarted")
+			logger.Web.Info("Server started.")
+		}
+	case "stop":
+		err = gamemgr.InternalStopServer()
+		switch {
+		case err != nil && err.Error() == "server not running":
+			err, message = nil, localization.GetString("BackendText_ServerNotRunningOrAlreadyStopped")
+		case err == nil:
+			detectionmgr.ClearPlayers(detectionmgr.GetDetector())
+			message = localization.GetString("BackendText_ServerStopped")
+			logger.Web.Info("Server stopped.")
+		}
+	}
+	audit.RecordError(entry, err)
+	c.respond(req.ID, err, message)
+}
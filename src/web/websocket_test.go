@@ -0,0 +1,83 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/security"
+	"github.com/JacksonTheMaster/StationeersServerUI/v5/src/core/ssestream"
+	"github.com/gorilla/websocket"
+)
+
+func TestWebSocketStreamsAndResponses(t *testing.T) {
+	setProxyConfig(t, "/ssui", nil) // the base path writer must let the connection be hijacked
+	server := httptest.NewServer(BasePathMiddleware(http.HandlerFunc(WebSocketHandler)))
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ssui/api/v2/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "welcome" || len(msg.Streams) != len(ssestream.Streams) {
+		t.Fatalf("expected a welcome listing all streams, got %+v (%v)", msg, err)
+	}
+
+	conn.WriteJSON(wsRequest{ID: "1", Type: "subscribe", Streams: []string{"console", "logs/info"}})
+	if err := conn.ReadJSON(&msg); err != nil || msg.ID != "1" || msg.Status != "success" || msg.Message != "console,logs/info" {
+		t.Fatalf("unexpected subscribe response %+v (%v)", msg, err)
+	}
+	ssestream.BroadcastConsoleOutput("hello from the game server")
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "message" || msg.Stream != "console" || msg.Data != "hello from the game server" {
+		t.Fatalf("expected a tagged console message, got %+v (%v)", msg, err)
+	}
+
+	conn.WriteJSON(wsRequest{ID: "2", Type: "subscribe", Streams: []string{"logs/nope"}})
+	if err := conn.ReadJSON(&msg); err != nil || msg.ID != "2" || msg.Status != "error" {
+		t.Fatalf("unknown streams must be rejected, got %+v (%v)", msg, err)
+	}
+
+	conn.WriteJSON(wsRequest{ID: "3", Type: "unsubscribe", Streams: []string{"console"}})
+	if err := conn.ReadJSON(&msg); err != nil || msg.ID != "3" || msg.Message != "logs/info" {
+		t.Fatalf("unexpected unsubscribe response %+v (%v)", msg, err)
+	}
+	ssestream.BroadcastConsoleOutput("not delivered")
+	conn.WriteJSON(wsRequest{ID: "4", Type: "ping"})
+	if err := conn.ReadJSON(&msg); err != nil || msg.ID != "4" || msg.Message != "pong" {
+		t.Fatalf("expected the pong right after unsubscribing, got %+v (%v)", msg, err)
+	}
+}
+
+func TestWebSocketDropsIdleSubscribersAfterLogout(t *testing.T) {
+	interval := wsAuthCheckInterval
+	wsAuthCheckInterval = 50 * time.Millisecond
+	t.Cleanup(func() { wsAuthCheckInterval = interval })
+
+	// the request looks authenticated, but its token no longer validates, like after a logout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), tokenInfoKey, security.TokenInfo{Subject: "alice", SessionID: "gone"}))
+		WebSocketHandler(w, r)
+	}))
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "welcome" {
+		t.Fatalf("expected a welcome, got %+v (%v)", msg, err)
+	}
+	err = conn.ReadJSON(&msg)
+	if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Fatalf("expected the socket to be closed without a client message, got %+v (%v)", msg, err)
+	}
+}
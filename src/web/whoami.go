@@ -15,3 +15,9 @@ func WhoAmIHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// AuthCheckHandler answers 200 to authenticated callers, the UI uses it to find out whether it has to show the login
+func AuthCheckHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statusMessage{Status: "success", Message: "Authenticated"})
+}